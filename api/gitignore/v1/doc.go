@@ -0,0 +1,4 @@
+// Package gitignorev1 contains the protobuf messages and gRPC service
+// definitions of version 1 of the gitignore API, generated from
+// gitignore.proto.
+package gitignorev1
@@ -0,0 +1,562 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: gitignore/v1/gitignore.proto
+
+package gitignorev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MatchRequest is the request for GitignoreService.Match.
+type MatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The lines of the .gitignore file to evaluate.
+	Lines []string `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	// The slash-separated paths to match, relative to the directory the rules
+	// apply to. Directories must have a trailing slash.
+	Paths         []string `protobuf:"bytes,2,rep,name=paths,proto3" json:"paths,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchRequest) Reset() {
+	*x = MatchRequest{}
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchRequest) ProtoMessage() {}
+
+func (x *MatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchRequest.ProtoReflect.Descriptor instead.
+func (*MatchRequest) Descriptor() ([]byte, []int) {
+	return file_gitignore_v1_gitignore_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MatchRequest) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *MatchRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+// MatchResponse is the response for GitignoreService.Match.
+type MatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The decision for each path, in request order.
+	Results       []*MatchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchResponse) Reset() {
+	*x = MatchResponse{}
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchResponse) ProtoMessage() {}
+
+func (x *MatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchResponse.ProtoReflect.Descriptor instead.
+func (*MatchResponse) Descriptor() ([]byte, []int) {
+	return file_gitignore_v1_gitignore_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MatchResponse) GetResults() []*MatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// MatchResult is the decision for a single path.
+type MatchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The path as given in the request.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Whether the path is ignored.
+	Ignored       bool `protobuf:"varint,2,opt,name=ignored,proto3" json:"ignored,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchResult) Reset() {
+	*x = MatchResult{}
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchResult) ProtoMessage() {}
+
+func (x *MatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchResult.ProtoReflect.Descriptor instead.
+func (*MatchResult) Descriptor() ([]byte, []int) {
+	return file_gitignore_v1_gitignore_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MatchResult) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MatchResult) GetIgnored() bool {
+	if x != nil {
+		return x.Ignored
+	}
+	return false
+}
+
+// ExplainRequest is the request for GitignoreService.Explain.
+type ExplainRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The lines of the .gitignore file to evaluate.
+	Lines []string `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	// The slash-separated path to explain, relative to the directory the rules
+	// apply to. Directories must have a trailing slash.
+	Path          string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainRequest) Reset() {
+	*x = ExplainRequest{}
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainRequest) ProtoMessage() {}
+
+func (x *ExplainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainRequest.ProtoReflect.Descriptor instead.
+func (*ExplainRequest) Descriptor() ([]byte, []int) {
+	return file_gitignore_v1_gitignore_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExplainRequest) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *ExplainRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+// ExplainResponse is the response for GitignoreService.Explain.
+type ExplainResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether the path is ignored.
+	Ignored bool `protobuf:"varint,1,opt,name=ignored,proto3" json:"ignored,omitempty"`
+	// Every rule matching the path, in file order.
+	Rules         []*Rule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainResponse) Reset() {
+	*x = ExplainResponse{}
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainResponse) ProtoMessage() {}
+
+func (x *ExplainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainResponse.ProtoReflect.Descriptor instead.
+func (*ExplainResponse) Descriptor() ([]byte, []int) {
+	return file_gitignore_v1_gitignore_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExplainResponse) GetIgnored() bool {
+	if x != nil {
+		return x.Ignored
+	}
+	return false
+}
+
+func (x *ExplainResponse) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// Rule is a single gitignore rule.
+type Rule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The pattern as written in the .gitignore file.
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// The 1-based line number of the rule.
+	Line int32 `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	// Whether the rule is negated with a leading "!".
+	Negate        bool `protobuf:"varint,3,opt,name=negate,proto3" json:"negate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_gitignore_v1_gitignore_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Rule) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *Rule) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Rule) GetNegate() bool {
+	if x != nil {
+		return x.Negate
+	}
+	return false
+}
+
+// WalkRequest is the request for GitignoreService.Walk.
+type WalkRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The lines of the .gitignore file to evaluate.
+	Lines []string `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	// The slash-separated directory to walk, relative to the root the server
+	// was started with. Rules apply relative to this directory.
+	Dir           string `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalkRequest) Reset() {
+	*x = WalkRequest{}
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalkRequest) ProtoMessage() {}
+
+func (x *WalkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalkRequest.ProtoReflect.Descriptor instead.
+func (*WalkRequest) Descriptor() ([]byte, []int) {
+	return file_gitignore_v1_gitignore_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WalkRequest) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *WalkRequest) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+// WalkResponse is a single path found by GitignoreService.Walk.
+type WalkResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The slash-separated path, relative to the walked directory.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Whether the path is a directory.
+	IsDir         bool `protobuf:"varint,2,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalkResponse) Reset() {
+	*x = WalkResponse{}
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalkResponse) ProtoMessage() {}
+
+func (x *WalkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gitignore_v1_gitignore_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalkResponse.ProtoReflect.Descriptor instead.
+func (*WalkResponse) Descriptor() ([]byte, []int) {
+	return file_gitignore_v1_gitignore_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WalkResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WalkResponse) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
+var File_gitignore_v1_gitignore_proto protoreflect.FileDescriptor
+
+const file_gitignore_v1_gitignore_proto_rawDesc = "" +
+	"\n" +
+	"\x1cgitignore/v1/gitignore.proto\x12\fgitignore.v1\":\n" +
+	"\fMatchRequest\x12\x14\n" +
+	"\x05lines\x18\x01 \x03(\tR\x05lines\x12\x14\n" +
+	"\x05paths\x18\x02 \x03(\tR\x05paths\"D\n" +
+	"\rMatchResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.gitignore.v1.MatchResultR\aresults\";\n" +
+	"\vMatchResult\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\aignored\x18\x02 \x01(\bR\aignored\":\n" +
+	"\x0eExplainRequest\x12\x14\n" +
+	"\x05lines\x18\x01 \x03(\tR\x05lines\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"U\n" +
+	"\x0fExplainResponse\x12\x18\n" +
+	"\aignored\x18\x01 \x01(\bR\aignored\x12(\n" +
+	"\x05rules\x18\x02 \x03(\v2\x12.gitignore.v1.RuleR\x05rules\"L\n" +
+	"\x04Rule\x12\x18\n" +
+	"\apattern\x18\x01 \x01(\tR\apattern\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06negate\x18\x03 \x01(\bR\x06negate\"5\n" +
+	"\vWalkRequest\x12\x14\n" +
+	"\x05lines\x18\x01 \x03(\tR\x05lines\x12\x10\n" +
+	"\x03dir\x18\x02 \x01(\tR\x03dir\"9\n" +
+	"\fWalkResponse\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x15\n" +
+	"\x06is_dir\x18\x02 \x01(\bR\x05isDir2\xdd\x01\n" +
+	"\x10GitignoreService\x12@\n" +
+	"\x05Match\x12\x1a.gitignore.v1.MatchRequest\x1a\x1b.gitignore.v1.MatchResponse\x12F\n" +
+	"\aExplain\x12\x1c.gitignore.v1.ExplainRequest\x1a\x1d.gitignore.v1.ExplainResponse\x12?\n" +
+	"\x04Walk\x12\x19.gitignore.v1.WalkRequest\x1a\x1a.gitignore.v1.WalkResponse0\x01BEZCgit.sr.ht/~jamesponddotco/gitignore-go/api/gitignore/v1;gitignorev1b\x06proto3"
+
+var (
+	file_gitignore_v1_gitignore_proto_rawDescOnce sync.Once
+	file_gitignore_v1_gitignore_proto_rawDescData []byte
+)
+
+func file_gitignore_v1_gitignore_proto_rawDescGZIP() []byte {
+	file_gitignore_v1_gitignore_proto_rawDescOnce.Do(func() {
+		file_gitignore_v1_gitignore_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gitignore_v1_gitignore_proto_rawDesc), len(file_gitignore_v1_gitignore_proto_rawDesc)))
+	})
+	return file_gitignore_v1_gitignore_proto_rawDescData
+}
+
+var file_gitignore_v1_gitignore_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_gitignore_v1_gitignore_proto_goTypes = []any{
+	(*MatchRequest)(nil),    // 0: gitignore.v1.MatchRequest
+	(*MatchResponse)(nil),   // 1: gitignore.v1.MatchResponse
+	(*MatchResult)(nil),     // 2: gitignore.v1.MatchResult
+	(*ExplainRequest)(nil),  // 3: gitignore.v1.ExplainRequest
+	(*ExplainResponse)(nil), // 4: gitignore.v1.ExplainResponse
+	(*Rule)(nil),            // 5: gitignore.v1.Rule
+	(*WalkRequest)(nil),     // 6: gitignore.v1.WalkRequest
+	(*WalkResponse)(nil),    // 7: gitignore.v1.WalkResponse
+}
+var file_gitignore_v1_gitignore_proto_depIdxs = []int32{
+	2, // 0: gitignore.v1.MatchResponse.results:type_name -> gitignore.v1.MatchResult
+	5, // 1: gitignore.v1.ExplainResponse.rules:type_name -> gitignore.v1.Rule
+	0, // 2: gitignore.v1.GitignoreService.Match:input_type -> gitignore.v1.MatchRequest
+	3, // 3: gitignore.v1.GitignoreService.Explain:input_type -> gitignore.v1.ExplainRequest
+	6, // 4: gitignore.v1.GitignoreService.Walk:input_type -> gitignore.v1.WalkRequest
+	1, // 5: gitignore.v1.GitignoreService.Match:output_type -> gitignore.v1.MatchResponse
+	4, // 6: gitignore.v1.GitignoreService.Explain:output_type -> gitignore.v1.ExplainResponse
+	7, // 7: gitignore.v1.GitignoreService.Walk:output_type -> gitignore.v1.WalkResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_gitignore_v1_gitignore_proto_init() }
+func file_gitignore_v1_gitignore_proto_init() {
+	if File_gitignore_v1_gitignore_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gitignore_v1_gitignore_proto_rawDesc), len(file_gitignore_v1_gitignore_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gitignore_v1_gitignore_proto_goTypes,
+		DependencyIndexes: file_gitignore_v1_gitignore_proto_depIdxs,
+		MessageInfos:      file_gitignore_v1_gitignore_proto_msgTypes,
+	}.Build()
+	File_gitignore_v1_gitignore_proto = out.File
+	file_gitignore_v1_gitignore_proto_goTypes = nil
+	file_gitignore_v1_gitignore_proto_depIdxs = nil
+}
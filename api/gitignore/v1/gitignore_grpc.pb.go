@@ -0,0 +1,213 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: gitignore/v1/gitignore.proto
+
+package gitignorev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GitignoreService_Match_FullMethodName   = "/gitignore.v1.GitignoreService/Match"
+	GitignoreService_Explain_FullMethodName = "/gitignore.v1.GitignoreService/Explain"
+	GitignoreService_Walk_FullMethodName    = "/gitignore.v1.GitignoreService/Walk"
+)
+
+// GitignoreServiceClient is the client API for GitignoreService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GitignoreService evaluates gitignore rules against paths.
+type GitignoreServiceClient interface {
+	// Match reports whether each of the given paths is ignored.
+	Match(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (*MatchResponse, error)
+	// Explain reports which rules match a path and the final decision.
+	Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error)
+	// Walk streams every path under a directory of the server that is not
+	// ignored, without descending into ignored directories.
+	Walk(ctx context.Context, in *WalkRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WalkResponse], error)
+}
+
+type gitignoreServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGitignoreServiceClient(cc grpc.ClientConnInterface) GitignoreServiceClient {
+	return &gitignoreServiceClient{cc}
+}
+
+func (c *gitignoreServiceClient) Match(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (*MatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatchResponse)
+	err := c.cc.Invoke(ctx, GitignoreService_Match_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gitignoreServiceClient) Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExplainResponse)
+	err := c.cc.Invoke(ctx, GitignoreService_Explain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gitignoreServiceClient) Walk(ctx context.Context, in *WalkRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WalkResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GitignoreService_ServiceDesc.Streams[0], GitignoreService_Walk_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WalkRequest, WalkResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GitignoreService_WalkClient = grpc.ServerStreamingClient[WalkResponse]
+
+// GitignoreServiceServer is the server API for GitignoreService service.
+// All implementations must embed UnimplementedGitignoreServiceServer
+// for forward compatibility.
+//
+// GitignoreService evaluates gitignore rules against paths.
+type GitignoreServiceServer interface {
+	// Match reports whether each of the given paths is ignored.
+	Match(context.Context, *MatchRequest) (*MatchResponse, error)
+	// Explain reports which rules match a path and the final decision.
+	Explain(context.Context, *ExplainRequest) (*ExplainResponse, error)
+	// Walk streams every path under a directory of the server that is not
+	// ignored, without descending into ignored directories.
+	Walk(*WalkRequest, grpc.ServerStreamingServer[WalkResponse]) error
+	mustEmbedUnimplementedGitignoreServiceServer()
+}
+
+// UnimplementedGitignoreServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGitignoreServiceServer struct{}
+
+func (UnimplementedGitignoreServiceServer) Match(context.Context, *MatchRequest) (*MatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Match not implemented")
+}
+func (UnimplementedGitignoreServiceServer) Explain(context.Context, *ExplainRequest) (*ExplainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Explain not implemented")
+}
+func (UnimplementedGitignoreServiceServer) Walk(*WalkRequest, grpc.ServerStreamingServer[WalkResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Walk not implemented")
+}
+func (UnimplementedGitignoreServiceServer) mustEmbedUnimplementedGitignoreServiceServer() {}
+func (UnimplementedGitignoreServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeGitignoreServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GitignoreServiceServer will
+// result in compilation errors.
+type UnsafeGitignoreServiceServer interface {
+	mustEmbedUnimplementedGitignoreServiceServer()
+}
+
+func RegisterGitignoreServiceServer(s grpc.ServiceRegistrar, srv GitignoreServiceServer) {
+	// If the following call pancis, it indicates UnimplementedGitignoreServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GitignoreService_ServiceDesc, srv)
+}
+
+func _GitignoreService_Match_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GitignoreServiceServer).Match(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GitignoreService_Match_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GitignoreServiceServer).Match(ctx, req.(*MatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GitignoreService_Explain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GitignoreServiceServer).Explain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GitignoreService_Explain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GitignoreServiceServer).Explain(ctx, req.(*ExplainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GitignoreService_Walk_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WalkRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitignoreServiceServer).Walk(m, &grpc.GenericServerStream[WalkRequest, WalkResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GitignoreService_WalkServer = grpc.ServerStreamingServer[WalkResponse]
+
+// GitignoreService_ServiceDesc is the grpc.ServiceDesc for GitignoreService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GitignoreService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gitignore.v1.GitignoreService",
+	HandlerType: (*GitignoreServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Match",
+			Handler:    _GitignoreService_Match_Handler,
+		},
+		{
+			MethodName: "Explain",
+			Handler:    _GitignoreService_Explain_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Walk",
+			Handler:       _GitignoreService_Walk_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gitignore/v1/gitignore.proto",
+}
@@ -0,0 +1,538 @@
+package gitignore
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrUnsafePath is returned when an archive entry would be extracted outside of
+// the destination directory.
+const ErrUnsafePath xerrors.Error = "unsafe path in archive"
+
+// defaultDirPerm is the permission used for parent directories that are not
+// present in an archive but must be created to extract its entries.
+const defaultDirPerm fs.FileMode = 0o755
+
+// ArchiveOption configures how WriteTar and WriteZip build an archive.
+type ArchiveOption func(o *archiveOptions)
+
+// archiveOptions holds the configuration set by the options given to an
+// archive writer.
+type archiveOptions struct {
+	tarHeader func(h *tar.Header) error
+	zipHeader func(h *zip.FileHeader) error
+	prefix    string
+	level     int
+}
+
+// WithTarHeader sets a function called with the header of every entry before
+// WriteTar writes it, so it can be rewritten, such as to clear ownership or set
+// a fixed modification time for reproducible builds, or to rename the entry.
+// Returning an error stops writing the archive, and the error is returned.
+func WithTarHeader(fn func(h *tar.Header) error) ArchiveOption {
+	return func(o *archiveOptions) {
+		o.tarHeader = fn
+	}
+}
+
+// WithZipHeader is like WithTarHeader, for the entries written by WriteZip.
+func WithZipHeader(fn func(h *zip.FileHeader) error) ArchiveOption {
+	return func(o *archiveOptions) {
+		o.zipHeader = fn
+	}
+}
+
+// WithPathPrefix makes the archive writers prepend prefix, a slash-separated
+// directory such as "project-1.0", to the name of every entry, so the archive
+// extracts into a single directory, like git archive --prefix.
+func WithPathPrefix(prefix string) ArchiveOption {
+	return func(o *archiveOptions) {
+		o.prefix = strings.Trim(filepath.ToSlash(prefix), "/")
+	}
+}
+
+// WithCompressionLevel sets the level WriteZip compresses files with, from
+// [flate.NoCompression], which stores them as is, to [flate.BestCompression].
+// It defaults to [flate.DefaultCompression].
+func WithCompressionLevel(level int) ArchiveOption {
+	return func(o *archiveOptions) {
+		o.level = level
+	}
+}
+
+// newArchiveOptions returns the configuration set by opts.
+func newArchiveOptions(opts []ArchiveOption) *archiveOptions {
+	o := &archiveOptions{
+		level: flate.DefaultCompression,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// archiveEntry is a file written to an archive.
+type archiveEntry struct {
+	info fs.FileInfo
+
+	// path is the path of the file, and name the name of its entry.
+	path string
+	name string
+
+	// link is the target of the file if it is a symbolic link.
+	link string
+}
+
+// WriteTar writes a tar archive of the files and directories below root that m
+// does not ignore to w, such as to build a source bundle or a Docker build
+// context. Ignored directories are pruned without being read, like with Walk,
+// and the .git directories are skipped.
+//
+// Entry paths are slash-separated and relative to root, with a trailing slash
+// for directories. Directories, regular files and symbolic links, which are not
+// followed, are written; other files, such as sockets, are skipped.
+func WriteTar(w io.Writer, root string, m Matcher, opts ...ArchiveOption) error {
+	var (
+		o  = newArchiveOptions(opts)
+		tw = tar.NewWriter(w)
+	)
+
+	err := walkArchive(root, m, o, func(entry *archiveEntry) error {
+		return writeTarEntry(tw, entry, o)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tw.Close(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// WriteZip is like WriteTar, but writes a zip archive, compressed with the
+// level set with WithCompressionLevel, for services expecting source uploads
+// in this format. Symbolic links are stored the way ExtractZip reads them,
+// with their target as contents.
+func WriteZip(w io.Writer, root string, m Matcher, opts ...ArchiveOption) error {
+	var (
+		o  = newArchiveOptions(opts)
+		zw = zip.NewWriter(w)
+	)
+
+	// The compressor is checked upfront, so an invalid level is reported even
+	// for empty trees.
+	if _, err := flate.NewWriter(io.Discard, o.level); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(out, o.level)
+	})
+
+	err := walkArchive(root, m, o, func(entry *archiveEntry) error {
+		return writeZipEntry(zw, entry, o)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = zw.Close(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// walkArchive calls write with every entry below root that m does not ignore,
+// skipping the .git directories and the files that are neither directories,
+// regular files nor symbolic links.
+func walkArchive(root string, m Matcher, o *archiveOptions, write func(entry *archiveEntry) error) error {
+	err := Walk(root, m, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if name == root {
+			return nil
+		}
+
+		if d.Name() == gitDir {
+			return skipEntry(d)
+		}
+
+		entry, err := newArchiveEntry(root, name, d, o)
+		if err != nil || entry == nil {
+			return err
+		}
+
+		return write(entry)
+	})
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// newArchiveEntry returns the entry for the file name, found below root, or
+// nil if it cannot be archived.
+func newArchiveEntry(root, name string, d fs.DirEntry, o *archiveOptions) (*archiveEntry, error) {
+	info, err := d.Info()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	entry := &archiveEntry{
+		info: info,
+		path: name,
+	}
+
+	switch mode := info.Mode(); {
+	case mode&fs.ModeSymlink != 0:
+		if entry.link, err = os.Readlink(name); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	case !mode.IsRegular() && !mode.IsDir():
+		return nil, nil //nolint:nilnil // Skipped without error.
+	}
+
+	rel, err := filepath.Rel(root, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	entry.name = path.Join(o.prefix, filepath.ToSlash(rel))
+
+	if info.IsDir() {
+		entry.name += "/"
+	}
+
+	return entry, nil
+}
+
+// writeTarEntry writes entry to tw.
+func writeTarEntry(tw *tar.Writer, entry *archiveEntry, o *archiveOptions) error {
+	header, err := tar.FileInfoHeader(entry.info, entry.link)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	header.Name = entry.name
+
+	if o.tarHeader != nil {
+		if err = o.tarHeader(header); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if !entry.info.Mode().IsRegular() {
+		return nil
+	}
+
+	return copyFile(tw, entry.path)
+}
+
+// writeZipEntry writes entry to zw.
+func writeZipEntry(zw *zip.Writer, entry *archiveEntry, o *archiveOptions) error {
+	header, err := zip.FileInfoHeader(entry.info)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	header.Name = entry.name
+
+	if !entry.info.IsDir() && o.level != flate.NoCompression {
+		header.Method = zip.Deflate
+	}
+
+	if o.zipHeader != nil {
+		if err = o.zipHeader(header); err != nil {
+			return err
+		}
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	switch {
+	case entry.link != "":
+		if _, err = io.WriteString(w, entry.link); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	case entry.info.Mode().IsRegular():
+		return copyFile(w, entry.path)
+	}
+
+	return nil
+}
+
+// copyFile copies the contents of the file name to w.
+func copyFile(w io.Writer, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(w, file); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// skipEntry returns the error making Walk skip the entry d.
+func skipEntry(d fs.DirEntry) error {
+	if d.IsDir() {
+		return fs.SkipDir
+	}
+
+	return nil
+}
+
+// ExtractTar extracts the tar archive read from r into the dst directory,
+// skipping every entry whose path is ignored by m.
+//
+// Entry paths are matched relative to the root of the archive. Directories,
+// regular files, and symbolic links are extracted; other entry types are
+// skipped. Entries whose path or link target would escape dst cause
+// [ErrUnsafePath] to be returned, and so do entries extracted through, or
+// over, a symbolic link already in dst, such as one extracted earlier.
+func ExtractTar(r io.Reader, dst string, m Matcher) error {
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		name, err := entryName(header.Name)
+		if err != nil {
+			return err
+		}
+
+		if name == "." || IsIgnored(m, name, header.Typeflag == tar.TypeDir) {
+			continue
+		}
+
+		mode := header.FileInfo().Mode().Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = mkdirUnder(dst, name, mode|0o700)
+		case tar.TypeReg:
+			err = extractFile(dst, name, tr, mode)
+		case tar.TypeSymlink:
+			err = extractSymlink(dst, name, header.Linkname)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ExtractZip extracts the zip archive zr into the dst directory, skipping every
+// entry whose path is ignored by m.
+//
+// Entry paths are matched relative to the root of the archive. Directories,
+// regular files, and symbolic links are extracted. Entries whose path or link
+// target would escape dst cause [ErrUnsafePath] to be returned, and so do
+// entries extracted through, or over, a symbolic link already in dst, such as
+// one extracted earlier.
+func ExtractZip(zr *zip.Reader, dst string, m Matcher) error {
+	for _, file := range zr.File {
+		name, err := entryName(file.Name)
+		if err != nil {
+			return err
+		}
+
+		info := file.FileInfo()
+
+		if name == "." || IsIgnored(m, name, info.IsDir()) {
+			continue
+		}
+
+		if err = extractZipFile(file, dst, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractZipFile extracts a single zip entry, whose clean name is name, into
+// dst.
+func extractZipFile(file *zip.File, dst, name string) error {
+	info := file.FileInfo()
+
+	if info.IsDir() {
+		return mkdirUnder(dst, name, info.Mode().Perm()|0o700)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer rc.Close()
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		var linkname []byte
+
+		linkname, err = io.ReadAll(rc)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return extractSymlink(dst, name, string(linkname))
+	}
+
+	return extractFile(dst, name, rc, info.Mode().Perm())
+}
+
+// extractFile writes the contents of r to a new file at name, a clean
+// slash-separated path within dst. The file is never written through a
+// symbolic link.
+func extractFile(dst, name string, r io.Reader, mode fs.FileMode) error {
+	if err := mkdirUnder(dst, path.Dir(name), defaultDirPerm); err != nil {
+		return err
+	}
+
+	target := filepath.Join(dst, filepath.FromSlash(name))
+
+	if err := notSymlink(target, name); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|openNoFollow, mode)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if _, err = io.Copy(file, r); err != nil {
+		file.Close()
+
+		return fmt.Errorf("%w", err)
+	}
+
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// extractSymlink creates a symbolic link at name, a clean slash-separated path
+// within dst, pointing to linkname, after making sure the link does not point
+// outside of dst.
+func extractSymlink(dst, name, linkname string) error {
+	resolved := path.Join(path.Dir(name), filepath.ToSlash(linkname))
+
+	if path.IsAbs(linkname) || filepath.IsAbs(linkname) || !fs.ValidPath(resolved) {
+		return fmt.Errorf("%w: %q links to %q", ErrUnsafePath, name, linkname)
+	}
+
+	if err := mkdirUnder(dst, path.Dir(name), defaultDirPerm); err != nil {
+		return err
+	}
+
+	if err := os.Symlink(linkname, filepath.Join(dst, filepath.FromSlash(name))); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// mkdirUnder creates the directory dir, a clean slash-separated path within
+// dst, along with its missing parents, like [os.MkdirAll]. Unlike it, it
+// returns [ErrUnsafePath] rather than going through a symbolic link, such as
+// one extracted from an earlier entry, which could lead outside of dst even if
+// its target looks safe, as links may point to other links.
+func mkdirUnder(dst, dir string, perm fs.FileMode) error {
+	if dir == "." {
+		return nil
+	}
+
+	current := dst
+
+	for _, component := range strings.Split(dir, "/") {
+		current = filepath.Join(current, component)
+
+		err := os.Mkdir(current, perm)
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%w", err)
+		}
+
+		info, err := os.Lstat(current)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %q goes through a symbolic link", ErrUnsafePath, dir)
+		}
+
+		if !info.IsDir() {
+			return fmt.Errorf("%w", &fs.PathError{Op: "mkdir", Path: current, Err: syscall.ENOTDIR})
+		}
+	}
+
+	return nil
+}
+
+// notSymlink returns [ErrUnsafePath] if target, where the entry name is
+// extracted, is an existing symbolic link.
+func notSymlink(target, name string) error {
+	info, err := os.Lstat(target)
+	if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		return fmt.Errorf("%w: %q is a symbolic link", ErrUnsafePath, name)
+	}
+
+	return nil
+}
+
+// entryName returns the clean, slash-separated form of an archive entry name,
+// or [ErrUnsafePath] if the entry would be extracted outside of the destination
+// directory.
+func entryName(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+
+	if path.IsAbs(clean) || filepath.IsAbs(name) || !fs.ValidPath(clean) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+
+	return clean, nil
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package gitignore
+
+// openNoFollow makes opening a file fail if it is a symbolic link, which is
+// only supported on Unix systems; elsewhere, links are detected beforehand.
+const openNoFollow int = 0
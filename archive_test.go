@@ -0,0 +1,515 @@
+package gitignore_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+type testEntry struct {
+	name     string
+	data     string
+	linkname string
+}
+
+func newArchiveMatcher(t *testing.T) *gitignore.File {
+	t.Helper()
+
+	matcher, err := gitignore.NewFromLines([]string{
+		"*.log",
+		"node_modules/",
+		"!keep.log",
+	})
+	if err != nil {
+		t.Fatalf("failed to create matcher: %v", err)
+	}
+
+	return matcher
+}
+
+func newTestTar(t *testing.T, entries []testEntry) *bytes.Buffer {
+	t.Helper()
+
+	var (
+		buf bytes.Buffer
+		tw  = tar.NewWriter(&buf)
+	)
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Mode:     0o644,
+			Size:     int64(len(entry.data)),
+			Typeflag: tar.TypeReg,
+		}
+
+		switch {
+		case entry.linkname != "":
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = entry.linkname
+			header.Size = 0
+		case entry.name[len(entry.name)-1] == '/':
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0o755
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("failed to write tar header: %v", err)
+		}
+
+		if _, err := tw.Write([]byte(entry.data)); err != nil {
+			t.Fatalf("failed to write tar entry: %v", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	return &buf
+}
+
+func newTestZip(t *testing.T, entries []testEntry) *zip.Reader {
+	t.Helper()
+
+	var (
+		buf bytes.Buffer
+		zw  = zip.NewWriter(&buf)
+	)
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name}
+		data := entry.data
+
+		if entry.linkname != "" {
+			header.SetMode(fs.ModeSymlink | 0o777)
+
+			data = entry.linkname
+		}
+
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+
+		if _, err = w.Write([]byte(data)); err != nil {
+			t.Fatalf("failed to write zip entry: %v", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to open zip archive: %v", err)
+	}
+
+	return zr
+}
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	entries := []testEntry{
+		{name: "app/"},
+		{name: "app/main.go", data: "package main"},
+		{name: "app/debug.log", data: "debug"},
+		{name: "app/keep.log", data: "keep"},
+		{name: "app/node_modules/"},
+		{name: "app/node_modules/left-pad/index.js", data: "module.exports = {}"},
+		{name: "./README.md", data: "# README"},
+	}
+
+	wantPresent := []string{"app/main.go", "app/keep.log", "README.md"}
+	wantMissing := []string{"app/debug.log", "app/node_modules"}
+
+	tests := []struct {
+		name    string
+		extract func(t *testing.T, dst string) error
+	}{
+		{
+			name: "Tar",
+			extract: func(t *testing.T, dst string) error {
+				t.Helper()
+
+				return gitignore.ExtractTar(newTestTar(t, entries), dst, newArchiveMatcher(t))
+			},
+		},
+		{
+			name: "Zip",
+			extract: func(t *testing.T, dst string) error {
+				t.Helper()
+
+				return gitignore.ExtractZip(newTestZip(t, entries), dst, newArchiveMatcher(t))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dst := t.TempDir()
+
+			if err := tt.extract(t, dst); err != nil {
+				t.Fatalf("extract unexpected error: %v", err)
+			}
+
+			for _, name := range wantPresent {
+				if _, err := os.Stat(filepath.Join(dst, filepath.FromSlash(name))); err != nil {
+					t.Errorf("expected %q to be extracted: %v", name, err)
+				}
+			}
+
+			for _, name := range wantMissing {
+				if _, err := os.Stat(filepath.Join(dst, filepath.FromSlash(name))); !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("expected %q to be skipped, got error %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestExtract_UnsafePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entries []testEntry
+	}{
+		{
+			name:    "Parent directory traversal",
+			entries: []testEntry{{name: "../evil.sh", data: "rm -rf /"}},
+		},
+		{
+			name:    "Absolute path",
+			entries: []testEntry{{name: "/etc/passwd", data: "root"}},
+		},
+		{
+			name:    "Symlink escaping destination",
+			entries: []testEntry{{name: "link", linkname: "../../etc"}},
+		},
+		{
+			name:    "Absolute symlink",
+			entries: []testEntry{{name: "link", linkname: "/etc"}},
+		},
+		{
+			name:    "File written through a symlink",
+			entries: []testEntry{{name: "link", linkname: "."}, {name: "link", data: "x"}},
+		},
+		{
+			name:    "Directory through a symlink",
+			entries: []testEntry{{name: "link", linkname: "."}, {name: "link/sub/a", data: "x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := gitignore.ExtractTar(newTestTar(t, tt.entries), t.TempDir(), newArchiveMatcher(t))
+			if !errors.Is(err, gitignore.ErrUnsafePath) {
+				t.Errorf("ExtractTar() error = %v, want %v", err, gitignore.ErrUnsafePath)
+			}
+		})
+	}
+}
+
+func TestExtract_SymlinkChain(t *testing.T) {
+	t.Parallel()
+
+	// Every link stays within the destination on its own, but x resolves to
+	// the parent of the destination through d/l.
+	entries := []testEntry{
+		{name: "d/l", linkname: ".."},
+		{name: "x", linkname: "d/l/.."},
+		{name: "x/evil", data: "evil"},
+	}
+
+	extract := map[string]func(dst string) error{
+		"tar": func(dst string) error {
+			return gitignore.ExtractTar(newTestTar(t, entries), dst, newArchiveMatcher(t))
+		},
+		"zip": func(dst string) error {
+			return gitignore.ExtractZip(newTestZip(t, entries), dst, newArchiveMatcher(t))
+		},
+	}
+
+	for name, fn := range extract {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dst := filepath.Join(t.TempDir(), "dst")
+
+			if err := os.Mkdir(dst, 0o700); err != nil {
+				t.Fatalf("Mkdir() error = %v", err)
+			}
+
+			if err := fn(dst); !errors.Is(err, gitignore.ErrUnsafePath) {
+				t.Errorf("extract error = %v, want %v", err, gitignore.ErrUnsafePath)
+			}
+
+			if _, err := os.Lstat(filepath.Join(filepath.Dir(dst), "evil")); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("file written outside of the destination, Lstat() error = %v", err)
+			}
+		})
+	}
+}
+
+func newArchiveTree(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":           "",
+		"main.go":               "package main\n",
+		"debug.log":             "debug",
+		"keep.log":              "keep",
+		"node_modules/pkg/a.js": "",
+		"pkg/lib.go":            "package pkg\n",
+	})
+
+	if err := os.Symlink("main.go", filepath.Join(root, "link.go")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	return root
+}
+
+func TestWriteTar(t *testing.T) {
+	t.Parallel()
+
+	root := newArchiveTree(t)
+
+	var buf bytes.Buffer
+
+	err := gitignore.WriteTar(&buf, root, newArchiveMatcher(t), gitignore.WithTarHeader(func(h *tar.Header) error {
+		h.Uid, h.Gid = 0, 0
+		h.Uname, h.Gname = "", ""
+		h.ModTime = time.Unix(0, 0)
+
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("WriteTar() error = %v", err)
+	}
+
+	var (
+		tr   = tar.NewReader(&buf)
+		got  []string
+		data = make(map[string]string)
+	)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+
+		if header.Uid != 0 || !header.ModTime.Equal(time.Unix(0, 0)) {
+			t.Errorf("header of %q was not rewritten", header.Name)
+		}
+
+		got = append(got, header.Name)
+
+		switch header.Typeflag {
+		case tar.TypeSymlink:
+			data[header.Name] = "-> " + header.Linkname
+		case tar.TypeReg:
+			contents, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+
+			data[header.Name] = string(contents)
+		}
+	}
+
+	want := []string{"keep.log", "link.go", "main.go", "pkg/", "pkg/lib.go"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("entries = %q, want %q", got, want)
+	}
+
+	wantData := map[string]string{
+		"keep.log":   "keep",
+		"link.go":    "-> main.go",
+		"main.go":    "package main\n",
+		"pkg/lib.go": "package pkg\n",
+	}
+
+	if !maps.Equal(data, wantData) {
+		t.Errorf("contents = %q, want %q", data, wantData)
+	}
+}
+
+func TestWriteTar_Error(t *testing.T) {
+	t.Parallel()
+
+	root := newArchiveTree(t)
+
+	errHeader := errors.New("header error")
+
+	err := gitignore.WriteTar(io.Discard, root, nil, gitignore.WithTarHeader(func(*tar.Header) error {
+		return errHeader
+	}))
+	if !errors.Is(err, errHeader) {
+		t.Errorf("WriteTar() error = %v, want %v", err, errHeader)
+	}
+
+	err = gitignore.WriteTar(io.Discard, filepath.Join(root, "missing"), nil)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("WriteTar() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestWriteZip(t *testing.T) {
+	t.Parallel()
+
+	root := newArchiveTree(t)
+
+	tests := []struct {
+		name       string
+		giveOpts   []gitignore.ArchiveOption
+		wantDir    string
+		wantNames  []string
+		wantMethod uint16
+	}{
+		{
+			name:       "Default",
+			wantNames:  []string{"keep.log", "link.go", "main.go", "pkg/", "pkg/lib.go"},
+			wantMethod: zip.Deflate,
+		},
+		{
+			name: "Prefix and no compression",
+			giveOpts: []gitignore.ArchiveOption{
+				gitignore.WithPathPrefix("project-1.0/"),
+				gitignore.WithCompressionLevel(flate.NoCompression),
+			},
+			wantDir: "project-1.0",
+			wantNames: []string{
+				"project-1.0/keep.log",
+				"project-1.0/link.go",
+				"project-1.0/main.go",
+				"project-1.0/pkg/",
+				"project-1.0/pkg/lib.go",
+			},
+			wantMethod: zip.Store,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			if err := gitignore.WriteZip(&buf, root, newArchiveMatcher(t), tt.giveOpts...); err != nil {
+				t.Fatalf("WriteZip() error = %v", err)
+			}
+
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+
+			names := make([]string, 0, len(zr.File))
+
+			for _, file := range zr.File {
+				names = append(names, file.Name)
+
+				if file.Mode().IsRegular() && file.Method != tt.wantMethod {
+					t.Errorf("method of %q = %d, want %d", file.Name, file.Method, tt.wantMethod)
+				}
+			}
+
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("entries = %q, want %q", names, tt.wantNames)
+			}
+
+			// The archive extracts back into the files that are not ignored.
+			dst := t.TempDir()
+
+			if err = gitignore.ExtractZip(zr, dst, gitignore.MatcherFunc(func(string) bool { return false })); err != nil {
+				t.Fatalf("ExtractZip() error = %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dst, tt.wantDir, "link.go"))
+			if err != nil || string(data) != "package main\n" {
+				t.Errorf("link.go = %q, %v, want %q", data, err, "package main\n")
+			}
+		})
+	}
+}
+
+func TestWriteZip_Error(t *testing.T) {
+	t.Parallel()
+
+	root := newArchiveTree(t)
+
+	if err := gitignore.WriteZip(io.Discard, root, nil, gitignore.WithCompressionLevel(42)); err == nil {
+		t.Error("WriteZip() error = nil, want an error for an invalid level")
+	}
+
+	errHeader := errors.New("header error")
+
+	err := gitignore.WriteZip(io.Discard, root, nil, gitignore.WithZipHeader(func(*zip.FileHeader) error {
+		return errHeader
+	}))
+	if !errors.Is(err, errHeader) {
+		t.Errorf("WriteZip() error = %v, want %v", err, errHeader)
+	}
+}
+
+func TestWriteTar_PathPrefix(t *testing.T) {
+	t.Parallel()
+
+	root := newArchiveTree(t)
+
+	var buf bytes.Buffer
+
+	if err := gitignore.WriteTar(&buf, root, newArchiveMatcher(t), gitignore.WithPathPrefix("src")); err != nil {
+		t.Fatalf("WriteTar() error = %v", err)
+	}
+
+	none, err := gitignore.NewFromLines(nil)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	dst := t.TempDir()
+
+	if err = gitignore.ExtractTar(&buf, dst, none); err != nil {
+		t.Fatalf("ExtractTar() error = %v", err)
+	}
+
+	files, err := none.ListIncluded(dst)
+	if err != nil {
+		t.Fatalf("ListIncluded() error = %v", err)
+	}
+
+	want := []string{"src/keep.log", "src/link.go", "src/main.go", "src/pkg/lib.go"}
+
+	if !slices.Equal(files, want) {
+		t.Errorf("extracted = %q, want %q", files, want)
+	}
+}
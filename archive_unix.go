@@ -0,0 +1,8 @@
+//go:build unix
+
+package gitignore
+
+import "syscall"
+
+// openNoFollow makes opening a file fail if it is a symbolic link.
+const openNoFollow int = syscall.O_NOFOLLOW
@@ -0,0 +1,54 @@
+package gitignore
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrBare is returned when walking the working tree of a bare repository,
+// which has none.
+const ErrBare xerrors.Error = "bare repository has no working tree"
+
+// Bare reports whether r is a bare repository, without a working tree. The
+// paths given to Match are then virtual, such as the ones of a tree being
+// pushed, and only the rules set with WithPatterns, info/exclude and the
+// excludes file apply, since there are no .gitignore files to read, unless they
+// are read from a revision with WithBlobReader.
+func (r *Repository) Bare() bool {
+	return r.bare
+}
+
+// setBare makes r a bare repository, whose working tree holds no file.
+func (r *Repository) setBare() {
+	r.bare = true
+	r.root = ""
+	r.fsys = emptyFS{}
+}
+
+// isGitDir reports whether dir looks like a git directory, holding a HEAD file
+// and the objects and refs directories, the way git recognizes one.
+func isGitDir(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	for _, name := range []string{"objects", "refs"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// emptyFS is a file system holding no file, standing for the missing working
+// tree of a bare repository.
+type emptyFS struct{}
+
+// Open implements fs.FS.
+func (emptyFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
@@ -0,0 +1,120 @@
+package gitignore_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestOpenRepository_Bare(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	root := t.TempDir()
+
+	writeTree(t, home, map[string]string{
+		".gitconfig": "[core]\n\texcludesFile = global-ignore\n",
+	})
+
+	writeTree(t, root, map[string]string{
+		"HEAD":             "ref: refs/heads/main\n",
+		"config":           "[core]\n\tbare = true\n",
+		"objects/.keep":    "",
+		"refs/heads/.keep": "",
+		"info/exclude":     "*.swp\n",
+		"global-ignore":    "*.bak\n",
+		// A bare repository has no working tree, so this is not one of its
+		// ignore files.
+		".gitignore": "*.log\n",
+	})
+
+	tests := []struct {
+		name    string
+		giveDir string
+		giveEnv map[string]string
+	}{
+		{
+			name:    "Git directory",
+			giveDir: root,
+		},
+		{
+			name:    "GIT_DIR",
+			giveDir: t.TempDir(),
+			giveEnv: map[string]string{"GIT_DIR": root},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo, err := gitignore.OpenRepository(tt.giveDir,
+				gitignore.WithHomeDir(home),
+				gitignore.WithoutDefaultExcludes(),
+				gitignore.WithPatterns("/build/"),
+				gitignore.WithEnv(func(key string) string {
+					return tt.giveEnv[key]
+				}),
+			)
+			if err != nil {
+				t.Fatalf("OpenRepository() error = %v", err)
+			}
+
+			if !repo.Bare() {
+				t.Error("Bare() = false, want true")
+			}
+
+			if got := repo.Root(); got != "" {
+				t.Errorf("Root() = %q, want %q", got, "")
+			}
+
+			want := map[string]bool{
+				"a.swp":       true,
+				"docs/a.bak":  true,
+				"build/main":  true,
+				"a.log":       false,
+				"src/main.go": false,
+				".git/config": true,
+			}
+
+			for path, want := range want {
+				if got := repo.Match(path); got != want {
+					t.Errorf("Match(%q) = %v, want %v", path, got, want)
+				}
+			}
+
+			if err = repo.Err(); err != nil {
+				t.Errorf("Err() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestOpenRepository_NotBare(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config": "[core]\n\tbare = false\n",
+		".gitignore":  "*.log\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	if repo.Bare() {
+		t.Error("Bare() = true, want false")
+	}
+
+	if got, want := repo.Root(), filepath.Clean(root); got != want {
+		t.Errorf("Root() = %q, want %q", got, want)
+	}
+
+	if !repo.Match("a.log") {
+		t.Errorf("Match(%q) = false, want true", "a.log")
+	}
+}
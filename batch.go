@@ -0,0 +1,64 @@
+package gitignore
+
+import (
+	"runtime"
+	"sync"
+)
+
+// minPathsPerWorker is the smallest number of paths MatchPaths hands to each
+// goroutine, below which spreading the work costs more than it saves.
+const minPathsPerWorker = 2048
+
+// MatchPaths reports whether each of the given paths is ignored, in order,
+// like calling Match for every path. Large batches are split across up to
+// GOMAXPROCS goroutines, so a normalizer set with WithPathNormalizer must be
+// safe for concurrent use.
+func (f *File) MatchPaths(paths []string) []bool {
+	results := make([]bool, len(paths))
+
+	workers := min(runtime.GOMAXPROCS(0), len(paths)/minPathsPerWorker)
+	if workers <= 1 {
+		for i, p := range paths {
+			results[i] = f.Match(p)
+		}
+
+		return results
+	}
+
+	var (
+		wg   sync.WaitGroup
+		size = (len(paths) + workers - 1) / workers
+	)
+
+	for start := 0; start < len(paths); start += size {
+		end := min(start+size, len(paths))
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := start; i < end; i++ {
+				results[i] = f.Match(paths[i])
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// MatchPathsMap is like MatchPaths, but returns the results keyed by path.
+func (f *File) MatchPathsMap(paths []string) map[string]bool {
+	var (
+		results = f.MatchPaths(paths)
+		m       = make(map[string]bool, len(paths))
+	)
+
+	for i, p := range paths {
+		m[p] = results[i]
+	}
+
+	return m
+}
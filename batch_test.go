@@ -0,0 +1,66 @@
+package gitignore_test
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFile_MatchPaths(t *testing.T) {
+	t.Parallel()
+
+	file := newTestMatcher(t, "*.log", "!keep.log", "/build/", "tmp/")
+
+	tests := []struct {
+		name     string
+		giveSize int
+	}{
+		{name: "Empty", giveSize: 0},
+		{name: "Small", giveSize: 10},
+		{name: "Large", giveSize: 50_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paths := make([]string, 0, tt.giveSize)
+
+			for i := range tt.giveSize {
+				switch i % 5 {
+				case 0:
+					paths = append(paths, fmt.Sprintf("src/%d/debug.log", i))
+				case 1:
+					paths = append(paths, fmt.Sprintf("src/%d/keep.log", i))
+				case 2:
+					paths = append(paths, fmt.Sprintf("build/%d", i))
+				case 3:
+					paths = append(paths, fmt.Sprintf("a/tmp/%d", i))
+				default:
+					paths = append(paths, fmt.Sprintf("src/%d/main.go", i))
+				}
+			}
+
+			got := file.MatchPaths(paths)
+			if len(got) != len(paths) {
+				t.Fatalf("MatchPaths() returned %d results, want %d", len(got), len(paths))
+			}
+
+			m := file.MatchPathsMap(paths)
+			if len(m) != len(paths) {
+				t.Fatalf("MatchPathsMap() returned %d results, want %d", len(m), len(paths))
+			}
+
+			for i, path := range paths {
+				want := file.Match(path)
+
+				if got[i] != want {
+					t.Fatalf("MatchPaths()[%d] (%q) = %v, want %v", i, path, got[i], want)
+				}
+
+				if m[path] != want {
+					t.Fatalf("MatchPathsMap()[%q] = %v, want %v", path, m[path], want)
+				}
+			}
+		})
+	}
+}
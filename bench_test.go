@@ -0,0 +1,235 @@
+package gitignore_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+// benchRuleSet is a set of rules benchmarks match paths against.
+type benchRuleSet struct {
+	name  string
+	lines []string
+}
+
+// benchRuleSets returns the rule sets of the benchmarks: the small and medium
+// ones are real .gitignore files, while the huge one, of several thousand
+// rules, combines every fixture with the kind of per-package rules monorepos
+// accumulate.
+func benchRuleSets(b *testing.B) []benchRuleSet {
+	b.Helper()
+
+	var (
+		small  = readLines(b, filepath.Join(corpusDir, "prometheus-react-app.gitignore"))
+		medium = readLines(b, filepath.Join(corpusDir, "kubernetes.gitignore"))
+		huge   = make([]string, 0)
+	)
+
+	for _, pattern := range []string{"testdata/corpus/*.gitignore", "templates/files/*.gitignore"} {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			b.Fatalf("failed to list fixtures: %v", err)
+		}
+
+		for _, file := range files {
+			huge = append(huge, readLines(b, file)...)
+		}
+	}
+
+	for i := range 1000 {
+		huge = append(huge,
+			fmt.Sprintf("/services/svc%04d/dist/", i),
+			fmt.Sprintf("/services/svc%04d/*.generated.go", i),
+			fmt.Sprintf("services/svc%04d/**/testdata/output-*", i),
+			fmt.Sprintf("!/services/svc%04d/dist/keep.txt", i),
+		)
+	}
+
+	return []benchRuleSet{
+		{name: "small", lines: small},
+		{name: "medium", lines: medium},
+		{name: "huge", lines: huge},
+	}
+}
+
+// benchPaths returns realistic paths to match, recorded in the golden corpus,
+// along with paths found in most trees.
+func benchPaths(b *testing.B) []string {
+	b.Helper()
+
+	paths := []string{
+		"README.md",
+		"go.mod",
+		"cmd/server/main.go",
+		"internal/storage/tsdb/head.go",
+		"src/components/Button/index.tsx",
+		"node_modules/react/cjs/react.development.js",
+		"node_modules/",
+		"build/static/js/main.js",
+		"dist/app.js",
+		"coverage/lcov.info",
+		"__pycache__/module.cpython-312.pyc",
+		"target/debug/app",
+		"logs/server.log",
+		"vendor/github.com/pkg/errors/errors.go",
+		"services/svc0042/dist/bundle.js",
+		"services/svc0042/api.generated.go",
+		"services/svc0042/cmd/main.go",
+		"services/svc0999/pkg/a/testdata/output-1.json",
+	}
+
+	golden, err := filepath.Glob(filepath.Join(corpusDir, "*.golden"))
+	if err != nil {
+		b.Fatalf("failed to list golden files: %v", err)
+	}
+
+	for _, file := range golden {
+		_, entries := readGolden(b, file)
+
+		for _, entry := range entries {
+			paths = append(paths, entry.path)
+		}
+	}
+
+	return paths
+}
+
+// readLines returns the lines of the file name.
+func readLines(b *testing.B, name string) []string {
+	b.Helper()
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		b.Fatalf("failed to read fixture: %v", err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, set := range benchRuleSets(b) {
+		b.Run(set.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(strings.Join(set.lines, "\n"))))
+
+			for range b.N {
+				if _, err := gitignore.NewFromLines(set.lines); err != nil {
+					b.Fatalf("NewFromLines() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMatch(b *testing.B) {
+	var (
+		sets  = benchRuleSets(b)
+		paths = benchPaths(b)
+	)
+
+	engines := []struct {
+		name string
+		opts []gitignore.Option
+	}{
+		{name: "regex"},
+		{name: "wildmatch", opts: []gitignore.Option{gitignore.WithWildmatch()}},
+	}
+
+	for _, set := range sets {
+		for _, engine := range engines {
+			f, err := gitignore.NewFromLines(set.lines, engine.opts...)
+			if err != nil {
+				b.Fatalf("NewFromLines() error = %v", err)
+			}
+
+			// Hits are the paths the rules ignore, and misses the ones
+			// they do not, which must be checked against every rule.
+			var hits, misses []string
+
+			for _, path := range paths {
+				if f.Match(path) {
+					hits = append(hits, path)
+				} else {
+					misses = append(misses, path)
+				}
+			}
+
+			for _, kind := range []struct {
+				name  string
+				paths []string
+			}{
+				{name: "hit", paths: hits},
+				{name: "miss", paths: misses},
+			} {
+				b.Run(set.name+"/"+engine.name+"/"+kind.name, func(b *testing.B) {
+					if len(kind.paths) == 0 {
+						b.Skip("no paths")
+					}
+
+					b.ReportAllocs()
+
+					for i := range b.N {
+						f.Match(kind.paths[i%len(kind.paths)])
+					}
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkWalk(b *testing.B) {
+	root := b.TempDir()
+	files := make(map[string]string)
+
+	// A web application with its dependencies and build outputs, most of
+	// which are ignored.
+	for i := range 20 {
+		files[fmt.Sprintf("src/components/c%02d/index.tsx", i)] = ""
+		files[fmt.Sprintf("src/components/c%02d/style.css", i)] = ""
+		files[fmt.Sprintf("dist/chunk-%02d.js", i)] = ""
+		files[fmt.Sprintf("logs/run-%02d.log", i)] = ""
+
+		for j := range 25 {
+			files[fmt.Sprintf("node_modules/pkg%02d/lib/file%02d.js", i, j)] = ""
+		}
+	}
+
+	files["README.md"] = ""
+	files["package.json"] = ""
+	files[".env"] = ""
+
+	writeTree(b, root, files)
+
+	f, err := gitignore.NewFromLines(readLines(b, filepath.Join("templates", "files", "Node.gitignore")))
+	if err != nil {
+		b.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	matchers := []struct {
+		name    string
+		matcher gitignore.Matcher
+	}{
+		{name: "none"},
+		{name: "file", matcher: f},
+	}
+
+	for _, m := range matchers {
+		b.Run(m.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				err := gitignore.Walk(root, m.matcher, func(_ string, _ fs.DirEntry, err error) error {
+					return err
+				})
+				if err != nil {
+					b.Fatalf("Walk() error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// Package billyfs provides a [billy.Filesystem] that hides paths ignored by
+// gitignore rules, for use with go-git and other billy-based tools.
+package billyfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"github.com/go-git/go-billy/v5"
+)
+
+var (
+	_ billy.Filesystem = (*Filesystem)(nil)
+	_ billy.Capable    = (*Filesystem)(nil)
+)
+
+// Filesystem is a [billy.Filesystem] that hides every path ignored by a
+// [gitignore.Matcher].
+//
+// Ignored files and directories behave as if they did not exist: opening,
+// stating, reading, renaming, or removing them returns [os.ErrNotExist], and
+// they are omitted from directory listings. Creating new files is allowed even
+// if their path is ignored, mirroring how git lets ignored files exist in the
+// working tree.
+type Filesystem struct {
+	billy.Filesystem
+
+	matcher gitignore.Matcher
+
+	// prefix is the path of the filesystem relative to the directory the
+	// matcher rules apply to, set when the filesystem was created by Chroot.
+	prefix string
+}
+
+// New returns a Filesystem that hides paths in fsys ignored by matcher. Paths
+// in fsys are matched as if fsys was rooted at the directory containing the
+// rules.
+func New(fsys billy.Filesystem, matcher gitignore.Matcher) *Filesystem {
+	return &Filesystem{
+		Filesystem: fsys,
+		matcher:    matcher,
+	}
+}
+
+// Create implements [billy.Basic].
+func (f *Filesystem) Create(filename string) (billy.File, error) {
+	return f.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+}
+
+// Open implements [billy.Basic].
+func (f *Filesystem) Open(filename string) (billy.File, error) {
+	if err := f.check("open", filename, f.Filesystem.Stat); err != nil {
+		return nil, err
+	}
+
+	file, err := f.Filesystem.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return file, nil
+}
+
+// OpenFile implements [billy.Basic]. Files that do not exist yet can always be
+// created.
+func (f *Filesystem) OpenFile(filename string, flag int, perm os.FileMode) (billy.File, error) {
+	err := f.check("open", filename, f.Filesystem.Stat)
+	if err != nil && (flag&os.O_CREATE == 0 || !f.notExist(filename)) {
+		return nil, err
+	}
+
+	file, err := f.Filesystem.OpenFile(filename, flag, perm)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return file, nil
+}
+
+// Stat implements [billy.Basic].
+func (f *Filesystem) Stat(filename string) (os.FileInfo, error) {
+	info, err := f.Filesystem.Stat(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if f.hidden(filename, info.IsDir()) {
+		return nil, &os.PathError{Op: "stat", Path: filename, Err: os.ErrNotExist}
+	}
+
+	return info, nil
+}
+
+// Lstat implements [billy.Symlink].
+func (f *Filesystem) Lstat(filename string) (os.FileInfo, error) {
+	info, err := f.Filesystem.Lstat(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if f.hidden(filename, info.IsDir()) {
+		return nil, &os.PathError{Op: "lstat", Path: filename, Err: os.ErrNotExist}
+	}
+
+	return info, nil
+}
+
+// Readlink implements [billy.Symlink].
+func (f *Filesystem) Readlink(link string) (string, error) {
+	if err := f.check("readlink", link, f.Filesystem.Lstat); err != nil {
+		return "", err
+	}
+
+	target, err := f.Filesystem.Readlink(link)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	return target, nil
+}
+
+// Rename implements [billy.Basic]. Like with OpenFile, newpath may be ignored
+// as long as it does not exist yet, so hidden files cannot be replaced.
+func (f *Filesystem) Rename(oldpath, newpath string) error {
+	if err := f.check("rename", oldpath, f.Filesystem.Lstat); err != nil {
+		return err
+	}
+
+	if err := f.check("rename", newpath, f.Filesystem.Lstat); err != nil && !f.notExist(newpath) {
+		return err
+	}
+
+	if err := f.Filesystem.Rename(oldpath, newpath); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// Remove implements [billy.Basic].
+func (f *Filesystem) Remove(filename string) error {
+	if err := f.check("remove", filename, f.Filesystem.Lstat); err != nil {
+		return err
+	}
+
+	if err := f.Filesystem.Remove(filename); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// ReadDir implements [billy.Dir].
+func (f *Filesystem) ReadDir(dir string) ([]os.FileInfo, error) {
+	if f.hidden(dir, true) {
+		return nil, &os.PathError{Op: "readdir", Path: dir, Err: os.ErrNotExist}
+	}
+
+	infos, err := f.Filesystem.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	filtered := make([]os.FileInfo, 0, len(infos))
+
+	for _, info := range infos {
+		if f.hidden(f.Join(dir, info.Name()), info.IsDir()) {
+			continue
+		}
+
+		filtered = append(filtered, info)
+	}
+
+	return filtered, nil
+}
+
+// Chroot implements [billy.Chroot]. The returned filesystem keeps matching
+// paths relative to the directory the rules apply to.
+func (f *Filesystem) Chroot(dir string) (billy.Filesystem, error) {
+	if f.hidden(dir, true) {
+		return nil, &os.PathError{Op: "chroot", Path: dir, Err: os.ErrNotExist}
+	}
+
+	fsys, err := f.Filesystem.Chroot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return &Filesystem{
+		Filesystem: fsys,
+		matcher:    f.matcher,
+		prefix:     path.Join(f.prefix, clean(dir)),
+	}, nil
+}
+
+// Capabilities implements [billy.Capable].
+func (f *Filesystem) Capabilities() billy.Capability {
+	return billy.Capabilities(f.Filesystem)
+}
+
+// check returns an error if filename exists but is hidden, using stat to find
+// out whether it refers to a directory. Errors from stat are returned as is.
+func (f *Filesystem) check(op, filename string, stat func(string) (os.FileInfo, error)) error {
+	info, err := stat(filename)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if f.hidden(filename, info.IsDir()) {
+		return &os.PathError{Op: op, Path: filename, Err: os.ErrNotExist}
+	}
+
+	return nil
+}
+
+// notExist reports whether filename does not exist yet, in which case creating
+// it is allowed even if the path is ignored.
+func (f *Filesystem) notExist(filename string) bool {
+	_, err := f.Filesystem.Lstat(filename)
+
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// hidden reports whether the given path should be hidden from callers.
+func (f *Filesystem) hidden(filename string, isDir bool) bool {
+	name := path.Join(f.prefix, clean(filename))
+	if name == "." || name == "" {
+		return false
+	}
+
+	return gitignore.IsIgnored(f.matcher, name, isDir)
+}
+
+// clean converts a billy path into a clean, slash-separated path relative to
+// the filesystem root.
+func clean(filename string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(filename)), "/")
+}
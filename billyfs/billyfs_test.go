@@ -0,0 +1,253 @@
+package billyfs_test
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/gitignore-go/billyfs"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+)
+
+func newTestFilesystem(t *testing.T) billy.Filesystem {
+	t.Helper()
+
+	matcher, err := gitignore.NewFromLines([]string{
+		"*.log",
+		"build/",
+		"!keep.log",
+	})
+	if err != nil {
+		t.Fatalf("failed to create matcher: %v", err)
+	}
+
+	fsys := memfs.New()
+
+	for _, name := range []string{
+		"main.go",
+		"debug.log",
+		"keep.log",
+		"build/out.bin",
+		"src/app.go",
+		"src/app.log",
+	} {
+		if err = util.WriteFile(fsys, name, []byte(name), 0o600); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	return billyfs.New(fsys, matcher)
+}
+
+func TestFilesystem_Stat(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilesystem(t)
+
+	tests := []struct {
+		name     string
+		givePath string
+		wantErr  error
+	}{
+		{
+			name:     "Included file",
+			givePath: "main.go",
+		},
+		{
+			name:     "Negated file",
+			givePath: "keep.log",
+		},
+		{
+			name:     "Ignored file",
+			givePath: "debug.log",
+			wantErr:  os.ErrNotExist,
+		},
+		{
+			name:     "Ignored directory",
+			givePath: "build",
+			wantErr:  os.ErrNotExist,
+		},
+		{
+			name:     "File inside ignored directory",
+			givePath: "build/out.bin",
+			wantErr:  os.ErrNotExist,
+		},
+		{
+			name:     "Nested ignored file with leading slash",
+			givePath: "/src/app.log",
+			wantErr:  os.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := fsys.Stat(tt.givePath); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Stat(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+
+			if _, err := fsys.Lstat(tt.givePath); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Lstat(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+
+			file, err := fsys.Open(tt.givePath)
+			if err == nil {
+				file.Close()
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Open(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilesystem_ReadDir(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilesystem(t)
+
+	infos, err := fsys.ReadDir("/")
+	if err != nil {
+		t.Fatalf("ReadDir(%q) unexpected error: %v", "/", err)
+	}
+
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+
+	slices.Sort(names)
+
+	want := []string{"keep.log", "main.go", "src"}
+
+	if !slices.Equal(names, want) {
+		t.Errorf("ReadDir(%q) = %v, want %v", "/", names, want)
+	}
+
+	if _, err = fsys.ReadDir("build"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadDir(%q) error = %v, want %v", "build", err, os.ErrNotExist)
+	}
+}
+
+func TestFilesystem_Write(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilesystem(t)
+
+	if err := fsys.Remove("debug.log"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Remove(%q) error = %v, want %v", "debug.log", err, os.ErrNotExist)
+	}
+
+	if err := fsys.Rename("debug.log", "debug.txt"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Rename(%q) error = %v, want %v", "debug.log", err, os.ErrNotExist)
+	}
+
+	if _, err := fsys.Create("debug.log"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Create(%q) on existing ignored file error = %v, want %v", "debug.log", err, os.ErrNotExist)
+	}
+
+	file, err := fsys.Create("new.log")
+	if err != nil {
+		t.Fatalf("Create(%q) unexpected error: %v", "new.log", err)
+	}
+
+	file.Close()
+
+	if _, err = fsys.Stat("new.log"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Stat(%q) after Create error = %v, want %v", "new.log", err, os.ErrNotExist)
+	}
+}
+
+func TestFilesystem_Rename(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOldPath string
+		giveNewPath string
+		wantErr     error
+	}{
+		{
+			name:        "Included paths",
+			giveOldPath: "main.go",
+			giveNewPath: "cmd.go",
+		},
+		{
+			name:        "Ignored old path",
+			giveOldPath: "debug.log",
+			giveNewPath: "debug.txt",
+			wantErr:     os.ErrNotExist,
+		},
+		{
+			name:        "Over an ignored file",
+			giveOldPath: "main.go",
+			giveNewPath: "debug.log",
+			wantErr:     os.ErrNotExist,
+		},
+		{
+			name:        "Over an ignored directory",
+			giveOldPath: "src",
+			giveNewPath: "build",
+			wantErr:     os.ErrNotExist,
+		},
+		{
+			name:        "To a new ignored path",
+			giveOldPath: "main.go",
+			giveNewPath: "main.log",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fsys := newTestFilesystem(t)
+
+			err := fsys.Rename(tt.giveOldPath, tt.giveNewPath)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Rename(%q, %q) unexpected error: %v", tt.giveOldPath, tt.giveNewPath, err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Rename(%q, %q) error = %v, want %v", tt.giveOldPath, tt.giveNewPath, err, tt.wantErr)
+			}
+
+			if _, err = fsys.Stat(tt.giveOldPath); tt.giveOldPath == "main.go" && err != nil {
+				t.Errorf("Stat(%q) after a failed Rename() error = %v", tt.giveOldPath, err)
+			}
+		})
+	}
+}
+
+func TestFilesystem_Chroot(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilesystem(t)
+
+	if _, err := fsys.Chroot("build"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Chroot(%q) error = %v, want %v", "build", err, os.ErrNotExist)
+	}
+
+	sub, err := fsys.Chroot("src")
+	if err != nil {
+		t.Fatalf("Chroot(%q) unexpected error: %v", "src", err)
+	}
+
+	if _, err = sub.Stat("app.go"); err != nil {
+		t.Errorf("Stat(%q) on chroot unexpected error: %v", "app.go", err)
+	}
+
+	if _, err = sub.Stat("app.log"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Stat(%q) on chroot error = %v, want %v", "app.log", err, os.ErrNotExist)
+	}
+}
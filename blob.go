@@ -0,0 +1,113 @@
+package gitignore
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+)
+
+// BlobReader returns the contents of the file at path, slash-separated and
+// relative to the root of a tree, such as the one of a commit, or an error
+// wrapping [fs.ErrNotExist] if the tree holds no such file. It is typically
+// implemented with git cat-file, reading the blob named "<revision>:<path>", or
+// with a git library. It must be safe for concurrent use.
+type BlobReader func(path string) ([]byte, error)
+
+// NewFromBlob creates a new File instance from the .gitignore file at path in
+// a tree, read with read, so tools can tell which paths the rules of a
+// revision ignore without checking it out. The rules are attributed to path.
+func NewFromBlob(read BlobReader, path string, opts ...Option) (*File, error) {
+	data, err := read(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return parse(path, bytes.NewReader(data), opts)
+}
+
+// WithBlobReader makes the Repository read the .gitignore and .gitmodules
+// files of the tree with read, such as from a commit, instead of from the
+// working tree, so callers can answer whether a path was ignored at a given
+// revision without checking it out. The rules set with WithPatterns,
+// info/exclude and the excludes file still apply, as they do not belong to
+// the tree. It works with bare repositories, which have no working tree.
+//
+// Nested repositories are only found through the .gitmodules file, and the
+// files read with read are not watched with WithWatch.
+func WithBlobReader(read BlobReader) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.blobs = read
+	}
+}
+
+// blobFS is a file system reading its files with a BlobReader. It has no
+// directories, which only the paths of its files imply.
+type blobFS BlobReader
+
+// Open implements fs.FS.
+func (fsys blobFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	data, err := fsys(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &blobFile{
+		Reader: bytes.NewReader(data),
+		name:   path.Base(name),
+		size:   int64(len(data)),
+	}, nil
+}
+
+// blobFile is a file of a blobFS, which is its own fs.FileInfo.
+type blobFile struct {
+	*bytes.Reader
+
+	name string
+	size int64
+}
+
+// Stat implements fs.File.
+func (f *blobFile) Stat() (fs.FileInfo, error) {
+	return f, nil
+}
+
+// Close implements fs.File.
+func (*blobFile) Close() error {
+	return nil
+}
+
+// Name implements fs.FileInfo.
+func (f *blobFile) Name() string {
+	return f.name
+}
+
+// Size implements fs.FileInfo.
+func (f *blobFile) Size() int64 {
+	return f.size
+}
+
+// Mode implements fs.FileInfo.
+func (*blobFile) Mode() fs.FileMode {
+	return 0o444
+}
+
+// ModTime implements fs.FileInfo. Blobs have no modification time.
+func (*blobFile) ModTime() time.Time {
+	return time.Time{}
+}
+
+// IsDir implements fs.FileInfo.
+func (*blobFile) IsDir() bool {
+	return false
+}
+
+// Sys implements fs.FileInfo.
+func (*blobFile) Sys() any {
+	return nil
+}
@@ -0,0 +1,107 @@
+package gitignore_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+// blobReader returns a BlobReader reading the files of a revision from blobs.
+func blobReader(blobs map[string]string) gitignore.BlobReader {
+	return func(path string) ([]byte, error) {
+		data, ok := blobs[path]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", path, fs.ErrNotExist)
+		}
+
+		return []byte(data), nil
+	}
+}
+
+func TestNewFromBlob(t *testing.T) {
+	t.Parallel()
+
+	read := blobReader(map[string]string{
+		"pkg/.gitignore": "*.log\n!keep.log\n",
+	})
+
+	file, err := gitignore.NewFromBlob(read, "pkg/.gitignore")
+	if err != nil {
+		t.Fatalf("NewFromBlob() error = %v", err)
+	}
+
+	result, ok := file.MatchResult("debug.log")
+	if !ok || !result.Ignored || result.Pattern.Source != "pkg/.gitignore" || result.Pattern.Line != 1 {
+		t.Errorf("MatchResult(%q) = %+v, %v, want a match of pkg/.gitignore:1", "debug.log", result, ok)
+	}
+
+	if file.Match("keep.log") {
+		t.Errorf("Match(%q) = true, want false", "keep.log")
+	}
+
+	if _, err = gitignore.NewFromBlob(read, ".gitignore"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("NewFromBlob() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestWithBlobReader(t *testing.T) {
+	t.Parallel()
+
+	read := blobReader(map[string]string{
+		".gitignore":     "*.log\n",
+		"pkg/.gitignore": "!debug.log\n*.tmp\n",
+		".gitmodules":    "[submodule \"lib\"]\n\tpath = vendor/lib\n",
+	})
+
+	bare := t.TempDir()
+
+	writeTree(t, bare, map[string]string{
+		"HEAD":             "ref: refs/heads/main\n",
+		"config":           "[core]\n\tbare = true\n",
+		"objects/.keep":    "",
+		"refs/heads/.keep": "",
+		"info/exclude":     "*.swp\n",
+	})
+
+	work := t.TempDir()
+
+	writeTree(t, work, map[string]string{
+		".git/info/exclude": "*.swp\n",
+		// The working tree differs from the revision, and is not read.
+		".gitignore": "*.md\n",
+	})
+
+	want := map[string]bool{
+		"a.log":            true,
+		"a.md":             false,
+		"a.swp":            true,
+		"pkg/debug.log":    false,
+		"pkg/a.tmp":        true,
+		"a.tmp":            false,
+		"vendor/lib/a.log": false,
+	}
+
+	for _, root := range []string{bare, work} {
+		repo, err := gitignore.OpenRepository(root,
+			gitignore.WithHomeDir(t.TempDir()),
+			gitignore.WithoutDefaultExcludes(),
+			gitignore.WithBlobReader(read),
+		)
+		if err != nil {
+			t.Fatalf("OpenRepository() error = %v", err)
+		}
+
+		for path, want := range want {
+			if got := repo.Match(path); got != want {
+				t.Errorf("Match(%q) = %v, want %v", path, got, want)
+			}
+		}
+
+		if result, ok := repo.CheckIgnore("pkg/a.tmp"); !ok || result.Pattern.Source != "pkg/.gitignore" || result.Pattern.Line != 2 {
+			t.Errorf("CheckIgnore(%q) = %+v, %v, want a match of pkg/.gitignore:2", "pkg/a.tmp", result, ok)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ignoreFile is the cached .gitignore file of a directory of a Repository.
+type ignoreFile struct {
+	// modTime is the modification time of the file when it was read.
+	modTime time.Time
+
+	// file holds the rules of the file, or nil if it does not exist or could
+	// not be read.
+	file *File
+
+	// err is the error reading the file, if any.
+	err error
+
+	// size is the size of the file when it was read.
+	size int64
+
+	// exists reports whether the file existed when it was read.
+	exists bool
+
+	// stale reports whether the entry must be checked against the file before
+	// being used again.
+	stale bool
+}
+
+// Invalidate makes r check the .gitignore file of the directory dir, given as
+// a slash-separated path relative to the root of the working tree, or "." for
+// the root itself, the next time it is needed, reading it again only if its
+// modification time or size changed. Whether dir holds a nested repository is
+// checked again as well. It is meant for long-running processes, such as file
+// watchers, told that the directory changed.
+func (r *Repository) Invalidate(dir string) {
+	dir = path.Clean(strings.TrimSuffix(dir, "/"))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.ignores[dir]; ok {
+		entry.stale = true
+	}
+
+	delete(r.probed, dir)
+
+	if registered, ok := r.submodules[dir]; ok && !registered {
+		delete(r.submodules, dir)
+	}
+}
+
+// InvalidateAll is like Invalidate for every directory of the working tree.
+// The configuration, excludes files and .gitmodules file are not read again.
+func (r *Repository) InvalidateAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, entry := range r.ignores {
+		entry.stale = true
+	}
+
+	clear(r.probed)
+
+	for dir, registered := range r.submodules {
+		if !registered {
+			delete(r.submodules, dir)
+		}
+	}
+}
+
+// ignore returns the rules of the .gitignore file of the directory name,
+// reading it on first use or when it changed since it was invalidated, or nil
+// if it has none.
+func (r *Repository) ignore(name string) *File {
+	file, first := r.load(name)
+
+	// Directories are watched as they are first used, so their .gitignore
+	// file is reloaded if it is created later on.
+	if first && r.watcher != nil && !r.bare && !r.blobs {
+		r.watchDir(filepath.Join(r.root, filepath.FromSlash(name)))
+	}
+
+	return file
+}
+
+// load returns the cached rules of the .gitignore file of the directory name,
+// reading it if needed, and whether it was read for the first time. The file
+// is read and parsed without holding r.mu, so other directories can be served
+// meanwhile.
+func (r *Repository) load(name string) (*File, bool) {
+	r.mu.Lock()
+
+	cached, ok := r.ignores[name]
+	fresh := ok && !cached.stale
+
+	r.mu.Unlock()
+
+	if fresh {
+		return cached.file, false
+	}
+
+	filename := path.Join(name, gitignoreFile)
+
+	info, err := fs.Stat(r.fsys, filename)
+	if ok && cached.unchanged(info, err) {
+		r.mu.Lock()
+
+		if r.ignores[name] == cached {
+			cached.stale = false
+		}
+
+		r.mu.Unlock()
+
+		return cached.file, false
+	}
+
+	entry := r.read(filename, info, err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Another goroutine may have read the file meanwhile.
+	current, found := r.ignores[name]
+	if found && current != cached && !current.stale {
+		return current.file, false
+	}
+
+	r.ignores[name] = entry
+
+	return entry.file, !found
+}
+
+// read returns the entry for the .gitignore file filename, described by info,
+// or missing if err is fs.ErrNotExist, parsing it if it exists.
+func (r *Repository) read(filename string, info fs.FileInfo, err error) *ignoreFile {
+	entry := &ignoreFile{}
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		entry.err = fmt.Errorf("%w", err)
+	default:
+		entry.exists = true
+		entry.modTime = info.ModTime()
+		entry.size = info.Size()
+
+		entry.file, entry.err = NewFromFS(r.fsys, filename, r.options()...)
+		if entry.err != nil {
+			entry.file = nil
+		}
+	}
+
+	return entry
+}
+
+// unchanged reports whether the file described by info, or missing if err is
+// fs.ErrNotExist, is the one e was read from.
+func (e *ignoreFile) unchanged(info fs.FileInfo, err error) bool {
+	if err != nil {
+		return !e.exists && e.err == nil && errors.Is(err, fs.ErrNotExist)
+	}
+
+	return e.exists && info.Size() == e.size && info.ModTime().Equal(e.modTime)
+}
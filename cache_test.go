@@ -0,0 +1,181 @@
+package gitignore_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestRepository_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":    "",
+		".gitignore":     "*.log\n",
+		"pkg/.gitignore": "*.tmp\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	check := func(path string, want bool) {
+		t.Helper()
+
+		if got := repo.Match(path); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	check("pkg/a.tmp", true)
+	check("pkg/a.txt", false)
+
+	writeTree(t, root, map[string]string{"pkg/.gitignore": "*.txt\n*.md\n"})
+
+	// The cached rules are used until the directory is invalidated.
+	check("pkg/a.tmp", true)
+
+	repo.Invalidate("pkg")
+
+	check("pkg/a.tmp", false)
+	check("pkg/a.txt", true)
+
+	if err = os.Remove(filepath.Join(root, "pkg", ".gitignore")); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	repo.Invalidate("pkg/")
+
+	check("pkg/a.txt", false)
+
+	writeTree(t, root, map[string]string{
+		".gitignore":        "*.txt\n*.md\n",
+		"pkg/.gitignore":    "*.tmp\n",
+		"nested/.git/HEAD":  "",
+		"nested/.gitignore": "",
+	})
+
+	check("a.txt", false)
+
+	repo.InvalidateAll()
+
+	check("a.log", false)
+	check("a.txt", true)
+	check("pkg/a.tmp", true)
+	check("nested/a.txt", false)
+
+	if got, want := repo.Submodules(), []string{"nested"}; !slices.Equal(got, want) {
+		t.Errorf("Submodules() = %q, want %q", got, want)
+	}
+}
+
+func TestRepository_Invalidate_Error(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config": "",
+		".gitignore":  "[invalid\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	repo.Match("a.log")
+
+	if err = repo.Err(); err == nil {
+		t.Fatal("Err() = nil, want an error")
+	}
+
+	writeTree(t, root, map[string]string{".gitignore": "*.log\n"})
+	repo.Invalidate(".")
+
+	if !repo.Match("a.log") {
+		t.Errorf("Match(%q) = false, want true", "a.log")
+	}
+
+	if err = repo.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestRepository_ConcurrentLoad(t *testing.T) {
+	t.Parallel()
+
+	var (
+		reading = make(chan struct{})
+		release = make(chan struct{})
+		once    sync.Once
+	)
+
+	// Reading slow/.gitignore blocks until released, which must not keep
+	// other directories from being served.
+	read := func(path string) ([]byte, error) {
+		switch path {
+		case "slow/.gitignore":
+			once.Do(func() { close(reading) })
+			<-release
+
+			return []byte("*.tmp\n"), nil
+		case "fast/.gitignore":
+			return []byte("*.log\n"), nil
+		default:
+			return nil, fmt.Errorf("%s: %w", path, fs.ErrNotExist)
+		}
+	}
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{".git/config": ""})
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithBlobReader(read),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	slow := make(chan bool)
+
+	go func() {
+		slow <- repo.Match("slow/a.tmp")
+	}()
+
+	<-reading
+
+	fast := make(chan bool)
+
+	go func() {
+		fast <- repo.Match("fast/a.log")
+	}()
+
+	select {
+	case got := <-fast:
+		if !got {
+			t.Errorf("Match(%q) = false, want true", "fast/a.log")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Match() blocked while another .gitignore file was read")
+	}
+
+	close(release)
+
+	if !<-slow {
+		t.Errorf("Match(%q) = false, want true", "slow/a.tmp")
+	}
+}
@@ -0,0 +1,24 @@
+module git.sr.ht/~jamesponddotco/gitignore-go/cmd/gitignore-grpc
+
+go 1.23.0
+
+require (
+	git.sr.ht/~jamesponddotco/gitignore-go v0.0.0
+	git.sr.ht/~jamesponddotco/gitignore-go/api v0.0.0
+	google.golang.org/grpc v1.75.0
+)
+
+require (
+	git.sr.ht/~jamesponddotco/xstd-go v0.9.0 // indirect
+	github.com/fsnotify/fsnotify v1.10.1 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+)
+
+replace (
+	git.sr.ht/~jamesponddotco/gitignore-go => ../..
+	git.sr.ht/~jamesponddotco/gitignore-go/api => ../../api
+)
@@ -0,0 +1,66 @@
+// Command gitignore-grpc serves the gitignore gRPC API, so build farms and
+// tools written in any language can evaluate gitignore rules with one canonical
+// implementation.
+//
+// Usage:
+//
+//	gitignore-grpc [-addr host:port] [-root dir]
+//
+// The service is defined in api/gitignore/v1/gitignore.proto. Walk requests are
+// resolved against the root directory and cannot escape it.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	gitignorev1 "git.sr.ht/~jamesponddotco/gitignore-go/api/gitignore/v1"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	var (
+		addr = flag.String("addr", "localhost:50051", "address to listen on")
+		root = flag.String("root", ".", "directory Walk requests are resolved against")
+	)
+
+	flag.Parse()
+
+	if err := run(*addr, *root); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the gitignore gRPC API on addr until the process is interrupted.
+func run(addr, root string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	srv := grpc.NewServer()
+	gitignorev1.RegisterGitignoreServiceServer(srv, newServer(root))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	log.Printf("serving gitignore gRPC API on %s", listener.Addr())
+
+	if err = srv.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
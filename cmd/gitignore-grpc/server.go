@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	gitignorev1 "git.sr.ht/~jamesponddotco/gitignore-go/api/gitignore/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// server implements [gitignorev1.GitignoreServiceServer].
+type server struct {
+	gitignorev1.UnimplementedGitignoreServiceServer
+
+	// root is the directory Walk requests are resolved against.
+	root string
+}
+
+// newServer returns a server resolving Walk requests against root.
+func newServer(root string) *server {
+	return &server{
+		root: root,
+	}
+}
+
+// Match implements [gitignorev1.GitignoreServiceServer].
+func (*server) Match(_ context.Context, req *gitignorev1.MatchRequest) (*gitignorev1.MatchResponse, error) {
+	file, err := gitignore.NewFromLines(req.GetLines())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	results := make([]*gitignorev1.MatchResult, 0, len(req.GetPaths()))
+
+	for _, p := range req.GetPaths() {
+		results = append(results, &gitignorev1.MatchResult{
+			Path:    p,
+			Ignored: file.Match(p),
+		})
+	}
+
+	return &gitignorev1.MatchResponse{
+		Results: results,
+	}, nil
+}
+
+// Explain implements [gitignorev1.GitignoreServiceServer].
+func (*server) Explain(_ context.Context, req *gitignorev1.ExplainRequest) (*gitignorev1.ExplainResponse, error) {
+	file, err := gitignore.NewFromLines(req.GetLines())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	matching := file.MatchAll(req.GetPath())
+	resp := &gitignorev1.ExplainResponse{
+		Ignored: file.Match(req.GetPath()),
+		Rules:   make([]*gitignorev1.Rule, 0, len(matching)),
+	}
+
+	for _, rule := range matching {
+		resp.Rules = append(resp.Rules, &gitignorev1.Rule{
+			Pattern: rule.Raw,
+			Line:    int32(rule.Line), //nolint:gosec // A request cannot hold more than 2^31 lines.
+			Negate:  rule.Negate,
+		})
+	}
+
+	return resp, nil
+}
+
+// Walk implements [gitignorev1.GitignoreServiceServer].
+func (s *server) Walk(req *gitignorev1.WalkRequest, stream grpc.ServerStreamingServer[gitignorev1.WalkResponse]) error {
+	file, err := gitignore.NewFromLines(req.GetLines())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	dir := path.Clean(req.GetDir())
+	if !fs.ValidPath(dir) {
+		return status.Errorf(codes.InvalidArgument, "invalid directory %q", req.GetDir())
+	}
+
+	fsys := gitignore.NewFilterFS(os.DirFS(filepath.Join(s.root, filepath.FromSlash(dir))), file)
+
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err = stream.Context().Err(); err != nil {
+			return err //nolint:wrapcheck // Converted into a status below.
+		}
+
+		if name == "." {
+			return nil
+		}
+
+		return stream.Send(&gitignorev1.WalkResponse{
+			Path:  name,
+			IsDir: d.IsDir(),
+		})
+	})
+
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return status.Errorf(codes.NotFound, "directory %q not found", req.GetDir())
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return status.FromContextError(err).Err()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	gitignorev1 "git.sr.ht/~jamesponddotco/gitignore-go/api/gitignore/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, root string) gitignorev1.GitignoreServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	srv := grpc.NewServer()
+	gitignorev1.RegisterGitignoreServiceServer(srv, newServer(root))
+
+	go srv.Serve(listener) //nolint:errcheck // Serve returns when the server is stopped.
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return gitignorev1.NewGitignoreServiceClient(conn)
+}
+
+func TestServer_Match(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, t.TempDir())
+
+	resp, err := client.Match(context.Background(), &gitignorev1.MatchRequest{
+		Lines: []string{"*.log", "!keep.log", "build/"},
+		Paths: []string{"debug.log", "keep.log", "main.go", "build/"},
+	})
+	if err != nil {
+		t.Fatalf("Match() unexpected error: %v", err)
+	}
+
+	want := []bool{true, false, false, true}
+
+	for i, result := range resp.GetResults() {
+		if result.GetIgnored() != want[i] {
+			t.Errorf("Match() result for %q = %v, want %v", result.GetPath(), result.GetIgnored(), want[i])
+		}
+	}
+
+	_, err = client.Match(context.Background(), &gitignorev1.MatchRequest{
+		Lines: []string{"[invalid-regex"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Match() with invalid rules error = %v, want code %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestServer_Explain(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, t.TempDir())
+
+	resp, err := client.Explain(context.Background(), &gitignorev1.ExplainRequest{
+		Lines: []string{"# Logs", "*.log", "debug.*", "!debug.log", "build/"},
+		Path:  "debug.log",
+	})
+	if err != nil {
+		t.Fatalf("Explain() unexpected error: %v", err)
+	}
+
+	if resp.GetIgnored() {
+		t.Errorf("Explain() ignored = true, want false")
+	}
+
+	var lines []int32
+	for _, rule := range resp.GetRules() {
+		lines = append(lines, rule.GetLine())
+	}
+
+	if want := []int32{2, 3, 4}; !slices.Equal(lines, want) {
+		t.Errorf("Explain() matching lines = %v, want %v", lines, want)
+	}
+
+	if !resp.GetRules()[2].GetNegate() {
+		t.Errorf("Explain() rule %q negate = false, want true", resp.GetRules()[2].GetPattern())
+	}
+
+	if got := resp.GetRules()[2].GetPattern(); got != "!debug.log" {
+		t.Errorf("Explain() rule pattern = %q, want %q", got, "!debug.log")
+	}
+}
+
+func TestServer_Walk(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	for _, name := range []string{
+		"project/main.go",
+		"project/debug.log",
+		"project/node_modules/left-pad/index.js",
+		"project/src/app.go",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("failed to create test directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(name), 0o600); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	client := newTestClient(t, root)
+
+	stream, err := client.Walk(context.Background(), &gitignorev1.WalkRequest{
+		Lines: []string{"*.log", "node_modules/"},
+		Dir:   "project",
+	})
+	if err != nil {
+		t.Fatalf("Walk() unexpected error: %v", err)
+	}
+
+	var paths []string
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Walk() unexpected error: %v", err)
+		}
+
+		paths = append(paths, resp.GetPath())
+	}
+
+	if want := []string{"main.go", "src", "src/app.go"}; !slices.Equal(paths, want) {
+		t.Errorf("Walk() = %v, want %v", paths, want)
+	}
+
+	for _, dir := range []string{"../outside", "/etc"} {
+		stream, err = client.Walk(context.Background(), &gitignorev1.WalkRequest{Dir: dir})
+		if err != nil {
+			t.Fatalf("Walk() unexpected error: %v", err)
+		}
+
+		if _, err = stream.Recv(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Walk(%q) error = %v, want code %v", dir, err, codes.InvalidArgument)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Command gitignore generates .gitignore files from the templates embedded in
+// this module.
+//
+// Usage:
+//
+//	gitignore gen [-auto] [-dir dir] [-source dir] [template ...]
+//	gitignore list
+//
+// The gen subcommand writes a .gitignore file combining the given templates to
+// standard output, with one managed section per template. With -auto, the
+// templates recommended for the project in dir are added to the given ones.
+// With -source, templates are read from a directory laid out like the
+// github/gitignore repository instead of the embedded ones. The list subcommand
+// prints the names of the available templates.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/templates"
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+const (
+	// ErrUsage is returned when the command is called with invalid arguments.
+	ErrUsage xerrors.Error = "usage: gitignore gen [-auto] [-dir dir] [-source dir] [template ...] | gitignore list"
+
+	// ErrNoTemplates is returned when gen has no template to generate.
+	ErrNoTemplates xerrors.Error = "no templates given or detected"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gitignore: ")
+
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+
+		log.Fatal(err)
+	}
+}
+
+// run executes the subcommand given in args, writing its output to w.
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "gen":
+		return gen(args[1:], w)
+	case "list":
+		return list(w)
+	default:
+		return fmt.Errorf("%w: unknown subcommand %q", ErrUsage, args[0])
+	}
+}
+
+// gen writes a .gitignore file combining the requested templates to w.
+func gen(args []string, w io.Writer) error {
+	var (
+		flags  = flag.NewFlagSet("gen", flag.ContinueOnError)
+		auto   = flags.Bool("auto", false, "add the templates recommended for the project in dir")
+		dir    = flags.String("dir", ".", "directory inspected by -auto")
+		source = flags.String("source", "", "directory to read templates from instead of the embedded ones")
+	)
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	names := flags.Args()
+
+	if *auto {
+		detected, err := templates.Detect(os.DirFS(*dir))
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		names = append(names, detected...)
+	}
+
+	if len(names) == 0 {
+		return ErrNoTemplates
+	}
+
+	var src templates.TemplateSource = templates.Embedded()
+	if *source != "" {
+		src = templates.NewDirSource(*source)
+	}
+
+	lines, err := templates.ComposeFrom(context.Background(), src, names...)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if _, err = io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// list writes the names of the available templates to w, one per line.
+func list(w io.Writer) error {
+	for _, name := range templates.Templates() {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	return nil
+}
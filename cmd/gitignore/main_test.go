@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/templates"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, name := range []string{"go.mod", "package.json", "Dockerfile"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	source := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(source, "Company.gitignore"), []byte("/secrets/\n"), 0o600); err != nil {
+		t.Fatalf("failed to create template: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		giveArgs     []string
+		wantErr      error
+		wantContains []string
+	}{
+		{
+			name:         "Gen with explicit templates",
+			giveArgs:     []string{"gen", "macOS"},
+			wantContains: []string{"# gitignore-go:begin macOS", ".DS_Store"},
+		},
+		{
+			name:     "Gen with detected templates",
+			giveArgs: []string{"gen", "-auto", "-dir", dir, "vim"},
+			wantContains: []string{
+				"# gitignore-go:begin Vim",
+				"# gitignore-go:begin Go",
+				"# gitignore-go:begin Node",
+			},
+		},
+		{
+			name:         "Gen with template source",
+			giveArgs:     []string{"gen", "-source", source, "company"},
+			wantContains: []string{"# gitignore-go:begin Company", "/secrets/"},
+		},
+		{
+			name:     "Gen with missing template in source",
+			giveArgs: []string{"gen", "-source", source, "Go"},
+			wantErr:  templates.ErrTemplateNotFound,
+		},
+		{
+			name:     "Gen without templates",
+			giveArgs: []string{"gen", "-auto", "-dir", t.TempDir()},
+			wantErr:  ErrNoTemplates,
+		},
+		{
+			name:         "List",
+			giveArgs:     []string{"list"},
+			wantContains: []string{"Go\n", "Python\n"},
+		},
+		{
+			name:     "Unknown subcommand",
+			giveArgs: []string{"frobnicate"},
+			wantErr:  ErrUsage,
+		},
+		{
+			name:    "No subcommand",
+			wantErr: ErrUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out strings.Builder
+
+			err := run(tt.giveArgs, &out)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run() error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("run() output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+//go:build conformance
+
+package gitignore_test
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/conformance"
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/property"
+)
+
+// conformanceCases covers the edge cases documented in gitignore(5).
+//
+//nolint:gochecknoglobals // Read-only test table.
+var conformanceCases = []conformance.Case{
+	{
+		Name:  "Basename glob",
+		Rules: []string{"*.log"},
+		Paths: []string{"a.log", "dir/b.log", "a.txt", "log"},
+	},
+	{
+		Name:  "Anchored pattern",
+		Rules: []string{"/build"},
+		Paths: []string{"build", "src/build", "builds"},
+	},
+	{
+		Name:  "Pattern with middle slash is anchored",
+		Rules: []string{"doc/frotz"},
+		Paths: []string{"doc/frotz", "a/doc/frotz"},
+	},
+	{
+		Name:  "Directory-only pattern",
+		Rules: []string{"out/"},
+		Paths: []string{"out/", "out/file", "src/out/", "src/out/file", "other/out"},
+	},
+	{
+		Name:  "Files inside excluded directories",
+		Rules: []string{"vendor/"},
+		Paths: []string{"vendor/a/b/c.go", "x/vendor/y.go"},
+	},
+	{
+		Name:  "Negation re-includes file",
+		Rules: []string{"*.log", "!keep.log"},
+		Paths: []string{"a.log", "keep.log", "dir/keep.log"},
+	},
+	{
+		Name:  "Last matching pattern wins",
+		Rules: []string{"!keep.log", "*.log"},
+		Paths: []string{"keep.log", "a.log"},
+	},
+	{
+		Name:  "Negation cannot re-include inside excluded directory",
+		Rules: []string{"logs/", "!logs/keep.log"},
+		Paths: []string{"logs/keep.log", "logs/other.log"},
+	},
+	{
+		Name:  "Re-include directory contents with star",
+		Rules: []string{"/*", "!/src"},
+		Paths: []string{"README", "src/main.go", "other/", "other/file"},
+	},
+	{
+		Name:  "Leading double star",
+		Rules: []string{"**/foo"},
+		Paths: []string{"foo", "a/foo", "a/b/foo", "c/foo/bar"},
+	},
+	{
+		Name:  "Leading double star with path",
+		Rules: []string{"**/foo/bar"},
+		Paths: []string{"foo/bar", "a/b/foo/bar", "foo/baz"},
+	},
+	{
+		Name:  "Trailing double star",
+		Rules: []string{"abc/**"},
+		Paths: []string{"abc/x", "abc/y/z", "x/abc/y"},
+	},
+	{
+		Name:  "Middle double star",
+		Rules: []string{"a/**/b"},
+		Paths: []string{"a/b", "a/x/b", "a/x/y/b", "x/a/b"},
+	},
+	{
+		Name:  "Single star does not cross directories",
+		Rules: []string{"foo/*"},
+		Paths: []string{"foo/test.json", "foo/bar/", "foo/bar/hello.c", "x/foo/bar"},
+	},
+	{
+		Name:  "Question mark",
+		Rules: []string{"file?.txt"},
+		Paths: []string{"file1.txt", "file10.txt", "file.txt"},
+	},
+	{
+		Name:  "Character class",
+		Rules: []string{"[ab].txt", "[!x]y"},
+		Paths: []string{"a.txt", "c.txt", "zy", "xy"},
+	},
+	{
+		Name:  "Escaped special characters",
+		Rules: []string{`\#hash`, `\!bang`, `star\*`},
+		Paths: []string{"#hash", "!bang", "star*", "stars"},
+	},
+	{
+		Name:  "Trailing spaces are ignored unless escaped",
+		Rules: []string{"trail  ", `space\ `},
+		Paths: []string{"trail", "space "},
+	},
+	{
+		Name:  "Comments and blank lines",
+		Rules: []string{"# comment", "", "real"},
+		Paths: []string{"# comment", "real"},
+	},
+	{
+		Name:  "Dots are literal",
+		Rules: []string{"a.b"},
+		Paths: []string{"a.b", "axb"},
+	},
+}
+
+// knownDivergences lists the cases where this package is known to disagree
+// with git, and why. They are skipped, and reported when they start passing so
+// the entry can be removed.
+//
+//nolint:gochecknoglobals // Read-only test table.
+var knownDivergences = map[string]string{
+	"Pattern with middle slash is anchored":      "patterns containing a slash are matched at any depth",
+	"Single star does not cross directories":     "patterns containing a slash are matched at any depth",
+	"Trailing double star":                       "patterns containing a slash are matched at any depth",
+	"Middle double star":                         "patterns containing a slash are matched at any depth",
+	"Question mark":                              `"?" is matched literally instead of as any character`,
+	"Character class":                            `"[!...]" is not translated to a negated character class`,
+	"Trailing spaces are ignored unless escaped": "escaped trailing spaces fail to compile",
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	for _, tt := range conformanceCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			if err := conformance.Materialize(ctx, dir, tt); err != nil {
+				t.Fatalf("Materialize() unexpected error: %v", err)
+			}
+
+			want, err := conformance.CheckIgnore(ctx, dir, tt.Paths)
+			if err != nil {
+				t.Fatalf("CheckIgnore() unexpected error: %v", err)
+			}
+
+			var diffs []string
+
+			matcher, err := gitignore.NewFromLines(tt.Rules)
+			if err != nil {
+				diffs = append(diffs, "  NewFromLines() unexpected error: "+err.Error())
+				want = nil
+			}
+
+			for _, result := range want {
+				name := strings.TrimSuffix(result.Path, "/")
+				got := gitignore.IsIgnored(matcher, name, strings.HasSuffix(result.Path, "/"))
+
+				if got != result.Ignored {
+					diffs = append(diffs, "  "+result.Path+": got ignored="+boolString(got)+
+						", git ignored="+boolString(result.Ignored)+" (pattern "+quote(result.Pattern)+")")
+				}
+			}
+
+			reason, known := knownDivergences[tt.Name]
+
+			switch {
+			case known && len(diffs) == 0:
+				t.Errorf("case now agrees with git, remove it from knownDivergences (%s)", reason)
+			case known:
+				t.Skipf("known divergence: %s\n%s", reason, strings.Join(diffs, "\n"))
+			case len(diffs) > 0:
+				t.Errorf("decisions differ from git check-ignore:\n%s", strings.Join(diffs, "\n"))
+			}
+		})
+	}
+}
+
+// TestConformance_Wildmatch checks that the wildmatch engine agrees with git on
+// every case, including the known divergences of the default engine.
+func TestConformance_Wildmatch(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	for _, tt := range conformanceCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			if err := conformance.Materialize(ctx, dir, tt); err != nil {
+				t.Fatalf("Materialize() unexpected error: %v", err)
+			}
+
+			want, err := conformance.CheckIgnore(ctx, dir, tt.Paths)
+			if err != nil {
+				t.Fatalf("CheckIgnore() unexpected error: %v", err)
+			}
+
+			matcher, err := gitignore.NewFromLines(tt.Rules, gitignore.WithWildmatch())
+			if err != nil {
+				t.Fatalf("NewFromLines() unexpected error: %v", err)
+			}
+
+			for _, result := range want {
+				name := strings.TrimSuffix(result.Path, "/")
+
+				if got := gitignore.IsIgnored(matcher, name, strings.HasSuffix(result.Path, "/")); got != result.Ignored {
+					t.Errorf("%s: got ignored=%t, git ignored=%t (pattern %q)", result.Path, got, result.Ignored, result.Pattern)
+				}
+			}
+		})
+	}
+}
+
+// TestConformance_Property checks that the expected decisions of the property
+// test generator agree with git.
+func TestConformance_Property(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	gen := property.NewGenerator(uint64(time.Now().UnixNano()))
+
+	for i := range 200 {
+		tt := gen.Next()
+
+		path := tt.Path
+		if tt.IsDir {
+			path += "/"
+		}
+
+		c := conformance.Case{
+			Name:  tt.Kind,
+			Rules: tt.Rules,
+			Paths: []string{path},
+		}
+
+		ctx := context.Background()
+		dir := t.TempDir()
+
+		if err := conformance.Materialize(ctx, dir, c); err != nil {
+			t.Fatalf("Materialize() unexpected error: %v", err)
+		}
+
+		results, err := conformance.CheckIgnore(ctx, dir, c.Paths)
+		if err != nil {
+			t.Fatalf("CheckIgnore() unexpected error: %v", err)
+		}
+
+		if results[0].Ignored != tt.Ignored {
+			t.Errorf("case %d (%s): git ignored %q with rules %q = %t, generator expects %t",
+				i, tt.Kind, path, tt.Rules, results[0].Ignored, tt.Ignored)
+		}
+	}
+}
+
+// repositoryCases covers the precedence of the ignore sources of a repository,
+// as documented in gitignore(5).
+//
+//nolint:gochecknoglobals // Read-only test table.
+var repositoryCases = []conformance.Case{
+	{
+		Name:  "Nested negation overrides root rule",
+		Rules: []string{"*.log"},
+		Files: map[string][]string{"pkg/.gitignore": {"!debug.log"}},
+		Paths: []string{"debug.log", "pkg/debug.log", "pkg/other.log"},
+	},
+	{
+		Name:  "Nested rule overrides root negation",
+		Rules: []string{"*.txt", "!keep.txt"},
+		Files: map[string][]string{"pkg/.gitignore": {"keep.txt"}},
+		Paths: []string{"keep.txt", "pkg/keep.txt", "pkg/sub/keep.txt", "pkg/a.txt"},
+	},
+	{
+		Name:  "Deepest directory wins",
+		Rules: []string{},
+		Files: map[string][]string{
+			"a/.gitignore":     {"*.tmp"},
+			"a/b/.gitignore":   {"!x.tmp"},
+			"a/b/c/.gitignore": {"x.tmp"},
+		},
+		Paths: []string{"a/x.tmp", "a/b/x.tmp", "a/b/y.tmp", "a/b/c/x.tmp", "a/b/c/d/x.tmp"},
+	},
+	{
+		Name:  ".gitignore overrides info/exclude",
+		Rules: []string{"!keep.swp", "*.md"},
+		Files: map[string][]string{".git/info/exclude": {"*.swp", "!*.md"}},
+		Paths: []string{"a.swp", "keep.swp", "README.md"},
+	},
+	{
+		Name:  "info/exclude overrides core.excludesFile",
+		Rules: []string{},
+		Files: map[string][]string{
+			".git/config":       {"[core]", "\texcludesFile = global-ignore"},
+			"global-ignore":     {"*.bak", "*.orig"},
+			".git/info/exclude": {"!keep.bak"},
+		},
+		Paths: []string{"a.bak", "keep.bak", "a.orig", "pkg/keep.bak"},
+	},
+	{
+		Name:  "Nested negation cannot re-include inside excluded directory",
+		Rules: []string{"build/"},
+		Files: map[string][]string{"build/.gitignore": {"!*"}},
+		Paths: []string{"build/", "build/out.o", "build/sub/out.o"},
+	},
+	{
+		Name:  "Negation in excludes file re-includes nothing ignored by .gitignore",
+		Rules: []string{"*.log"},
+		Files: map[string][]string{
+			".git/config":   {"[core]", "\texcludesFile = global-ignore"},
+			"global-ignore": {"!*.log"},
+		},
+		Paths: []string{"debug.log", "pkg/debug.log"},
+	},
+}
+
+func TestConformance_Repository(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	for _, tt := range repositoryCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			if err := conformance.Materialize(ctx, dir, tt); err != nil {
+				t.Fatalf("Materialize() unexpected error: %v", err)
+			}
+
+			want, err := conformance.CheckIgnore(ctx, dir, tt.Paths)
+			if err != nil {
+				t.Fatalf("CheckIgnore() unexpected error: %v", err)
+			}
+
+			// Isolate the repository from the configuration of the user,
+			// like the git binary run by CheckIgnore.
+			repo, err := gitignore.OpenRepository(dir,
+				gitignore.WithHomeDir(dir),
+				gitignore.WithConfigHome(dir),
+				gitignore.WithEnv(func(_ string) string { return "" }),
+			)
+			if err != nil {
+				t.Fatalf("OpenRepository() unexpected error: %v", err)
+			}
+
+			for _, result := range want {
+				if got := repo.Match(result.Path); got != result.Ignored {
+					t.Errorf("%s: got ignored=%t, git ignored=%t (pattern %s)", result.Path, got, result.Ignored, quote(result.Pattern))
+				}
+
+				// Like git check-ignore -v, CheckIgnore reports the
+				// deciding pattern, negated or not.
+				got, _ := repo.CheckIgnore(result.Path)
+				if got.Pattern.Raw != result.Pattern || got.Pattern.Line != result.Line {
+					t.Errorf("%s: CheckIgnore() pattern = %s at line %d, git pattern = %s at line %d",
+						result.Path, quote(got.Pattern.Raw), got.Pattern.Line, quote(result.Pattern), result.Line)
+				}
+
+				if strings.HasSuffix(result.Source, gitignoreFile) && got.Pattern.Source != result.Source {
+					t.Errorf("%s: CheckIgnore() source = %q, git source = %q", result.Path, got.Pattern.Source, result.Source)
+				}
+			}
+		})
+	}
+}
+
+// gitignoreFile is the name of the per-directory ignore files.
+const gitignoreFile = ".gitignore"
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+
+	return "false"
+}
+
+func quote(s string) string {
+	if s == "" {
+		return "none"
+	}
+
+	return `"` + s + `"`
+}
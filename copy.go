@@ -0,0 +1,196 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// CopyOption configures how CopyDir copies a tree.
+type CopyOption func(o *copyOptions)
+
+// copyOptions holds the configuration set by the options given to CopyDir.
+type copyOptions struct {
+	symlinks bool
+}
+
+// WithSymlinks makes CopyDir recreate symbolic links as links to the same
+// target, like git stores them, instead of copying the files they point to.
+func WithSymlinks() CopyOption {
+	return func(o *copyOptions) {
+		o.symlinks = true
+	}
+}
+
+// CopyDir copies the files and directories below src that m does not ignore to
+// dst, which is created if needed, such as to scaffold a project from a
+// template or vendor a dependency. Ignored directories are pruned without being
+// read, like with Walk, and the .git directories are skipped. Existing files of
+// dst are overwritten, and dst is skipped if it is inside src.
+//
+// The permissions of files and directories are preserved. Symbolic links are
+// followed, and the files they point to copied as regular files, unless
+// WithSymlinks is given; links to directories are then skipped, so copies never
+// loop. Other files, such as sockets, are skipped.
+func CopyDir(src, dst string, m Matcher, opts ...CopyOption) error {
+	o := &copyOptions{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	c := &copier{opts: o, src: absSrc, dst: absDst}
+
+	err = Walk(absSrc, m, c.copy)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return c.restoreDirs()
+}
+
+// copier holds the state of a CopyDir call.
+type copier struct {
+	opts *copyOptions
+	src  string
+	dst  string
+
+	// dirs holds the directories created, along with their permissions,
+	// which are only set once their contents are copied, in case they do not
+	// allow writing.
+	dirs  []string
+	modes []fs.FileMode
+}
+
+// copy copies the entry name of the source tree, and is a [fs.WalkDirFunc].
+func (c *copier) copy(name string, d fs.DirEntry, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if name == c.dst || (name != c.src && d.Name() == gitDir) {
+		return skipEntry(d)
+	}
+
+	rel, err := filepath.Rel(c.src, name)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	target := filepath.Join(c.dst, rel)
+
+	info, err := d.Info()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		return c.copySymlink(name, target)
+	}
+
+	return c.copyEntry(name, target, info)
+}
+
+// copyEntry copies the directory or regular file name, described by info, to
+// target.
+func (c *copier) copyEntry(name, target string, info fs.FileInfo) error {
+	switch {
+	case info.IsDir():
+		if err := os.MkdirAll(target, info.Mode().Perm()|0o700); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		c.dirs = append(c.dirs, target)
+		c.modes = append(c.modes, info.Mode().Perm())
+
+		return nil
+	case info.Mode().IsRegular():
+		return copyRegular(name, target, info.Mode().Perm())
+	default:
+		return nil
+	}
+}
+
+// copySymlink copies the symbolic link name to target, as a link or as the
+// file it points to, depending on the options of c.
+func (c *copier) copySymlink(name, target string) error {
+	if c.opts.symlinks {
+		link, err := os.Readlink(name)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if err = os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w", err)
+		}
+
+		if err = os.Symlink(link, target); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return nil
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if info.IsDir() {
+		return nil
+	}
+
+	return c.copyEntry(name, target, info)
+}
+
+// restoreDirs sets the permissions of the directories created, from the
+// deepest.
+func (c *copier) restoreDirs() error {
+	for i, dir := range slices.Backward(c.dirs) {
+		if err := os.Chmod(dir, c.modes[i]); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	return nil
+}
+
+// copyRegular copies the regular file name to target, with the permissions
+// mode.
+func copyRegular(name, target string, mode fs.FileMode) error {
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err = copyFile(file, name); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	// The mode given to OpenFile is subject to the umask, and ignored for
+	// existing files.
+	if err = os.Chmod(target, mode); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package gitignore_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestCopyDir(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveOpts []gitignore.CopyOption
+		wantLink bool
+	}{
+		{
+			name: "Follow symlinks",
+		},
+		{
+			name:     "Keep symlinks",
+			giveOpts: []gitignore.CopyOption{gitignore.WithSymlinks()},
+			wantLink: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src := newArchiveTree(t)
+
+			writeTree(t, src, map[string]string{"bin/run.sh": "#!/bin/sh\n"})
+
+			if err := os.Chmod(filepath.Join(src, "bin", "run.sh"), 0o755); err != nil {
+				t.Fatalf("failed to change mode: %v", err)
+			}
+
+			if err := os.Chmod(filepath.Join(src, "bin"), 0o555); err != nil {
+				t.Fatalf("failed to change mode: %v", err)
+			}
+
+			if err := os.Symlink("pkg", filepath.Join(src, "dirlink")); err != nil {
+				t.Fatalf("failed to create symlink: %v", err)
+			}
+
+			// The destination inside the source is not copied into itself.
+			dst := filepath.Join(src, "out")
+
+			// Let the temporary directory be removed.
+			t.Cleanup(func() {
+				os.Chmod(filepath.Join(src, "bin"), 0o700) //nolint:errcheck // Best effort.
+				os.Chmod(filepath.Join(dst, "bin"), 0o700) //nolint:errcheck // Best effort.
+			})
+
+			if err := gitignore.CopyDir(src, dst, newArchiveMatcher(t), tt.giveOpts...); err != nil {
+				t.Fatalf("CopyDir() error = %v", err)
+			}
+
+			none, err := gitignore.NewFromLines(nil)
+			if err != nil {
+				t.Fatalf("NewFromLines() error = %v", err)
+			}
+
+			got, err := none.ListIncluded(dst)
+			if err != nil {
+				t.Fatalf("ListIncluded() error = %v", err)
+			}
+
+			want := []string{"bin/run.sh", "keep.log", "link.go", "main.go", "pkg/lib.go"}
+			if tt.wantLink {
+				want = []string{"bin/run.sh", "dirlink", "keep.log", "link.go", "main.go", "pkg/lib.go"}
+			}
+
+			if !slices.Equal(got, want) {
+				t.Errorf("copied %q, want %q", got, want)
+			}
+
+			info, err := os.Lstat(filepath.Join(dst, "link.go"))
+			if err != nil {
+				t.Fatalf("Lstat() error = %v", err)
+			}
+
+			if isLink := info.Mode()&os.ModeSymlink != 0; isLink != tt.wantLink {
+				t.Errorf("link.go is a symlink = %v, want %v", isLink, tt.wantLink)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dst, "link.go"))
+			if err != nil || string(data) != "package main\n" {
+				t.Errorf("link.go = %q, %v, want %q", data, err, "package main\n")
+			}
+
+			for name, want := range map[string]os.FileMode{"bin": 0o555, "bin/run.sh": 0o755} {
+				info, err := os.Stat(filepath.Join(dst, filepath.FromSlash(name)))
+				if err != nil {
+					t.Fatalf("Stat() error = %v", err)
+				}
+
+				if got := info.Mode().Perm(); got != want {
+					t.Errorf("mode of %s = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCopyDir_Error(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	err := gitignore.CopyDir(filepath.Join(root, "missing"), filepath.Join(root, "dst"), nil)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("CopyDir() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
@@ -0,0 +1,194 @@
+package gitignore_test
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/conformance"
+)
+
+//nolint:gochecknoglobals // Test flag.
+var corpusUpdate = flag.Bool("corpus.update", false, "record the decisions of the golden corpus with git")
+
+// corpusDir is the absolute path of the golden corpus, resolved before any test
+// runs, as some tests change the working directory.
+//
+//nolint:gochecknoglobals // Resolved once at startup.
+var corpusDir, _ = filepath.Abs(filepath.Join("testdata", "corpus"))
+
+// corpusDivergences lists the paths of the golden corpus where this package is
+// known to disagree with git, and why, keyed by corpus and path.
+//
+//nolint:gochecknoglobals // Read-only test table.
+var corpusDivergences = map[string]string{
+	"prometheus-mantine-ui:.vscode/":                     `".vscode/*" matches the directory itself`,
+	"prometheus-mantine-ui:.vscode/extensions.json":      `".vscode/*" matches the directory itself, which cannot be re-included`,
+	"prometheus-mantine-ui:.main.tsx.swp":                `"?" in "*.sw?" is matched literally`,
+	"prometheus-mantine-ui:.main.tsx.swo":                `"?" in "*.sw?" is matched literally`,
+	"kubernetes:.drone.sec":                              `escaped dots in "!\\.drone\\.sec" are not unescaped`,
+	"kubernetes:vendor/github.com/go-openapi/.drone.sec": `escaped dots in "!\\.drone\\.sec" are not unescaped`,
+}
+
+// goldenEntry is a path of the golden corpus and the decision git made for it.
+type goldenEntry struct {
+	path    string
+	ignored bool
+}
+
+func TestCorpus(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob(filepath.Join(corpusDir, "*.gitignore"))
+	if err != nil {
+		t.Fatalf("failed to list corpus: %v", err)
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".gitignore")
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("failed to read rules: %v", err)
+			}
+
+			var (
+				rules  = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+				golden = strings.TrimSuffix(file, ".gitignore") + ".golden"
+			)
+
+			header, entries := readGolden(t, golden)
+
+			if *corpusUpdate {
+				entries = recordGolden(t, rules, entries)
+				writeGolden(t, golden, header, entries)
+			}
+
+			matcher, err := gitignore.NewFromLines(rules)
+			if err != nil {
+				t.Fatalf("NewFromLines() unexpected error: %v", err)
+			}
+
+			for _, entry := range entries {
+				var (
+					got           = gitignore.IsIgnored(matcher, strings.TrimSuffix(entry.path, "/"), strings.HasSuffix(entry.path, "/"))
+					reason, known = corpusDivergences[name+":"+entry.path]
+				)
+
+				switch {
+				case known && got == entry.ignored:
+					t.Errorf("%s now agrees with git, remove it from corpusDivergences (%s)", entry.path, reason)
+				case !known && got != entry.ignored:
+					t.Errorf("%s: ignored = %t, git ignored = %t", entry.path, got, entry.ignored)
+				}
+			}
+		})
+	}
+}
+
+// readGolden returns the leading comment lines and the entries of a golden
+// file.
+func readGolden(t testing.TB, name string) ([]string, []goldenEntry) {
+	t.Helper()
+
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("failed to open golden file: %v", err)
+	}
+	defer file.Close()
+
+	var (
+		header  = make([]string, 0)
+		entries = make([]goldenEntry, 0)
+		scanner = bufio.NewScanner(file)
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "#") {
+			header = append(header, line)
+
+			continue
+		}
+
+		decision, path, ok := strings.Cut(line, "\t")
+		if !ok || (decision != "ignored" && decision != "included") {
+			t.Fatalf("malformed golden line %q", line)
+		}
+
+		entries = append(entries, goldenEntry{path: path, ignored: decision == "ignored"})
+	}
+
+	if err = scanner.Err(); err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	return header, entries
+}
+
+// recordGolden returns entries with the decisions git makes for them.
+func recordGolden(t *testing.T, rules []string, entries []goldenEntry) []goldenEntry {
+	t.Helper()
+
+	c := conformance.Case{
+		Rules: rules,
+		Paths: make([]string, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		c.Paths = append(c.Paths, entry.path)
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	if err := conformance.Materialize(ctx, dir, c); err != nil {
+		t.Fatalf("Materialize() unexpected error: %v", err)
+	}
+
+	results, err := conformance.CheckIgnore(ctx, dir, c.Paths)
+	if err != nil {
+		t.Fatalf("CheckIgnore() unexpected error: %v", err)
+	}
+
+	recorded := make([]goldenEntry, 0, len(results))
+
+	for i, result := range results {
+		recorded = append(recorded, goldenEntry{path: entries[i].path, ignored: result.Ignored})
+	}
+
+	return recorded
+}
+
+// writeGolden writes header and entries to a golden file.
+func writeGolden(t *testing.T, name string, header []string, entries []goldenEntry) {
+	t.Helper()
+
+	var b strings.Builder
+
+	for _, line := range header {
+		b.WriteString(line + "\n")
+	}
+
+	for _, entry := range entries {
+		decision := "included"
+		if entry.ignored {
+			decision = "ignored"
+		}
+
+		b.WriteString(decision + "\t" + entry.path + "\n")
+	}
+
+	if err := os.WriteFile(name, []byte(b.String()), 0o600); err != nil {
+		t.Fatalf("failed to write golden file: %v", err)
+	}
+}
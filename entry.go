@@ -0,0 +1,26 @@
+package gitignore
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+)
+
+// MatchEntry reports whether the entry d of the directory parent is ignored,
+// so callbacks of [fs.WalkDir] and [filepath.WalkDir] can pass entries as is.
+// Whether the entry is a directory is taken from d, without an extra call to
+// Stat. The parent is relative to the directory the rules apply to and may use
+// the separator of the operating system; use "." or "" for the root.
+func (f *File) MatchEntry(parent string, d fs.DirEntry) bool {
+	return f.MatchIsDir(path.Join(filepath.ToSlash(parent), d.Name()), d.IsDir())
+}
+
+// MatchInfo reports whether the file at path, described by info, is ignored,
+// for callbacks of [filepath.Walk] and code holding the result of [os.Lstat].
+// Whether the file is a directory is taken from info. Like git, which never
+// follows symbolic links, a symbolic link described by [os.Lstat] is matched as
+// a file even when it points to a directory; one described by [os.Stat] is
+// matched as its target.
+func (f *File) MatchInfo(path string, info fs.FileInfo) bool {
+	return f.MatchIsDir(filepath.ToSlash(path), info.IsDir())
+}
@@ -0,0 +1,106 @@
+package gitignore_test
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFile_MatchEntry(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"main.go":          {},
+		"build":            {},
+		"src/build/out":    {},
+		"src/cache/a.bin":  {},
+		"src/cache.txt":    {},
+		"src/debug.log":    {},
+		"docs/build/index": {},
+		"logs":             {},
+		"var/logs/app":     {},
+	}
+
+	file := newTestMatcher(t, "cache/", "*.log", "/docs/build/", "build", "logs/")
+
+	var ignored []string
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || name == "." {
+			return err
+		}
+
+		if file.MatchEntry(path.Dir(name), d) {
+			ignored = append(ignored, name)
+
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+
+	want := []string{"build", "docs/build", "src/build", "src/cache", "src/debug.log", "var/logs"}
+	if !slices.Equal(ignored, want) {
+		t.Errorf("ignored = %q, want %q", ignored, want)
+	}
+}
+
+func TestFile_MatchInfo(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, name := range []string{"build", "src/vendor", "data"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o700); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+	}
+
+	for _, name := range []string{"main.go", "vendor", "debug.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+
+	if err := os.Symlink(filepath.Join(dir, "data"), filepath.Join(dir, "link")); err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+
+	file := newTestMatcher(t, "build/", "vendor/", "*.log", "link/")
+
+	var ignored []string
+
+	err := filepath.Walk(dir, func(name string, info fs.FileInfo, err error) error {
+		if err != nil || name == dir {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+
+		if file.MatchInfo(rel, info) {
+			ignored = append(ignored, filepath.ToSlash(rel))
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	want := []string{"build", "debug.log", "src/vendor"}
+	if !slices.Equal(ignored, want) {
+		t.Errorf("ignored = %q, want %q", ignored, want)
+	}
+}
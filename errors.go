@@ -0,0 +1,126 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+)
+
+// ParseError describes a line of a .gitignore file that could not be parsed.
+// It is returned by New, NewFromFS and NewFromLines, as well as for nested
+// .gitignore files read while scanning a tree, and can be retrieved with
+// errors.As, allowing editors and linters to point at the offending line.
+type ParseError struct {
+	// Err is the underlying cause, which wraps ErrRegexCompile, ErrStrict for
+	// the lines rejected with WithStrict, or ErrLimitExceeded for the limits
+	// set with WithLimits.
+	Err error
+
+	// Source is the path of the .gitignore file, or empty if the rules were
+	// not read from a file.
+	Source string
+
+	// Text is the offending line, as read.
+	Text string
+
+	// Pattern is the offending rule, including its leading "!" if negated,
+	// without surrounding spaces.
+	Pattern string
+
+	// Line is the 1-based line number of the offending line.
+	Line int
+
+	// Column is the 1-based column of the offending part of the line, or of
+	// the start of the pattern when it cannot be located.
+	Column int
+}
+
+// Error implements the error interface, using the usual SOURCE:LINE:COLUMN
+// prefix of compiler diagnostics.
+func (e *ParseError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("%d:%d: %q: %v", e.Line, e.Column, e.Text, e.Err)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %q: %v", e.Source, e.Line, e.Column, e.Text, e.Err)
+}
+
+// Unwrap returns the underlying cause.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors returns the parse errors reported in err, in line order, such as
+// every line skipped by a File created with WithPermissive, so callers can
+// report them without unwrapping the joined error themselves. It returns nil if
+// err does not hold any *ParseError.
+func ParseErrors(err error) []*ParseError {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint // Collecting every joined error.
+		var errs []*ParseError
+
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, ParseErrors(e)...)
+		}
+
+		return errs
+	}
+
+	var parseErr *ParseError
+
+	if !errors.As(err, &parseErr) {
+		return nil
+	}
+
+	return []*ParseError{parseErr}
+}
+
+// parseError converts an error returned by the pattern parser into the error
+// returned to callers, attributing it to source. Joined errors, returned in
+// permissive mode, are converted one by one.
+func parseError(source string, err error) error {
+	// Only the outermost error is inspected, as the causes of a single error
+	// may also wrap several errors.
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint // See above.
+		errs := joined.Unwrap()
+		converted := make([]error, 0, len(errs))
+
+		for _, e := range errs {
+			converted = append(converted, parseError(source, e))
+		}
+
+		return errors.Join(converted...)
+	}
+
+	var patternErr *pattern.Error
+
+	if !errors.As(err, &patternErr) {
+		return fmt.Errorf("%w", err)
+	}
+
+	var cause error
+
+	switch {
+	case errors.Is(patternErr.Err, pattern.ErrInvalidRegex):
+		cause = ErrRegexCompile
+	case errors.Is(patternErr.Err, pattern.ErrTooManyPatterns),
+		errors.Is(patternErr.Err, pattern.ErrLineTooLong),
+		errors.Is(patternErr.Err, pattern.ErrRegexTooLarge):
+		cause = ErrLimitExceeded
+	default:
+		cause = ErrStrict
+	}
+
+	return &ParseError{
+		Err:     fmt.Errorf("%w: %w", cause, patternErr.Err),
+		Source:  source,
+		Text:    patternErr.Text,
+		Pattern: patternErr.Pattern,
+		Line:    patternErr.Line,
+		Column:  patternErr.Column,
+	}
+}
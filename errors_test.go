@@ -0,0 +1,210 @@
+package gitignore_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp/syntax"
+	"slices"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveLines   []string
+		wantText    string
+		wantPattern string
+		wantLine    int
+		wantColumn  int
+		wantError   string
+	}{
+		{
+			name:        "Unclosed character class",
+			giveLines:   []string{"*.log", "[invalid-regex"},
+			wantText:    "[invalid-regex",
+			wantPattern: "[invalid-regex",
+			wantLine:    2,
+			wantColumn:  1,
+			wantError:   `2:1: "[invalid-regex": failed to compile regex: invalid regex: error parsing regexp: missing closing ]: ` + "`[invalid-regex(|/.*)$`",
+		},
+		{
+			name:        "Invalid range after prefix",
+			giveLines:   []string{"# comment", "", "build-[z-a]"},
+			wantText:    "build-[z-a]",
+			wantPattern: "build-[z-a]",
+			wantLine:    3,
+			wantColumn:  8,
+			wantError:   `3:8: "build-[z-a]": failed to compile regex: invalid regex: error parsing regexp: invalid character class range: ` + "`z-a`",
+		},
+		{
+			name:        "Leading spaces",
+			giveLines:   []string{"  [invalid"},
+			wantText:    "  [invalid",
+			wantPattern: "[invalid",
+			wantLine:    1,
+			wantColumn:  3,
+			wantError:   `1:3: "  [invalid": failed to compile regex: invalid regex: error parsing regexp: missing closing ]: ` + "`[invalid(|/.*)$`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := gitignore.NewFromLines(tt.giveLines)
+
+			var parseErr *gitignore.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("NewFromLines(%q) error = %v, want *ParseError", tt.giveLines, err)
+			}
+
+			if parseErr.Source != "" {
+				t.Errorf("Source = %q, want empty", parseErr.Source)
+			}
+
+			if parseErr.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", parseErr.Text, tt.wantText)
+			}
+
+			if parseErr.Pattern != tt.wantPattern {
+				t.Errorf("Pattern = %q, want %q", parseErr.Pattern, tt.wantPattern)
+			}
+
+			if parseErr.Line != tt.wantLine {
+				t.Errorf("Line = %d, want %d", parseErr.Line, tt.wantLine)
+			}
+
+			if parseErr.Column != tt.wantColumn {
+				t.Errorf("Column = %d, want %d", parseErr.Column, tt.wantColumn)
+			}
+
+			if got := err.Error(); got != tt.wantError {
+				t.Errorf("Error() = %q, want %q", got, tt.wantError)
+			}
+
+			if !errors.Is(err, gitignore.ErrRegexCompile) {
+				t.Errorf("errors.Is(err, ErrRegexCompile) = false, want true")
+			}
+
+			var syntaxErr *syntax.Error
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("errors.As(err, *syntax.Error) = false, want true")
+			}
+		})
+	}
+}
+
+func TestParseError_Source(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log\n\n[invalid\n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	_, err := gitignore.New(path)
+
+	var parseErr *gitignore.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("New(%q) error = %v, want *ParseError", path, err)
+	}
+
+	if parseErr.Source != path {
+		t.Errorf("Source = %q, want %q", parseErr.Source, path)
+	}
+
+	if parseErr.Line != 3 {
+		t.Errorf("Line = %d, want 3", parseErr.Line)
+	}
+
+	want := path + `:3:1: "[invalid": failed to compile regex: invalid regex: error parsing regexp: missing closing ]: ` + "`[invalid(|/.*)$`"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveErr      func(t *testing.T) error
+		wantPatterns []string
+	}{
+		{
+			name: "Permissive",
+			giveErr: func(t *testing.T) error {
+				t.Helper()
+
+				_, err := gitignore.NewFromLines([]string{"[z-a]", "*.log", "foo[", "  [bar  "}, gitignore.WithPermissive())
+
+				return err
+			},
+			wantPatterns: []string{"[z-a]", "foo[", "[bar"},
+		},
+		{
+			name: "Strict",
+			giveErr: func(t *testing.T) error {
+				t.Helper()
+
+				_, err := gitignore.NewFromLines([]string{"*.log", "foo[", "[bar"})
+
+				return err
+			},
+			wantPatterns: []string{"foo["},
+		},
+		{
+			name: "Wrapped",
+			giveErr: func(t *testing.T) error {
+				t.Helper()
+
+				_, err := gitignore.NewFromLines([]string{"foo["})
+
+				return fmt.Errorf("loading rules: %w", err)
+			},
+			wantPatterns: []string{"foo["},
+		},
+		{
+			name: "No error",
+			giveErr: func(t *testing.T) error {
+				t.Helper()
+
+				_, err := gitignore.NewFromLines([]string{"*.log"}, gitignore.WithPermissive())
+
+				return err
+			},
+			wantPatterns: nil,
+		},
+		{
+			name: "Other error",
+			giveErr: func(_ *testing.T) error {
+				return os.ErrNotExist
+			},
+			wantPatterns: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			errs := gitignore.ParseErrors(tt.giveErr(t))
+
+			var patterns []string
+			for _, e := range errs {
+				patterns = append(patterns, e.Pattern)
+			}
+
+			if !slices.Equal(patterns, tt.wantPatterns) {
+				t.Errorf("ParseErrors() patterns = %q, want %q", patterns, tt.wantPatterns)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package gitignore_test
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+// bucket stands in for the client of a cloud storage service, such as an S3
+// uploader or a GCS bucket handle.
+type bucket struct{}
+
+// uploadBatch uploads a batch of objects in a single round trip.
+func (*bucket) uploadBatch(keys []string) {
+	fmt.Println("uploading", keys)
+}
+
+// This example shows how a sync tool can walk a tree, prune ignored
+// directories, and upload the remaining files in batches under a key prefix.
+func ExampleShouldUpload() {
+	matcher, err := gitignore.NewFromLines([]string{
+		"*.log",
+		"node_modules/",
+		".env",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		".env":                       {Data: []byte("SECRET=hunter2")},
+		"index.html":                 {Data: []byte("<h1>Hello</h1>")},
+		"app.js":                     {Data: []byte("console.log('hi')")},
+		"debug.log":                  {Data: []byte("debug")},
+		"node_modules/left-pad/a.js": {Data: []byte("module.exports = {}")},
+		"static/style.css":           {Data: []byte("body {}")},
+		"static/logo.svg":            {Data: []byte("<svg/>")},
+	}
+
+	const (
+		batchSize = 2
+		prefix    = "releases/v1.2.3"
+	)
+
+	var (
+		dst   = &bucket{}
+		batch = make([]string, 0, batchSize)
+	)
+
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		// Returning the error prunes ignored directories, so files inside
+		// node_modules are never visited.
+		upload, err := gitignore.ShouldUpload(matcher, name, info)
+		if !upload {
+			return err
+		}
+
+		batch = append(batch, path.Join(prefix, name))
+
+		if len(batch) == batchSize {
+			dst.uploadBatch(batch)
+
+			batch = batch[:0]
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(batch) > 0 {
+		dst.uploadBatch(batch)
+	}
+
+	// Output:
+	// uploading [releases/v1.2.3/app.js releases/v1.2.3/index.html]
+	// uploading [releases/v1.2.3/static/logo.svg releases/v1.2.3/static/style.css]
+}
@@ -0,0 +1,94 @@
+package gitignore
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Step is a rule consulted while evaluating a path, as reported by Explain.
+type Step struct {
+	// Pattern describes the rule.
+	Pattern PatternInfo
+
+	// Matched reports whether the rule matches the path.
+	Matched bool
+}
+
+// Explanation is the evaluation trace of a path, as returned by Explain.
+type Explanation struct {
+	// Path is the path, as given to Explain.
+	Path string
+
+	// Steps lists every rule consulted, in file order, followed by the rule
+	// set with WithGitDirIgnored, if any.
+	Steps []Step
+
+	// Result is the final decision. It is only set if Matched is true.
+	Result Result
+
+	// Matched reports whether any rule matches the path. If not, the path is
+	// not ignored.
+	Matched bool
+}
+
+// Explain evaluates path against every rule of f and returns the full trace,
+// for debugging complicated rule sets.
+func (f *File) Explain(path string) Explanation {
+	var (
+		clean = f.clean(path)
+		steps = make([]Step, 0, len(f.patterns)+1)
+	)
+
+	for _, pat := range f.patterns {
+		steps = append(steps, Step{
+			Pattern: f.info(pat),
+			Matched: pat.Match(clean),
+		})
+	}
+
+	// The rule ignoring git directories takes precedence over every other
+	// one, as if it came last.
+	if f.gitDir != nil {
+		steps = append(steps, Step{
+			Pattern: f.info(f.gitDir),
+			Matched: f.gitDir.Match(clean),
+		})
+	}
+
+	result, matched := f.MatchResult(path)
+
+	return Explanation{
+		Path:    path,
+		Steps:   steps,
+		Result:  result,
+		Matched: matched,
+	}
+}
+
+// Ignored reports whether the path is ignored.
+func (e Explanation) Ignored() bool {
+	return e.Matched && e.Result.Ignored
+}
+
+// String returns the explanation in the format of git check-ignore -v -n:
+// the source, line number and text of the deciding rule, a tab, and the path,
+// like ".gitignore:3:*.log\tdebug.log". Negated rules are reported as well,
+// and paths no rule matches are reported as "::\tpath". The source is empty
+// for a File created from lines.
+func (e Explanation) String() string {
+	if !e.Matched {
+		return "::\t" + e.Path
+	}
+
+	var b strings.Builder
+
+	b.WriteString(e.Result.Pattern.Source)
+	b.WriteByte(':')
+	b.WriteString(strconv.Itoa(e.Result.Pattern.Line))
+	b.WriteByte(':')
+	b.WriteString(e.Result.Pattern.Raw)
+	b.WriteByte('\t')
+	b.WriteString(e.Path)
+
+	return b.String()
+}
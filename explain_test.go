@@ -0,0 +1,98 @@
+package gitignore_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestFile_Explain(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		".gitignore": {Data: []byte("# logs\n*.log\n!keep.log\nlogs/\n")},
+	}
+
+	file, err := gitignore.NewFromFS(fsys, ".gitignore")
+	if err != nil {
+		t.Fatalf("NewFromFS() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		givePath    string
+		wantString  string
+		wantMatched []bool
+		wantIgnored bool
+	}{
+		{
+			name:        "Ignored",
+			givePath:    "debug.log",
+			wantString:  ".gitignore:2:*.log\tdebug.log",
+			wantMatched: []bool{true, false, false},
+			wantIgnored: true,
+		},
+		{
+			name:        "Re-included",
+			givePath:    "keep.log",
+			wantString:  ".gitignore:3:!keep.log\tkeep.log",
+			wantMatched: []bool{true, true, false},
+			wantIgnored: false,
+		},
+		{
+			name:        "Several matching rules",
+			givePath:    "logs/app.log",
+			wantString:  ".gitignore:4:logs/\tlogs/app.log",
+			wantMatched: []bool{true, false, true},
+			wantIgnored: true,
+		},
+		{
+			name:        "No matching rule",
+			givePath:    "main.go",
+			wantString:  "::\tmain.go",
+			wantMatched: []bool{false, false, false},
+			wantIgnored: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := file.Explain(tt.givePath)
+
+			if s := got.String(); s != tt.wantString {
+				t.Errorf("Explain(%q).String() = %q, want %q", tt.givePath, s, tt.wantString)
+			}
+
+			if got.Ignored() != tt.wantIgnored || got.Ignored() != file.Match(tt.givePath) {
+				t.Errorf("Explain(%q).Ignored() = %v, want %v", tt.givePath, got.Ignored(), tt.wantIgnored)
+			}
+
+			if len(got.Steps) != len(tt.wantMatched) {
+				t.Fatalf("Explain(%q) has %d steps, want %d", tt.givePath, len(got.Steps), len(tt.wantMatched))
+			}
+
+			for i, step := range got.Steps {
+				if step.Matched != tt.wantMatched[i] {
+					t.Errorf("Explain(%q).Steps[%d] (%s) matched = %v, want %v",
+						tt.givePath, i, step.Pattern.Raw, step.Matched, tt.wantMatched[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFile_Explain_FromLines(t *testing.T) {
+	t.Parallel()
+
+	file, err := gitignore.NewFromLines([]string{"*.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	if got, want := file.Explain("debug.log").String(), ":1:*.log\tdebug.log"; got != want {
+		t.Errorf("Explain().String() = %q, want %q", got, want)
+	}
+}
@@ -1,9 +1,13 @@
 package gitignore
 
 import (
-	"errors"
+	"crypto/sha256"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 
 	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
@@ -11,70 +15,397 @@ import (
 	"git.sr.ht/~jamesponddotco/xstd-go/xstrings"
 )
 
-// ErrRegexCompile is returned when an error occurs while compiling regular
-// expressions when parsing a .gitignore file.
-const ErrRegexCompile xerrors.Error = "failed to compile regex"
+const (
+	// ErrRegexCompile is returned when an error occurs while compiling regular
+	// expressions when parsing a .gitignore file.
+	ErrRegexCompile xerrors.Error = "failed to compile regex"
+
+	// ErrNoBaseDir is returned when matching an absolute path against a File
+	// without a base directory.
+	ErrNoBaseDir xerrors.Error = "no base directory to resolve the path against"
+)
 
 // File provides the functionality to match paths against gitignore rules.
 type File struct {
-	patterns []*pattern.Pattern
+	normalize     func(path string) string
+	source        string
+	base          string
+	prefix        string
+	patterns      []*pattern.Pattern
+	skipped       pattern.Counts
+	prenormalized bool
+
+	// gitDir is the pattern ignoring git directories, set with
+	// WithGitDirIgnored, which takes precedence over every rule.
+	gitDir *pattern.Pattern
+
+	// index looks up the rules matching a path. It must be rebuilt whenever
+	// patterns changes, and is nil for the zero File.
+	index *index
+
+	// opts holds the options f was created with, used to parse the rules
+	// added with AddLines. It is nil for a File not created by a
+	// constructor, such as one returned by Merge.
+	opts *options
 }
 
-// New creates a new File instance from a given .gitignore file givePath.
-func New(path string) (*File, error) {
+// New creates a new File instance from a given .gitignore file givePath. Lines
+// that cannot be parsed are reported with a *ParseError. Unless set with
+// WithBaseDir, the base directory of the File is the directory of path.
+func New(path string, opts ...Option) (*File, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
 	defer file.Close()
 
-	patterns, err := pattern.Parse(file)
-	if err != nil {
-		if errors.Is(err, pattern.ErrInvalidRegex) {
-			return nil, fmt.Errorf("%w: %w", ErrRegexCompile, err)
-		}
+	return parse(path, file, append([]Option{WithBaseDir(filepath.Dir(path))}, opts...))
+}
 
+// NewFromFS creates a new File instance from the .gitignore file at path in
+// fsys, such as an [embed.FS], a fstest.MapFS, or a zip archive opened with
+// [zip.NewReader]. The path follows the rules of [fs.ValidPath].
+func NewFromFS(fsys fs.FS, path string, opts ...Option) (*File, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
+	defer file.Close()
 
-	return &File{
-		patterns: patterns,
-	}, nil
+	return parse(path, file, opts)
 }
 
 // NewFromLines creates a new File instance from a list of strings.
-func NewFromLines(lines []string) (*File, error) {
+func NewFromLines(lines []string, opts ...Option) (*File, error) {
 	r := strings.NewReader(xstrings.JoinWithSeparator("\n", lines...))
 
-	patterns, err := pattern.Parse(r)
+	return parse("", r, opts)
+}
+
+// parse creates a new File from the rules read from r, attributing them to
+// source.
+func parse(source string, r io.Reader, opts []Option) (*File, error) {
+	o := newOptions(opts)
+
+	if o.source != "" {
+		source = o.source
+	}
+
+	var (
+		skipped pattern.Counts
+		parser  = o.parser(source)
+	)
+
+	parser.Counts = &skipped
+
+	patterns, err := parser.Parse(r)
 	if err != nil {
-		if errors.Is(err, pattern.ErrInvalidRegex) {
-			return nil, fmt.Errorf("%w: %w", ErrRegexCompile, err)
+		// In permissive mode, patterns are returned along with the errors.
+		err = parseError(source, err)
+		if patterns == nil {
+			return nil, err
 		}
+	}
 
-		return nil, fmt.Errorf("%w", err)
+	for _, pat := range patterns {
+		pat.Source = source
+	}
+
+	var gitDir *pattern.Pattern
+
+	if o.gitDir {
+		var gitDirErr error
+
+		if gitDir, gitDirErr = gitDirPattern(); gitDirErr != nil {
+			return nil, gitDirErr
+		}
 	}
 
 	return &File{
-		patterns: patterns,
-	}, nil
+		normalize:     o.normalize,
+		source:        source,
+		base:          o.baseDir,
+		patterns:      patterns,
+		skipped:       skipped,
+		prenormalized: o.prenormalized,
+		gitDir:        gitDir,
+		index:         newIndex(patterns),
+		opts:          o,
+	}, err
 }
 
 // Match checks if the given givePath matches any of the gitignore rules.
 func (f *File) Match(path string) bool {
-	path = strings.ReplaceAll(path, string(os.PathSeparator), "/")
+	ignored, _ := f.decide(path)
+
+	return ignored
+}
+
+// MatchAbsolute is like Match, but takes an absolute path, or one relative to
+// the current working directory, and resolves it against the base directory of
+// f, which is the directory of the .gitignore file for a File created with New
+// or the one set with WithBaseDir. A trailing separator marks a directory.
+//
+// It returns [ErrOutsideBase] if path is not within the base directory, and
+// [ErrNoBaseDir] if f has none.
+func (f *File) MatchAbsolute(path string) (bool, error) {
+	if f.base == "" {
+		return false, ErrNoBaseDir
+	}
 
-	var match bool
+	rel, err := RelPath(f.base, path)
+	if err != nil {
+		return false, err
+	}
+
+	if rel == "." {
+		return false, nil
+	}
+
+	return f.Match(rel), nil
+}
+
+// MatchIsDir is like Match, but takes whether path refers to a directory from
+// isDir rather than from a trailing slash, for callers that already know it,
+// such as walkers holding an [fs.DirEntry]. A trailing slash in path is
+// ignored.
+func (f *File) MatchIsDir(path string, isDir bool) bool {
+	path = strings.TrimSuffix(path, "/")
+
+	if isDir {
+		path += "/"
+	}
+
+	return f.Match(path)
+}
+
+// decide reports whether path is ignored and whether any pattern matched it at
+// all, so callers layering several rule sets can tell a path that is not
+// ignored apart from one the rules have no opinion on.
+func (f *File) decide(path string) (bool, bool) {
+	pat := f.deciding(path)
+	if pat == nil {
+		return false, false
+	}
+
+	return !pat.Negate, true
+}
+
+// deciding returns the pattern deciding whether path is ignored, or nil if no
+// pattern matches it. Like git, the last matching pattern decides, so patterns
+// of a later layer, which come after the ones of earlier layers, override their
+// decision.
+func (f *File) deciding(path string) *pattern.Pattern {
+	return f.decidingClean(f.clean(path))
+}
+
+// decidingClean is like deciding, for a path already cleaned.
+func (f *File) decidingClean(path string) *pattern.Pattern {
+	if f.gitDir != nil && f.gitDir.Match(path) {
+		return f.gitDir
+	}
+
+	// The wildcards of regular expressions do not match newlines, so
+	// literal rules are only looked up for paths without any.
+	if f.index != nil && !strings.Contains(path, "\n") {
+		return f.index.deciding(path)
+	}
+
+	for i := len(f.patterns) - 1; i >= 0; i-- {
+		if f.patterns[i].Match(path) {
+			return f.patterns[i]
+		}
+	}
+
+	return nil
+}
+
+// clean returns path in the form patterns are matched against.
+func (f *File) clean(path string) string {
+	if f.normalize != nil {
+		path = f.normalize(path)
+	}
+
+	if !f.prenormalized {
+		path = strings.ReplaceAll(path, string(os.PathSeparator), "/")
+	}
+
+	if f.prefix != "" {
+		path = f.prefix + "/" + path
+	}
+
+	return path
+}
+
+// Len returns the number of rules of f, not counting comments and blank lines.
+func (f *File) Len() int {
+	return len(f.patterns)
+}
+
+// IsEmpty reports whether f has no rules, in which case it matches no path
+// and callers may skip matching altogether. A File ignoring git directories
+// with WithGitDirIgnored is never empty.
+func (f *File) IsEmpty() bool {
+	return f.Len() == 0 && f.gitDir == nil
+}
+
+// Fingerprint returns the SHA-256 hash of the normalized rules of f, so caches
+// can be keyed by rule-set identity. Comments, blank lines, surrounding spaces,
+// line endings and the source path do not affect the fingerprint, while the
+// order of the rules does, as it changes their meaning. The fingerprint is
+// stable across processes and machines.
+func (f *File) Fingerprint() [sha256.Size]byte {
+	h := sha256.New()
 
 	for _, pat := range f.patterns {
-		if pat.Regex.MatchString(path) {
-			if pat.Negate {
-				return false
-			}
+		if pat.Prefix != "" {
+			// Re-anchored rules have another meaning.
+			h.Write([]byte(pat.Prefix + "\x00"))
+		}
 
-			match = true
+		if pat.IgnoreCase {
+			// Case-insensitive rules match more paths.
+			h.Write([]byte("\x00i"))
 		}
+
+		if pat.Wildmatch {
+			// The engines disagree on a few edge cases.
+			h.Write([]byte("\x00w"))
+		}
+
+		// Rules never contain a newline, so it safely separates them.
+		h.Write([]byte(pat.Raw))
+		h.Write([]byte{'\n'})
 	}
 
-	return match
+	if f.prefix != "" {
+		// A scoped File answers differently, so it must not share the
+		// fingerprint of the File it was derived from.
+		h.Write([]byte("\x00" + f.prefix))
+	}
+
+	if f.gitDir != nil {
+		h.Write([]byte("\x00" + gitDir))
+	}
+
+	var sum [sha256.Size]byte
+
+	h.Sum(sum[:0])
+
+	return sum
+}
+
+// Clone returns a deep copy of f, so a variant can be derived from it, such as
+// with AddLines or RemoveIf, without affecting the original, which may be in
+// use by other goroutines. Compiled regular expressions are immutable and
+// shared rather than recompiled.
+func (f *File) Clone() *File {
+	patterns := make([]*pattern.Pattern, 0, len(f.patterns))
+
+	for _, pat := range f.patterns {
+		clone := *pat
+		patterns = append(patterns, &clone)
+	}
+
+	return f.derive(patterns)
+}
+
+// derive returns a File with the given patterns and the same settings as f.
+func (f *File) derive(patterns []*pattern.Pattern) *File {
+	return &File{
+		normalize:     f.normalize,
+		source:        f.source,
+		base:          f.base,
+		prefix:        f.prefix,
+		patterns:      patterns,
+		skipped:       f.skipped,
+		prenormalized: f.prenormalized,
+		gitDir:        f.gitDir,
+		index:         newIndex(patterns),
+		opts:          f.opts,
+	}
+}
+
+// WithPrefix returns a File answering for paths relative to the subdirectory
+// dir of the directory f applies to, so callers working within a subdirectory
+// do not need to join every path with it. Matching a path p against the
+// returned File is equivalent to matching dir/p against f, so anchored rules
+// keep their meaning.
+//
+// Prefixes accumulate, so f.WithPrefix("a").WithPrefix("b") is equivalent to
+// f.WithPrefix("a/b"). An empty dir or "." returns a copy of f.
+func (f *File) WithPrefix(dir string) *File {
+	clone := f.Clone()
+
+	dir = strings.Trim(path.Clean("/"+filepath.ToSlash(dir)), "/")
+	if dir == "" {
+		return clone
+	}
+
+	if clone.base != "" {
+		clone.base = filepath.Join(clone.base, filepath.FromSlash(dir))
+	}
+
+	if clone.prefix != "" {
+		dir = clone.prefix + "/" + dir
+	}
+
+	clone.prefix = dir
+
+	return clone
+}
+
+// AddLines parses lines as additional rules and appends them to f, so a File
+// can be extended at runtime, such as with user-supplied excludes layered over
+// the rules of a repository. Added rules take precedence over existing ones
+// like later lines of a .gitignore file. They have no source, and their line
+// numbers count from 1 within lines.
+//
+// Lines are parsed with the options f was created with, such as
+// WithIgnoreCase and WithWildmatch, and the limits set with WithLimits apply
+// to the rules of f and the added ones together.
+//
+// If any line cannot be parsed, a *ParseError is returned and f is left
+// unchanged, unless f was created with WithPermissive, in which case the other
+// lines are added. AddLines must not be called concurrently with other methods
+// of f; use Clone to extend a File shared with other goroutines.
+func (f *File) AddLines(lines ...string) error {
+	o := f.opts
+	if o == nil {
+		o = newOptions(nil)
+	}
+
+	var (
+		skipped pattern.Counts
+		parser  = o.parser("")
+	)
+
+	parser.Counts = &skipped
+	parser.Held = len(f.patterns)
+
+	for _, pat := range f.patterns {
+		parser.HeldRegexSize += pat.RegexSize()
+	}
+
+	added, err := parser.Parse(strings.NewReader(xstrings.JoinWithSeparator("\n", lines...)))
+	if err != nil {
+		// In permissive mode, patterns are returned along with the errors.
+		err = parseError("", err)
+		if added == nil {
+			return err
+		}
+	}
+
+	if len(f.patterns) > 0 {
+		for _, pat := range added {
+			pat.Layer = f.patterns[len(f.patterns)-1].Layer
+		}
+	}
+
+	f.patterns = append(f.patterns, added...)
+	f.index = newIndex(f.patterns)
+	f.skipped.Comments += skipped.Comments
+	f.skipped.Blanks += skipped.Blanks
+
+	return err
 }
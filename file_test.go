@@ -1,10 +1,14 @@
 package gitignore_test
 
 import (
+	"encoding/hex"
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"testing/fstest"
 
 	"git.sr.ht/~jamesponddotco/gitignore-go"
 )
@@ -1264,3 +1268,725 @@ func TestFile_Match_Negation(t *testing.T) {
 		})
 	}
 }
+
+func TestFile_Len(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		giveRules      []string
+		wantLen        int
+		wantIsEmpty    bool
+		givePermissive bool
+	}{
+		{
+			name:        "No rules",
+			giveRules:   nil,
+			wantLen:     0,
+			wantIsEmpty: true,
+		},
+		{
+			name:        "Only comments and blank lines",
+			giveRules:   []string{"# comment", "", "   ", "# another"},
+			wantLen:     0,
+			wantIsEmpty: true,
+		},
+		{
+			name:        "Rules and negations",
+			giveRules:   []string{"# comment", "*.log", "", "!keep.log", "build/"},
+			wantLen:     3,
+			wantIsEmpty: false,
+		},
+		{
+			name:           "Skipped invalid rules",
+			giveRules:      []string{"[invalid", "*.log"},
+			givePermissive: true,
+			wantLen:        1,
+			wantIsEmpty:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []gitignore.Option
+			if tt.givePermissive {
+				opts = append(opts, gitignore.WithPermissive())
+			}
+
+			file, _ := gitignore.NewFromLines(tt.giveRules, opts...)
+			if file == nil {
+				t.Fatal("NewFromLines() = nil file")
+			}
+
+			if got := file.Len(); got != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", got, tt.wantLen)
+			}
+
+			if got := file.IsEmpty(); got != tt.wantIsEmpty {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.wantIsEmpty)
+			}
+		})
+	}
+}
+
+func TestFile_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	base := []string{"*.log", "!keep.log", "build/"}
+
+	tests := []struct {
+		name      string
+		giveRules []string
+		wantSame  bool
+	}{
+		{
+			name:      "Identical rules",
+			giveRules: []string{"*.log", "!keep.log", "build/"},
+			wantSame:  true,
+		},
+		{
+			name:      "Comments, blank lines and spaces",
+			giveRules: []string{"# logs", "  *.log", "", "!keep.log\r", "build/", "# end"},
+			wantSame:  true,
+		},
+		{
+			name:      "Reordered rules",
+			giveRules: []string{"!keep.log", "*.log", "build/"},
+			wantSame:  false,
+		},
+		{
+			name:      "Changed rule",
+			giveRules: []string{"*.log", "!keep.log", "dist/"},
+			wantSame:  false,
+		},
+		{
+			name:      "Rules joined differently",
+			giveRules: []string{"*.log!keep.log", "build/"},
+			wantSame:  false,
+		},
+		{
+			name:      "Missing rule",
+			giveRules: []string{"*.log", "!keep.log"},
+			wantSame:  false,
+		},
+	}
+
+	want, err := gitignore.NewFromLines(base)
+	if err != nil {
+		t.Fatalf("NewFromLines(%q) error = %v", base, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := gitignore.NewFromLines(tt.giveRules)
+			if err != nil {
+				t.Fatalf("NewFromLines(%q) error = %v", tt.giveRules, err)
+			}
+
+			if got := file.Fingerprint() == want.Fingerprint(); got != tt.wantSame {
+				t.Errorf("Fingerprint() equal = %v, want %v", got, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestFile_Fingerprint_Source(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log\n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	fromFile, err := gitignore.New(path)
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", path, err)
+	}
+
+	fromLines, err := gitignore.NewFromLines([]string{"*.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	if fromFile.Fingerprint() != fromLines.Fingerprint() {
+		t.Error("Fingerprint() differs between a file and lines with the same rules")
+	}
+
+	// The fingerprint must not change across releases, as it may be persisted.
+	const want = "318d9a16533732a69cda7bb7b174ee392fdd15be3d72a114cd8f2d51f3eab510"
+
+	sum := fromLines.Fingerprint()
+	if got := hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("Fingerprint() = %s, want %s", got, want)
+	}
+}
+
+func TestFile_Clone(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log\n!keep.log\n/build/\n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	file, err := gitignore.New(path)
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", path, err)
+	}
+
+	clone := file.Clone()
+	if clone == file {
+		t.Fatal("Clone() returned the same File")
+	}
+
+	if got, want := clone.String(), file.String(); got != want {
+		t.Errorf("Clone().String() = %q, want %q", got, want)
+	}
+
+	if !slices.Equal(clone.Patterns(), file.Patterns()) {
+		t.Errorf("Clone().Patterns() = %v, want %v", clone.Patterns(), file.Patterns())
+	}
+
+	for _, path := range []string{"debug.log", "keep.log", "build/out", "src/build/out"} {
+		if got, want := clone.Match(path), file.Match(path); got != want {
+			t.Errorf("Clone().Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestFile_WithPrefix(t *testing.T) {
+	t.Parallel()
+
+	rules := []string{
+		"*.log",
+		"!keep.log",
+		"/build/",
+		"/pkg/api/gen/",
+		"docs/*.html",
+		"**/testdata/large",
+	}
+
+	tests := []struct {
+		name       string
+		givePrefix []string
+		givePath   string
+		wantMatch  bool
+	}{
+		{
+			name:       "Unanchored rule",
+			givePrefix: []string{"pkg/api"},
+			givePath:   "debug.log",
+			wantMatch:  true,
+		},
+		{
+			name:       "Negation",
+			givePrefix: []string{"pkg/api"},
+			givePath:   "keep.log",
+			wantMatch:  false,
+		},
+		{
+			name:       "Root-anchored rule does not match in subdirectory",
+			givePrefix: []string{"pkg"},
+			givePath:   "build/out",
+			wantMatch:  false,
+		},
+		{
+			name:       "Anchored rule rewritten relative to prefix",
+			givePrefix: []string{"pkg/api"},
+			givePath:   "gen/types.go",
+			wantMatch:  true,
+		},
+		{
+			name:       "Anchored rule from another subdirectory",
+			givePrefix: []string{"pkg/web"},
+			givePath:   "gen/types.go",
+			wantMatch:  false,
+		},
+		{
+			name:       "Middle slash rule",
+			givePrefix: []string{"docs"},
+			givePath:   "index.html",
+			wantMatch:  true,
+		},
+		{
+			name:       "Leading double star",
+			givePrefix: []string{"pkg"},
+			givePath:   "api/testdata/large",
+			wantMatch:  true,
+		},
+		{
+			name:       "Accumulated prefixes",
+			givePrefix: []string{"pkg", "api"},
+			givePath:   "gen/types.go",
+			wantMatch:  true,
+		},
+		{
+			name:       "Prefix with leading dot and trailing slash",
+			givePrefix: []string{"./pkg/api/"},
+			givePath:   "gen/types.go",
+			wantMatch:  true,
+		},
+		{
+			name:       "Empty prefix",
+			givePrefix: []string{""},
+			givePath:   "build/out",
+			wantMatch:  true,
+		},
+		{
+			name:       "Dot prefix",
+			givePrefix: []string{"."},
+			givePath:   "build/out",
+			wantMatch:  true,
+		},
+	}
+
+	file, err := gitignore.NewFromLines(rules)
+	if err != nil {
+		t.Fatalf("NewFromLines(%q) error = %v", rules, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scoped := file
+			for _, prefix := range tt.givePrefix {
+				scoped = scoped.WithPrefix(prefix)
+			}
+
+			if got := scoped.Match(tt.givePath); got != tt.wantMatch {
+				t.Errorf("WithPrefix(%q).Match(%q) = %v, want %v", tt.givePrefix, tt.givePath, got, tt.wantMatch)
+			}
+		})
+	}
+
+	if !file.Match("build/out") {
+		t.Error("WithPrefix() modified the original File")
+	}
+
+	if file.WithPrefix("pkg").Fingerprint() == file.Fingerprint() {
+		t.Error("WithPrefix().Fingerprint() = Fingerprint(), want different fingerprints")
+	}
+}
+
+func TestNewFromFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n!keep.log\n")},
+		"sub/.gitignore": {Data: []byte("[invalid\n/dist/\n")},
+	}
+
+	tests := []struct {
+		name           string
+		givePath       string
+		giveOpts       []gitignore.Option
+		wantErr        error
+		wantMatch      string
+		wantParseError bool
+	}{
+		{
+			name:      "Root file",
+			givePath:  ".gitignore",
+			wantMatch: "debug.log",
+		},
+		{
+			name:           "Invalid pattern",
+			givePath:       "sub/.gitignore",
+			wantErr:        gitignore.ErrRegexCompile,
+			wantParseError: true,
+		},
+		{
+			name:           "Invalid pattern in permissive mode",
+			givePath:       "sub/.gitignore",
+			giveOpts:       []gitignore.Option{gitignore.WithPermissive()},
+			wantErr:        gitignore.ErrRegexCompile,
+			wantMatch:      "dist/app.js",
+			wantParseError: true,
+		},
+		{
+			name:     "Missing file",
+			givePath: "missing/.gitignore",
+			wantErr:  fs.ErrNotExist,
+		},
+		{
+			name:     "Invalid path",
+			givePath: "/.gitignore",
+			wantErr:  fs.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := gitignore.NewFromFS(fsys, tt.givePath, tt.giveOpts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewFromFS(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+
+			var parseErr *gitignore.ParseError
+			if errors.As(err, &parseErr) != tt.wantParseError {
+				t.Fatalf("NewFromFS(%q) error = %v, want *ParseError: %v", tt.givePath, err, tt.wantParseError)
+			}
+
+			if tt.wantParseError && parseErr.Source != tt.givePath {
+				t.Errorf("ParseError.Source = %q, want %q", parseErr.Source, tt.givePath)
+			}
+
+			if tt.wantMatch == "" {
+				return
+			}
+
+			if !file.Match(tt.wantMatch) {
+				t.Errorf("Match(%q) = false, want true", tt.wantMatch)
+			}
+
+			if info := file.Patterns()[0]; info.Source != tt.givePath {
+				t.Errorf("Patterns()[0].Source = %q, want %q", info.Source, tt.givePath)
+			}
+		})
+	}
+}
+
+func TestFile_MatchIsDir(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		givePath  string
+		giveIsDir bool
+		wantMatch bool
+	}{
+		{
+			name:      "Directory rule on directory",
+			givePath:  "build",
+			giveIsDir: true,
+			wantMatch: true,
+		},
+		{
+			name:      "Directory rule on file",
+			givePath:  "build",
+			giveIsDir: false,
+			wantMatch: false,
+		},
+		{
+			name:      "Directory rule on file with misleading trailing slash",
+			givePath:  "build/",
+			giveIsDir: false,
+			wantMatch: false,
+		},
+		{
+			name:      "Directory rule on directory with trailing slash",
+			givePath:  "build/",
+			giveIsDir: true,
+			wantMatch: true,
+		},
+		{
+			name:      "Nested directory",
+			givePath:  "src/cache",
+			giveIsDir: true,
+			wantMatch: true,
+		},
+		{
+			name:      "File rule on file",
+			givePath:  "debug.log",
+			giveIsDir: false,
+			wantMatch: true,
+		},
+		{
+			name:      "File rule on directory",
+			givePath:  "logs.log",
+			giveIsDir: true,
+			wantMatch: true,
+		},
+		{
+			name:      "Unmatched directory",
+			givePath:  "src",
+			giveIsDir: true,
+			wantMatch: false,
+		},
+	}
+
+	file, err := gitignore.NewFromLines([]string{"build/", "cache/", "*.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := file.MatchIsDir(tt.givePath, tt.giveIsDir); got != tt.wantMatch {
+				t.Errorf("MatchIsDir(%q, %v) = %v, want %v", tt.givePath, tt.giveIsDir, got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestFile_AddLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log\nbuild/\n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	file, err := gitignore.New(path)
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", path, err)
+	}
+
+	base := file.Clone()
+
+	if err = file.AddLines("!keep.log", "", "*.tmp"); err != nil {
+		t.Fatalf("AddLines() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath  string
+		wantFile  bool
+		wantClone bool
+	}{
+		{givePath: "debug.log", wantFile: true, wantClone: true},
+		{givePath: "keep.log", wantFile: false, wantClone: true},
+		{givePath: "cache.tmp", wantFile: true, wantClone: false},
+		{givePath: "build/out", wantFile: true, wantClone: true},
+	}
+
+	for _, tt := range tests {
+		if got := file.Match(tt.givePath); got != tt.wantFile {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.wantFile)
+		}
+
+		if got := base.Match(tt.givePath); got != tt.wantClone {
+			t.Errorf("Clone().Match(%q) = %v, want %v", tt.givePath, got, tt.wantClone)
+		}
+	}
+
+	want := path + ":1: *.log\n" + path + ":2: build/\n1: !keep.log\n3: *.tmp"
+	if got := file.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	err = file.AddLines("*.bak", "[invalid")
+
+	var parseErr *gitignore.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 {
+		t.Fatalf("AddLines() error = %v, want *ParseError on line 2", err)
+	}
+
+	if file.Len() != 4 || file.Match("old.bak") {
+		t.Errorf("AddLines() modified the File despite an error, Len() = %d", file.Len())
+	}
+}
+
+func TestFile_AddLines_Options(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveOpts  []gitignore.Option
+		giveAdded []string
+		givePath  string
+		wantMatch bool
+	}{
+		{
+			name:      "Default",
+			giveAdded: []string{"*.TMP"},
+			givePath:  "cache.tmp",
+			wantMatch: false,
+		},
+		{
+			name:      "Ignore case",
+			giveOpts:  []gitignore.Option{gitignore.WithIgnoreCase()},
+			giveAdded: []string{"*.TMP"},
+			givePath:  "cache.tmp",
+			wantMatch: true,
+		},
+		{
+			name:      "Regex engine",
+			giveAdded: []string{"q|z"},
+			givePath:  "z",
+			wantMatch: true,
+		},
+		{
+			name:      "Wildmatch",
+			giveOpts:  []gitignore.Option{gitignore.WithWildmatch()},
+			giveAdded: []string{"q|z"},
+			givePath:  "z",
+			wantMatch: false,
+		},
+		{
+			name:      "Cloned",
+			giveOpts:  []gitignore.Option{gitignore.WithIgnoreCase()},
+			giveAdded: []string{"Build/"},
+			givePath:  "build/out",
+			wantMatch: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := gitignore.NewFromLines([]string{"*.log"}, tt.giveOpts...)
+			if err != nil {
+				t.Fatalf("NewFromLines() error = %v", err)
+			}
+
+			file = file.Clone()
+
+			if err = file.AddLines(tt.giveAdded...); err != nil {
+				t.Fatalf("AddLines() error = %v", err)
+			}
+
+			if got := file.Match(tt.givePath); got != tt.wantMatch {
+				t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestFile_Clone_Independent(t *testing.T) {
+	t.Parallel()
+
+	base := newTestMatcher(t, "*.log", "!keep.log", "build/")
+	clone := base.Clone()
+
+	if err := clone.AddLines("*.tmp"); err != nil {
+		t.Fatalf("AddLines() error = %v", err)
+	}
+
+	clone.RemoveIf(func(info gitignore.PatternInfo) bool {
+		return info.Negate
+	})
+
+	if err := base.AddLines("*.bak"); err != nil {
+		t.Fatalf("AddLines() error = %v", err)
+	}
+
+	if got, want := base.String(), "1: *.log\n2: !keep.log\n3: build/\n1: *.bak"; got != want {
+		t.Errorf("base.String() = %q, want %q", got, want)
+	}
+
+	if got, want := clone.String(), "1: *.log\n3: build/\n1: *.tmp"; got != want {
+		t.Errorf("clone.String() = %q, want %q", got, want)
+	}
+
+	if base.Match("keep.log") || !clone.Match("keep.log") {
+		t.Error("Clone() shares rules with the original File")
+	}
+}
+
+func TestFile_MatchAbsolute(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log\n/build/\n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	file, err := gitignore.New(path)
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", path, err)
+	}
+
+	tests := []struct {
+		name      string
+		giveFile  *gitignore.File
+		givePath  string
+		wantMatch bool
+		wantErr   error
+	}{
+		{
+			name:      "Ignored file",
+			giveFile:  file,
+			givePath:  filepath.Join(dir, "src", "debug.log"),
+			wantMatch: true,
+		},
+		{
+			name:      "Included file",
+			giveFile:  file,
+			givePath:  filepath.Join(dir, "src", "main.go"),
+			wantMatch: false,
+		},
+		{
+			name:      "Ignored directory",
+			giveFile:  file,
+			givePath:  filepath.Join(dir, "build") + string(filepath.Separator),
+			wantMatch: true,
+		},
+		{
+			name:      "Base directory",
+			giveFile:  file,
+			givePath:  dir,
+			wantMatch: false,
+		},
+		{
+			name:     "Outside base directory",
+			giveFile: file,
+			givePath: filepath.Join(filepath.Dir(dir), "debug.log"),
+			wantErr:  gitignore.ErrOutsideBase,
+		},
+		{
+			name:      "Scoped with prefix",
+			giveFile:  file.WithPrefix("build"),
+			givePath:  filepath.Join(dir, "build", "out"),
+			wantMatch: true,
+		},
+		{
+			name:     "Scoped with prefix outside subdirectory",
+			giveFile: file.WithPrefix("build"),
+			givePath: filepath.Join(dir, "debug.log"),
+			wantErr:  gitignore.ErrOutsideBase,
+		},
+		{
+			name:      "Base directory set with option",
+			giveFile:  newTestMatcherWithOptions(t, []string{"/build/"}, gitignore.WithBaseDir(dir)),
+			givePath:  filepath.Join(dir, "build", "out"),
+			wantMatch: true,
+		},
+		{
+			name:     "No base directory",
+			giveFile: newTestMatcher(t, "*.log"),
+			givePath: filepath.Join(dir, "debug.log"),
+			wantErr:  gitignore.ErrNoBaseDir,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.giveFile.MatchAbsolute(tt.givePath)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MatchAbsolute(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+
+			if got != tt.wantMatch {
+				t.Errorf("MatchAbsolute(%q) = %v, want %v", tt.givePath, got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func newTestMatcherWithOptions(t *testing.T, lines []string, opts ...gitignore.Option) *gitignore.File {
+	t.Helper()
+
+	file, err := gitignore.NewFromLines(lines, opts...)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	return file
+}
@@ -0,0 +1,255 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+)
+
+var (
+	_ fs.ReadDirFS  = (*FilterFS)(nil)
+	_ fs.StatFS     = (*FilterFS)(nil)
+	_ fs.ReadFileFS = (*FilterFS)(nil)
+	_ fs.SubFS      = (*FilterFS)(nil)
+	_ fs.GlobFS     = (*FilterFS)(nil)
+)
+
+// FilterFS is an [fs.FS] that hides every path ignored by a [Matcher].
+//
+// Ignored files and directories behave as if they did not exist: opening them
+// returns [fs.ErrNotExist] and they are omitted from directory listings and
+// glob results. Besides Open, FilterFS implements [fs.ReadDirFS], [fs.StatFS],
+// [fs.ReadFileFS], [fs.SubFS], and [fs.GlobFS], delegating to the wrapped file
+// system's own implementation when it has one, so wrapping a file system does
+// not lose its optimized code paths.
+type FilterFS struct {
+	fsys    fs.FS
+	matcher Matcher
+
+	// prefix is the path of fsys relative to the directory the matcher rules
+	// apply to, set when the file system was created by Sub.
+	prefix string
+}
+
+// NewFilterFS returns a FilterFS that hides paths in fsys ignored by matcher.
+// Paths in fsys are matched as if fsys was rooted at the directory containing
+// the rules.
+func NewFilterFS(fsys fs.FS, matcher Matcher) *FilterFS {
+	return &FilterFS{
+		fsys:    fsys,
+		matcher: matcher,
+	}
+}
+
+// Open implements [fs.FS].
+func (f *FilterFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	file, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if f.hidden(name, info.IsDir()) {
+		file.Close()
+
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if !info.IsDir() {
+		return file, nil
+	}
+
+	return &filterDir{
+		File: file,
+		fsys: f,
+		name: name,
+	}, nil
+}
+
+// ReadDir implements [fs.ReadDirFS].
+func (f *FilterFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if f.hidden(name, true) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries, err := fs.ReadDir(f.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return f.filterEntries(name, entries), nil
+}
+
+// Stat implements [fs.StatFS].
+func (f *FilterFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	info, err := fs.Stat(f.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if f.hidden(name, info.IsDir()) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return info, nil
+}
+
+// ReadFile implements [fs.ReadFileFS].
+func (f *FilterFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if _, err := f.Stat(name); err != nil {
+		return nil, err
+	}
+
+	data, err := fs.ReadFile(f.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return data, nil
+}
+
+// Sub implements [fs.SubFS]. The returned file system keeps matching paths
+// relative to the directory the rules apply to.
+func (f *FilterFS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+
+	if dir == "." {
+		return f, nil
+	}
+
+	info, err := f.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+
+	sub, err := fs.Sub(f.fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return &FilterFS{
+		fsys:    sub,
+		matcher: f.matcher,
+		prefix:  path.Join(f.prefix, dir),
+	}, nil
+}
+
+// Glob implements [fs.GlobFS].
+func (f *FilterFS) Glob(pattern string) ([]string, error) {
+	matches, err := fs.Glob(f.fsys, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	filtered := make([]string, 0, len(matches))
+
+	for _, name := range matches {
+		info, err := fs.Stat(f.fsys, name)
+		if err != nil {
+			continue
+		}
+
+		if f.hidden(name, info.IsDir()) {
+			continue
+		}
+
+		filtered = append(filtered, name)
+	}
+
+	return filtered, nil
+}
+
+// hidden reports whether the given path should be hidden from callers.
+func (f *FilterFS) hidden(name string, isDir bool) bool {
+	if name == "." {
+		return false
+	}
+
+	return IsIgnored(f.matcher, path.Join(f.prefix, name), isDir)
+}
+
+// filterEntries returns the entries of the directory dir that are not hidden.
+func (f *FilterFS) filterEntries(dir string, entries []fs.DirEntry) []fs.DirEntry {
+	filtered := make([]fs.DirEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if f.hidden(path.Join(dir, entry.Name()), entry.IsDir()) {
+			continue
+		}
+
+		filtered = append(filtered, entry)
+	}
+
+	return filtered
+}
+
+// filterDir is a directory opened from a FilterFS. It omits hidden entries
+// when read.
+type filterDir struct {
+	fs.File
+
+	fsys *FilterFS
+	name string
+}
+
+// ReadDir implements [fs.ReadDirFile].
+func (d *filterDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	dir, ok := d.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: errors.ErrUnsupported}
+	}
+
+	if n <= 0 {
+		entries, err := dir.ReadDir(n)
+
+		return d.fsys.filterEntries(d.name, entries), err //nolint:wrapcheck // io.EOF must not be wrapped
+	}
+
+	filtered := make([]fs.DirEntry, 0, n)
+
+	for len(filtered) < n {
+		entries, err := dir.ReadDir(n - len(filtered))
+
+		filtered = append(filtered, d.fsys.filterEntries(d.name, entries)...)
+
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(filtered) > 0 {
+				return filtered, nil
+			}
+
+			return filtered, err //nolint:wrapcheck // io.EOF must not be wrapped
+		}
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,233 @@
+package gitignore_test
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func newTestFilterFS(t *testing.T) *gitignore.FilterFS {
+	t.Helper()
+
+	matcher, err := gitignore.NewFromLines([]string{
+		"*.log",
+		"build/",
+		"!keep.log",
+		"docs/private",
+	})
+	if err != nil {
+		t.Fatalf("failed to create matcher: %v", err)
+	}
+
+	fsys := fstest.MapFS{
+		"main.go":              {Data: []byte("package main")},
+		"debug.log":            {Data: []byte("debug")},
+		"keep.log":             {Data: []byte("keep")},
+		"build/out.bin":        {Data: []byte("binary")},
+		"build/keep.log":       {Data: []byte("keep")},
+		"docs/index.md":        {Data: []byte("# Index")},
+		"docs/private/todo.md": {Data: []byte("# TODO")},
+		"src/app/app.go":       {Data: []byte("package app")},
+		"src/app/app.log":      {Data: []byte("app")},
+	}
+
+	return gitignore.NewFilterFS(fsys, matcher)
+}
+
+func TestFilterFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilterFS(t)
+
+	err := fstest.TestFS(fsys, "main.go", "keep.log", "docs/index.md", "src/app/app.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := fsys.Sub("src")
+	if err != nil {
+		t.Fatalf("Sub(%q) unexpected error: %v", "src", err)
+	}
+
+	if err = fstest.TestFS(sub, "app/app.go"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilterFS_Hidden(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilterFS(t)
+
+	tests := []struct {
+		name     string
+		givePath string
+		wantErr  error
+	}{
+		{
+			name:     "Included file",
+			givePath: "main.go",
+		},
+		{
+			name:     "Negated file",
+			givePath: "keep.log",
+		},
+		{
+			name:     "Ignored file",
+			givePath: "debug.log",
+			wantErr:  fs.ErrNotExist,
+		},
+		{
+			name:     "Ignored directory",
+			givePath: "build",
+			wantErr:  fs.ErrNotExist,
+		},
+		{
+			name:     "File inside ignored directory",
+			givePath: "build/out.bin",
+			wantErr:  fs.ErrNotExist,
+		},
+		{
+			name:     "Negated file inside ignored directory",
+			givePath: "build/keep.log",
+			wantErr:  fs.ErrNotExist,
+		},
+		{
+			name:     "Nested ignored file",
+			givePath: "src/app/app.log",
+			wantErr:  fs.ErrNotExist,
+		},
+		{
+			name:     "Missing file",
+			givePath: "missing.go",
+			wantErr:  fs.ErrNotExist,
+		},
+		{
+			name:     "Invalid path",
+			givePath: "../main.go",
+			wantErr:  fs.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := fsys.Open(tt.givePath)
+			if err == nil {
+				file.Close()
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Open(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+
+			if _, err = fsys.Stat(tt.givePath); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Stat(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterFS_ReadDir(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilterFS(t)
+
+	tests := []struct {
+		name      string
+		giveDir   string
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "Root directory",
+			giveDir:   ".",
+			wantNames: []string{"docs", "keep.log", "main.go", "src"},
+		},
+		{
+			name:      "Directory with ignored child directory",
+			giveDir:   "docs",
+			wantNames: []string{"index.md"},
+		},
+		{
+			name:      "Nested directory",
+			giveDir:   "src/app",
+			wantNames: []string{"app.go"},
+		},
+		{
+			name:    "Ignored directory",
+			giveDir: "build",
+			wantErr: fs.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := fsys.ReadDir(tt.giveDir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadDir(%q) error = %v, want %v", tt.giveDir, err, tt.wantErr)
+			}
+
+			names := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("ReadDir(%q) = %v, want %v", tt.giveDir, names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestFilterFS_Glob(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilterFS(t)
+
+	got, err := fsys.Glob("*.log")
+	if err != nil {
+		t.Fatalf("Glob(%q) unexpected error: %v", "*.log", err)
+	}
+
+	want := []string{"keep.log"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Glob(%q) = %v, want %v", "*.log", got, want)
+	}
+}
+
+func TestFilterFS_Sub(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFilterFS(t)
+
+	if _, err := fsys.Sub("build"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Sub(%q) error = %v, want %v", "build", err, fs.ErrNotExist)
+	}
+
+	sub, err := fsys.Sub("docs")
+	if err != nil {
+		t.Fatalf("Sub(%q) unexpected error: %v", "docs", err)
+	}
+
+	if _, err = fs.Stat(sub, "private/todo.md"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(%q) on sub file system error = %v, want %v", "private/todo.md", err, fs.ErrNotExist)
+	}
+
+	data, err := fs.ReadFile(sub, "index.md")
+	if err != nil {
+		t.Fatalf("ReadFile(%q) on sub file system unexpected error: %v", "index.md", err)
+	}
+
+	if string(data) != "# Index" {
+		t.Errorf("ReadFile(%q) on sub file system = %q, want %q", "index.md", data, "# Index")
+	}
+}
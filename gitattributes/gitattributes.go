@@ -0,0 +1,379 @@
+// Package gitattributes resolves the attributes git assigns to paths from
+// .gitattributes files, such as the ones driving Git LFS, line ending
+// conversion or GitHub Linguist, matching paths with the same pattern engine
+// as the gitignore package.
+//
+// Like with git, a pattern without a slash matches a file name at any depth,
+// while a pattern only matches the paths it names, not the contents of the
+// directories it names. Negated patterns are not allowed and their lines are
+// skipped, like git does.
+package gitattributes
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+const (
+	// ErrInvalidAttribute is returned for an attribute whose name is not
+	// made of letters, digits, dashes, dots and underscores, or starts with a
+	// dash.
+	ErrInvalidAttribute xerrors.Error = "invalid attribute name"
+
+	// ErrInvalidPattern is returned for a pattern that cannot be compiled,
+	// or a quoted pattern that is not terminated.
+	ErrInvalidPattern xerrors.Error = "invalid pattern"
+
+	// ErrReadingFile is returned when a .gitattributes file cannot be read.
+	ErrReadingFile xerrors.Error = "failed to read attributes"
+)
+
+// macroPrefix starts the lines defining a macro attribute.
+const macroPrefix = "[attr]"
+
+// State is the state of an attribute for a path.
+type State int
+
+const (
+	// Unspecified means no pattern matching the path says anything about the
+	// attribute, or a pattern explicitly reset it with "!attr".
+	Unspecified State = iota
+
+	// Set means the attribute was set with "attr".
+	Set
+
+	// Unset means the attribute was unset with "-attr".
+	Unset
+
+	// Valued means the attribute was set to a value with "attr=value".
+	Valued
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Unspecified:
+		return "unspecified"
+	case Set:
+		return "set"
+	case Unset:
+		return "unset"
+	case Valued:
+		return "valued"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Attribute is the state of a named attribute for a path.
+type Attribute struct {
+	// Name is the name of the attribute, such as "text" or "filter".
+	Name string
+
+	// Value is the value of the attribute, set if its State is Valued.
+	Value string
+
+	// State is the state of the attribute.
+	State State
+}
+
+// String returns the value of the attribute in the format of git check-attr:
+// "set", "unset", "unspecified", or its value.
+func (a Attribute) String() string {
+	if a.State == Valued {
+		return a.Value
+	}
+
+	return a.State.String()
+}
+
+// rule is a line of a .gitattributes file: a pattern and the attributes it
+// assigns.
+type rule struct {
+	pattern *pattern.Pattern
+	attrs   []Attribute
+}
+
+// File holds the rules of one or more .gitattributes files applying to the
+// same directory.
+//
+// A File is safe for concurrent use.
+type File struct {
+	// macros holds the macro attributes, which set other attributes when
+	// set, keyed by name. The "binary" macro is always defined.
+	macros map[string][]Attribute
+	rules  []rule
+}
+
+// New creates a File from the .gitattributes file at path.
+func New(path string) (*File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingFile, err)
+	}
+	defer file.Close()
+
+	return parse(path, file)
+}
+
+// NewFromFS creates a File from the .gitattributes file at path in fsys.
+func NewFromFS(fsys fs.FS, path string) (*File, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingFile, err)
+	}
+	defer file.Close()
+
+	return parse(path, file)
+}
+
+// NewFromLines creates a File from the given lines.
+func NewFromLines(lines []string) (*File, error) {
+	return parse("", strings.NewReader(strings.Join(lines, "\n")))
+}
+
+// Merge returns a File holding the rules and macros of every given file, in
+// order, with later files taking precedence, the way git layers the
+// .gitattributes files of parent and child directories. Every file must apply
+// to the same directory. Nil files are skipped.
+func Merge(files ...*File) *File {
+	merged := &File{
+		macros: builtinMacros(),
+	}
+
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+
+		merged.rules = append(merged.rules, file.rules...)
+
+		for name, attrs := range file.macros {
+			merged.macros[name] = attrs
+		}
+	}
+
+	return merged
+}
+
+// Lookup returns every attribute specified for path, keyed by name. The path
+// is slash-separated and relative to the directory of the .gitattributes file,
+// with a trailing slash for directories. Attributes reset with "!attr" are not
+// included.
+func (f *File) Lookup(path string) map[string]Attribute {
+	resolved := make(map[string]Attribute)
+
+	// Like git, the rules are consulted from the last to the first, and the
+	// first one assigning an attribute decides it.
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if f.rules[i].pattern.Match(path) {
+			f.fill(resolved, f.rules[i].attrs)
+		}
+	}
+
+	for name, attr := range resolved {
+		if attr.State == Unspecified {
+			delete(resolved, name)
+		}
+	}
+
+	return resolved
+}
+
+// Get returns the attribute name for path, in the form taken by Lookup. It is
+// Unspecified if no rule assigns it.
+func (f *File) Get(path, name string) Attribute {
+	if attr, ok := f.Lookup(path)[name]; ok {
+		return attr
+	}
+
+	return Attribute{Name: name}
+}
+
+// fill assigns the attributes of a line to resolved, from the last to the
+// first, skipping the ones already assigned by a rule taking precedence, and
+// expanding the macros that are set.
+func (f *File) fill(resolved map[string]Attribute, attrs []Attribute) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		attr := attrs[i]
+
+		if _, ok := resolved[attr.Name]; ok {
+			continue
+		}
+
+		resolved[attr.Name] = attr
+
+		if macro, ok := f.macros[attr.Name]; ok && attr.State == Set {
+			f.fill(resolved, macro)
+		}
+	}
+}
+
+// builtinMacros returns the macros git always defines.
+func builtinMacros() map[string][]Attribute {
+	return map[string][]Attribute{
+		"binary": {
+			{Name: "diff", State: Unset},
+			{Name: "merge", State: Unset},
+			{Name: "text", State: Unset},
+		},
+	}
+}
+
+// parse creates a File from the lines read from r, attributing errors to
+// source.
+func parse(source string, r io.Reader) (*File, error) {
+	var (
+		file       = &File{macros: builtinMacros()}
+		scanner    = bufio.NewScanner(r)
+		lineNumber int
+	)
+
+	for scanner.Scan() {
+		lineNumber++
+
+		if err := file.parseLine(scanner.Text()); err != nil {
+			if source == "" {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			return nil, fmt.Errorf("%s:%d: %w", source, lineNumber, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingFile, err)
+	}
+
+	return file, nil
+}
+
+// parseLine adds the rule or macro defined by line to f.
+func (f *File) parseLine(line string) error {
+	line = strings.TrimLeft(strings.TrimRight(line, "\r"), " \t")
+
+	if line == "" || line[0] == '#' {
+		return nil
+	}
+
+	rawPattern, rest, err := cutPattern(line)
+	if err != nil {
+		return err
+	}
+
+	attrs := make([]Attribute, 0)
+
+	for _, field := range strings.Fields(rest) {
+		attr, err := parseAttribute(field)
+		if err != nil {
+			return err
+		}
+
+		attrs = append(attrs, attr)
+	}
+
+	if name, ok := strings.CutPrefix(rawPattern, macroPrefix); ok {
+		if !validName(name) {
+			return fmt.Errorf("%w: %q", ErrInvalidAttribute, name)
+		}
+
+		f.macros[name] = attrs
+
+		return nil
+	}
+
+	// Negated patterns are not allowed, and skipped with a warning by git.
+	if strings.HasPrefix(rawPattern, "!") {
+		return nil
+	}
+
+	pat, err := pattern.Compile(rawPattern, true)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrInvalidPattern, rawPattern, err)
+	}
+
+	f.rules = append(f.rules, rule{pattern: pat, attrs: attrs})
+
+	return nil
+}
+
+// cutPattern returns the pattern at the start of line, unquoted if it is a
+// C-style quoted string, and the rest of line.
+func cutPattern(line string) (string, string, error) {
+	if line[0] != '"' {
+		i := strings.IndexAny(line, " \t")
+		if i < 0 {
+			return line, "", nil
+		}
+
+		return line[:i], line[i:], nil
+	}
+
+	for i := 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			unquoted, err := strconv.Unquote(line[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("%w: %s: %w", ErrInvalidPattern, line[:i+1], err)
+			}
+
+			return unquoted, line[i+1:], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: unterminated quote in %s", ErrInvalidPattern, line)
+}
+
+// parseAttribute parses an attribute assignment such as "text", "-text",
+// "!text" or "eol=lf".
+func parseAttribute(field string) (Attribute, error) {
+	var attr Attribute
+
+	switch {
+	case strings.HasPrefix(field, "-"):
+		attr = Attribute{Name: field[1:], State: Unset}
+	case strings.HasPrefix(field, "!"):
+		attr = Attribute{Name: field[1:], State: Unspecified}
+	default:
+		name, value, valued := strings.Cut(field, "=")
+
+		attr = Attribute{Name: name, State: Set}
+		if valued {
+			attr = Attribute{Name: name, Value: value, State: Valued}
+		}
+	}
+
+	if !validName(attr.Name) {
+		return Attribute{}, fmt.Errorf("%w: %q", ErrInvalidAttribute, field)
+	}
+
+	return attr, nil
+}
+
+// validName reports whether name is a valid attribute name, made of letters,
+// digits, dashes, dots and underscores, and not starting with a dash.
+func validName(name string) bool {
+	if name == "" || name[0] == '-' {
+		return false
+	}
+
+	for i := range len(name) {
+		c := name[i]
+
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,280 @@
+package gitattributes_test
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/gitattributes"
+)
+
+// testLines mirrors a .gitattributes file whose attributes were checked
+// against git check-attr.
+//
+//nolint:gochecknoglobals // Read-only test table.
+var testLines = []string{
+	"# Line endings.",
+	"*.txt text eol=lf",
+	"*.png binary",
+	"*.bin binary diff",
+	"*.dat diff binary",
+	"docs/*.md linguist-documentation",
+	"*.md -text",
+	"README.md !text",
+	"[attr]lfs filter=lfs diff=lfs merge=lfs -text",
+	"*.psd lfs",
+	"vendor/ linguist-vendored",
+	"vendor/** linguist-vendored",
+	`"with space.txt" custom`,
+	"!*.go ignored",
+}
+
+func TestFile_Lookup(t *testing.T) {
+	t.Parallel()
+
+	file, err := gitattributes.NewFromLines(testLines)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		givePath string
+		want     map[string]string
+	}{
+		{
+			name:     "Set and valued",
+			givePath: "a.txt",
+			want:     map[string]string{"text": "set", "eol": "lf"},
+		},
+		{
+			name:     "Nested file",
+			givePath: "sub/b.txt",
+			want:     map[string]string{"text": "set", "eol": "lf"},
+		},
+		{
+			name:     "Builtin macro",
+			givePath: "x.png",
+			want:     map[string]string{"binary": "set", "diff": "unset", "merge": "unset", "text": "unset"},
+		},
+		{
+			name:     "Attribute after macro",
+			givePath: "x.bin",
+			want:     map[string]string{"binary": "set", "diff": "set", "merge": "unset", "text": "unset"},
+		},
+		{
+			name:     "Macro after attribute",
+			givePath: "x.dat",
+			want:     map[string]string{"binary": "set", "diff": "unset", "merge": "unset", "text": "unset"},
+		},
+		{
+			name:     "Anchored pattern",
+			givePath: "docs/a.md",
+			want:     map[string]string{"linguist-documentation": "set", "text": "unset"},
+		},
+		{
+			name:     "Anchored pattern in subdirectory",
+			givePath: "a/docs/a.md",
+			want:     map[string]string{"text": "unset"},
+		},
+		{
+			name:     "Reset attribute",
+			givePath: "README.md",
+			want:     map[string]string{},
+		},
+		{
+			name:     "Custom macro",
+			givePath: "x.psd",
+			want:     map[string]string{"lfs": "set", "filter": "lfs", "diff": "lfs", "merge": "lfs", "text": "unset"},
+		},
+		{
+			name:     "Directory pattern",
+			givePath: "vendor/",
+			want:     map[string]string{"linguist-vendored": "set"},
+		},
+		{
+			name:     "Directory contents",
+			givePath: "vendor/x.go",
+			want:     map[string]string{"linguist-vendored": "set"},
+		},
+		{
+			name:     "Quoted pattern",
+			givePath: "with space.txt",
+			want:     map[string]string{"text": "set", "eol": "lf", "custom": "set"},
+		},
+		{
+			name:     "Negated pattern",
+			givePath: "main.go",
+			want:     map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := make(map[string]string)
+			for name, attr := range file.Lookup(tt.givePath) {
+				got[name] = attr.String()
+			}
+
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("Lookup(%q) = %v, want %v", tt.givePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFile_Get(t *testing.T) {
+	t.Parallel()
+
+	file, err := gitattributes.NewFromLines(testLines)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		givePath string
+		giveName string
+		want     gitattributes.Attribute
+	}{
+		{
+			name:     "Set",
+			givePath: "a.txt",
+			giveName: "text",
+			want:     gitattributes.Attribute{Name: "text", State: gitattributes.Set},
+		},
+		{
+			name:     "Unset",
+			givePath: "a.png",
+			giveName: "diff",
+			want:     gitattributes.Attribute{Name: "diff", State: gitattributes.Unset},
+		},
+		{
+			name:     "Valued",
+			givePath: "a.txt",
+			giveName: "eol",
+			want:     gitattributes.Attribute{Name: "eol", Value: "lf", State: gitattributes.Valued},
+		},
+		{
+			name:     "Unspecified",
+			givePath: "a.go",
+			giveName: "text",
+			want:     gitattributes.Attribute{Name: "text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := file.Get(tt.givePath, tt.giveName); got != tt.want {
+				t.Errorf("Get(%q, %q) = %+v, want %+v", tt.givePath, tt.giveName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFromLines_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveLines []string
+		wantErr   error
+	}{
+		{
+			name:      "Invalid attribute",
+			giveLines: []string{"*.txt te$t"},
+			wantErr:   gitattributes.ErrInvalidAttribute,
+		},
+		{
+			name:      "Attribute starting with a dash",
+			giveLines: []string{"*.txt --text"},
+			wantErr:   gitattributes.ErrInvalidAttribute,
+		},
+		{
+			name:      "Invalid macro",
+			giveLines: []string{"[attr]-bin -diff"},
+			wantErr:   gitattributes.ErrInvalidAttribute,
+		},
+		{
+			name:      "Unterminated quote",
+			giveLines: []string{`"a.txt text`},
+			wantErr:   gitattributes.ErrInvalidPattern,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := gitattributes.NewFromLines(tt.giveLines); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewFromLines() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), ".gitattributes")
+
+	if err := os.WriteFile(name, []byte("*.sh text eol=lf\r\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	file, err := gitattributes.New(name)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if got := file.Get("run.sh", "eol").String(); got != "lf" {
+		t.Errorf("Get(%q, %q) = %q, want %q", "run.sh", "eol", got, "lf")
+	}
+
+	if _, err = gitattributes.New(name + ".missing"); !errors.Is(err, gitattributes.ErrReadingFile) {
+		t.Errorf("New() error = %v, want %v", err, gitattributes.ErrReadingFile)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	parent, err := gitattributes.NewFromLines([]string{
+		"[attr]generated linguist-generated -diff",
+		"*.go text diff=golang",
+	})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	child, err := gitattributes.NewFromLines([]string{
+		"*.go -text",
+		"*.pb.go generated",
+	})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	merged := gitattributes.Merge(parent, nil, child)
+
+	want := map[string]string{"text": "unset", "diff": "golang"}
+
+	got := make(map[string]string)
+	for name, attr := range merged.Lookup("main.go") {
+		got[name] = attr.String()
+	}
+
+	if !maps.Equal(got, want) {
+		t.Errorf("Lookup(%q) = %v, want %v", "main.go", got, want)
+	}
+
+	if got := merged.Get("a.pb.go", "diff").State; got != gitattributes.Unset {
+		t.Errorf("Get(%q, %q).State = %v, want %v", "a.pb.go", "diff", got, gitattributes.Unset)
+	}
+}
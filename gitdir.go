@@ -0,0 +1,43 @@
+package gitignore
+
+import (
+	"fmt"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+)
+
+// WithGitDirIgnored makes the File ignore every directory named .git, along
+// with its contents and the .git files of linked worktrees and submodules,
+// whatever its rules say, so callers walking a working tree do not need to
+// skip them themselves. The decision is reported by MatchResult, MatchAll and
+// Explain with a ".git" pattern without source or line number, which comes
+// after every other rule.
+func WithGitDirIgnored() Option {
+	return func(o *options) {
+		o.gitDir = true
+	}
+}
+
+// WithoutGitDirIgnored stops Match from reporting the .git directories of the
+// working tree, and everything inside them, as ignored, which it does by
+// default like with WithGitDirIgnored.
+func WithoutGitDirIgnored() RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.keepGitDir = true
+	}
+}
+
+// gitDirPattern returns the pattern matching git directories and their
+// contents at any depth.
+func gitDirPattern() (*pattern.Pattern, error) {
+	patterns, err := new(pattern.Parser).Parse(strings.NewReader(gitDir))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	pat := patterns[0]
+	pat.Line = 0
+
+	return pat, nil
+}
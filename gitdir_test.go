@@ -0,0 +1,147 @@
+package gitignore_test
+
+import (
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestWithGitDirIgnored(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: ".git/", want: true},
+		{givePath: ".git/config", want: true},
+		{givePath: "sub/.git", want: true},
+		{givePath: "sub/.git/objects/pack/", want: true},
+		{givePath: ".gitignore", want: false},
+		{givePath: ".github/workflows/ci.yml", want: false},
+		{givePath: "x.git", want: false},
+		{givePath: "debug.log", want: true},
+		{givePath: "main.go", want: false},
+	}
+
+	// A negated rule cannot re-include git directories.
+	file, err := gitignore.NewFromLines([]string{"*.log", "!.git/"}, gitignore.WithGitDirIgnored())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	plain, err := gitignore.NewFromLines([]string{"*.log", "!.git/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.givePath, func(t *testing.T) {
+			t.Parallel()
+
+			if got := file.Match(tt.givePath); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+			}
+		})
+	}
+
+	if plain.Match(".git/config") {
+		t.Errorf("Match(%q) = true without WithGitDirIgnored, want false", ".git/config")
+	}
+
+	result, ok := file.MatchResult(".git/HEAD")
+	if !ok || !result.Ignored || result.Pattern.Raw != ".git" || result.Pattern.Line != 0 {
+		t.Errorf("MatchResult() = %+v, %v, want the .git pattern without line number", result, ok)
+	}
+
+	if file.Fingerprint() == plain.Fingerprint() {
+		t.Error("Fingerprint() does not depend on WithGitDirIgnored")
+	}
+
+	empty, err := gitignore.NewFromLines(nil, gitignore.WithGitDirIgnored())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	if empty.IsEmpty() {
+		t.Error("IsEmpty() = true, want false with WithGitDirIgnored")
+	}
+}
+
+func TestWithoutGitDirIgnored(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":  "",
+		".gitignore":   "!*\n",
+		"sub/.git":     "gitdir: ../.git/modules/sub\n",
+		"sub/main.go":  "",
+		"other/x.conf": "",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	kept, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithoutGitDirIgnored(),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	for _, path := range []string{".git/", ".git/config", "sub/.git"} {
+		if !repo.Match(path) {
+			t.Errorf("Match(%q) = false, want true by default", path)
+		}
+
+		if kept.Match(path) {
+			t.Errorf("Match(%q) = true with WithoutGitDirIgnored, want false", path)
+		}
+	}
+
+	if repo.Match("sub/main.go") || repo.Match("other/x.conf") {
+		t.Error("Match() ignores paths outside of git directories")
+	}
+}
+
+func TestWithGitDirIgnored_Trace(t *testing.T) {
+	t.Parallel()
+
+	file, err := gitignore.NewFromLines([]string{"*.log", "!.git/"}, gitignore.WithGitDirIgnored())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	all := file.MatchAll(".git/")
+	if len(all) != 2 || all[0].Raw != "!.git/" || all[1].Raw != ".git" || all[1].Line != 0 {
+		t.Errorf("MatchAll() = %+v, want the negated rule followed by the .git pattern", all)
+	}
+
+	if all = file.MatchAll("debug.log"); len(all) != 1 || all[0].Raw != "*.log" {
+		t.Errorf("MatchAll() = %+v, want only the *.log rule", all)
+	}
+
+	explanation := file.Explain(".git/")
+	if len(explanation.Steps) != 3 {
+		t.Fatalf("Explain() has %d steps, want 3", len(explanation.Steps))
+	}
+
+	last := explanation.Steps[2]
+	if !last.Matched || last.Pattern.Raw != ".git" {
+		t.Errorf("Explain() last step = %+v, want the matching .git pattern", last)
+	}
+
+	if !explanation.Ignored() || explanation.Result.Pattern != last.Pattern {
+		t.Errorf("Explain() result = %+v, want the .git pattern deciding", explanation.Result)
+	}
+
+	if steps := file.Explain("debug.log").Steps; steps[2].Matched {
+		t.Errorf("Explain() matched the .git pattern for %q", "debug.log")
+	}
+}
@@ -0,0 +1,132 @@
+// Package gitignoretest provides utilities for testing code built on top of
+// the gitignore package.
+package gitignoretest
+
+import (
+	"sync"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+const (
+	// DefaultGoroutines is the default number of goroutines started by
+	// Stress.
+	DefaultGoroutines = 16
+
+	// DefaultIterations is the default number of times each goroutine started
+	// by Stress matches every path.
+	DefaultIterations = 100
+)
+
+// StressOption configures a Stress run.
+type StressOption func(c *stressConfig)
+
+// stressConfig holds the configuration of a Stress run.
+type stressConfig struct {
+	mutate     func(i int)
+	goroutines int
+	iterations int
+}
+
+// WithGoroutines sets the number of goroutines matching paths concurrently.
+func WithGoroutines(n int) StressOption {
+	return func(c *stressConfig) {
+		c.goroutines = n
+	}
+}
+
+// WithIterations sets the number of times each goroutine matches every path.
+func WithIterations(n int) StressOption {
+	return func(c *stressConfig) {
+		c.iterations = n
+	}
+}
+
+// WithMutator sets a function called repeatedly from its own goroutine while
+// paths are being matched, with an increasing counter, to exercise the matcher
+// while its state changes, such as when rules are reloaded.
+//
+// As the decisions of a matcher may legitimately change while it is mutated,
+// they are not compared with the ones made before the run starts.
+func WithMutator(fn func(i int)) StressOption {
+	return func(c *stressConfig) {
+		c.mutate = fn
+	}
+}
+
+// Stress matches paths against m from many goroutines at once, failing tb if a
+// decision differs from the one made sequentially before the run started.
+//
+// Run it with the race detector enabled, with go test -race, to turn the
+// thread-safety guarantees of a matcher into executable proof.
+func Stress(tb testing.TB, m gitignore.Matcher, paths []string, opts ...StressOption) {
+	tb.Helper()
+
+	c := &stressConfig{
+		goroutines: DefaultGoroutines,
+		iterations: DefaultIterations,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	want := make([]bool, len(paths))
+
+	for i, path := range paths {
+		want[i] = m.Match(path)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+		errs = make(chan int, c.goroutines)
+	)
+
+	if c.mutate != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+					c.mutate(i)
+				}
+			}
+		}()
+	}
+
+	var workers sync.WaitGroup
+
+	for range c.goroutines {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for range c.iterations {
+				for i, path := range paths {
+					if m.Match(path) != want[i] && c.mutate == nil {
+						errs <- i
+
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	workers.Wait()
+	close(done)
+	wg.Wait()
+	close(errs)
+
+	for i := range errs {
+		tb.Errorf("Match(%q) = %t under concurrent use, want %t", paths[i], !want[i], want[i])
+	}
+}
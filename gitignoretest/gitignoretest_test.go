@@ -0,0 +1,74 @@
+package gitignoretest_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/gitignore-go/gitignoretest"
+)
+
+func TestStress(t *testing.T) {
+	t.Parallel()
+
+	matcher, err := gitignore.NewFromLines([]string{"*.log", "!keep.log", "build/"})
+	if err != nil {
+		t.Fatalf("failed to create matcher: %v", err)
+	}
+
+	gitignoretest.Stress(t, matcher, []string{"a.log", "keep.log", "build/", "src/main.go"})
+}
+
+func TestStress_Mutator(t *testing.T) {
+	t.Parallel()
+
+	var (
+		flip    atomic.Bool
+		calls   atomic.Int64
+		matcher = gitignore.MatcherFunc(func(_ string) bool {
+			return flip.Load()
+		})
+	)
+
+	gitignoretest.Stress(t, matcher, []string{"a", "b"},
+		gitignoretest.WithGoroutines(4),
+		gitignoretest.WithIterations(50),
+		gitignoretest.WithMutator(func(i int) {
+			calls.Add(1)
+			flip.Store(i%2 == 0)
+		}),
+	)
+
+	if calls.Load() == 0 {
+		t.Error("Stress() never called the mutator")
+	}
+}
+
+func TestStress_DetectsInconsistency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		calls   atomic.Int64
+		matcher = gitignore.MatcherFunc(func(_ string) bool {
+			return calls.Add(1)%7 == 0
+		})
+		rec = &recorder{TB: t}
+	)
+
+	gitignoretest.Stress(rec, matcher, []string{"a"}, gitignoretest.WithGoroutines(2), gitignoretest.WithIterations(20))
+
+	if !rec.failed.Load() {
+		t.Error("Stress() did not report an inconsistent matcher")
+	}
+}
+
+// recorder records failures instead of failing the test.
+type recorder struct {
+	testing.TB
+
+	failed atomic.Bool
+}
+
+func (r *recorder) Errorf(_ string, _ ...any) {
+	r.failed.Store(true)
+}
@@ -2,4 +2,10 @@ module git.sr.ht/~jamesponddotco/gitignore-go
 
 go 1.23
 
-require git.sr.ht/~jamesponddotco/xstd-go v0.9.0
+require (
+	git.sr.ht/~jamesponddotco/xstd-go v0.9.0
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/go-git/go-billy/v5 v5.6.2
+)
+
+require golang.org/x/sys v0.29.0 // indirect
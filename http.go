@@ -0,0 +1,217 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var _ http.FileSystem = (*FilterFileSystem)(nil)
+
+// FileServer returns an [http.Handler] that serves the contents of the root
+// directory like [http.FileServer], except that paths ignored by m are
+// answered with "404 Not Found" and omitted from directory listings.
+//
+// Symbolic links are matched by the path of their target relative to root as
+// well, so a link such as public/env pointing to an ignored ../.env file is
+// hidden too, and links pointing outside of root are always hidden.
+//
+// It is meant as a safer drop-in for quick static and development servers,
+// which would otherwise happily serve .env files, build artifacts, and the
+// .git directory itself.
+func FileServer(root string, m Matcher) http.Handler {
+	return http.FileServer(http.FS(NewFilterFS(os.DirFS(root), newLinkMatcher(root, m))))
+}
+
+// linkMatcher is a Matcher for the files of the directory root that also
+// reports paths as ignored if they resolve, through symbolic links, to an
+// ignored path or to one outside of root.
+type linkMatcher struct {
+	matcher Matcher
+	root    string
+}
+
+// newLinkMatcher returns a linkMatcher for the files of root ignored by m.
+func newLinkMatcher(root string, m Matcher) *linkMatcher {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+
+	// The root itself may be a symbolic link, which the targets of the links
+	// inside it are resolved through.
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	return &linkMatcher{
+		matcher: m,
+		root:    root,
+	}
+}
+
+// Match implements [Matcher].
+func (m *linkMatcher) Match(name string) bool {
+	if m.matcher.Match(name) {
+		return true
+	}
+
+	name = strings.TrimSuffix(name, "/")
+
+	target, err := filepath.EvalSymlinks(filepath.Join(m.root, filepath.FromSlash(name)))
+	if err != nil {
+		// Missing files and broken links cannot be served anyway.
+		return false
+	}
+
+	rel, err := filepath.Rel(m.root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return true
+	}
+
+	rel = filepath.ToSlash(rel)
+	if rel == name {
+		return false
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return true
+	}
+
+	return rel != "." && IsIgnored(m.matcher, rel, info.IsDir())
+}
+
+// FilterFileSystem is an [http.FileSystem] that hides every path ignored by a
+// [Matcher], like FilterFS does for an [fs.FS], so servers built on an existing
+// http.FileSystem, such as an [http.Dir], never serve ignored files.
+//
+// Ignored files and directories behave as if they did not exist: opening them
+// returns [fs.ErrNotExist], which [http.FileServer] answers with "404 Not
+// Found", and they are omitted from directory listings. Unlike with FileServer,
+// symbolic links are matched by their own path only, since an http.FileSystem
+// offers no way to resolve them.
+type FilterFileSystem struct {
+	fsys    http.FileSystem
+	matcher Matcher
+}
+
+// NewFilterFileSystem returns a FilterFileSystem that hides paths in fsys
+// ignored by matcher. Paths in fsys are matched as if fsys was rooted at the
+// directory containing the rules.
+func NewFilterFileSystem(fsys http.FileSystem, matcher Matcher) *FilterFileSystem {
+	return &FilterFileSystem{
+		fsys:    fsys,
+		matcher: matcher,
+	}
+}
+
+// Open implements [http.FileSystem].
+func (f *FilterFileSystem) Open(name string) (http.File, error) {
+	file, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	rel := httpPath(name)
+
+	if f.hidden(rel, info.IsDir()) {
+		file.Close()
+
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if !info.IsDir() {
+		return file, nil
+	}
+
+	return &filterHTTPDir{
+		File: file,
+		fsys: f,
+		name: rel,
+	}, nil
+}
+
+// hidden reports whether the path name, relative to the root of f, is
+// ignored.
+func (f *FilterFileSystem) hidden(name string, isDir bool) bool {
+	if name == "." {
+		return false
+	}
+
+	return IsIgnored(f.matcher, name, isDir)
+}
+
+// httpPath returns the clean, slash-separated form of name, a path given to an
+// [http.FileSystem], relative to its root, or "." for the root itself.
+func httpPath(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+// filterHTTPDir is a directory opened from a FilterFileSystem. It omits hidden
+// entries when read.
+type filterHTTPDir struct {
+	http.File
+
+	fsys *FilterFileSystem
+	name string
+}
+
+// Readdir implements [http.File].
+func (d *filterHTTPDir) Readdir(count int) ([]fs.FileInfo, error) {
+	if count <= 0 {
+		infos, err := d.File.Readdir(count)
+
+		return d.filter(infos), err //nolint:wrapcheck // io.EOF must not be wrapped
+	}
+
+	filtered := make([]fs.FileInfo, 0, count)
+
+	for len(filtered) < count {
+		infos, err := d.File.Readdir(count - len(filtered))
+
+		filtered = append(filtered, d.filter(infos)...)
+
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(filtered) > 0 {
+				return filtered, nil
+			}
+
+			return filtered, err //nolint:wrapcheck // io.EOF must not be wrapped
+		}
+	}
+
+	return filtered, nil
+}
+
+// filter returns the entries of d that are not hidden.
+func (d *filterHTTPDir) filter(infos []fs.FileInfo) []fs.FileInfo {
+	filtered := make([]fs.FileInfo, 0, len(infos))
+
+	for _, info := range infos {
+		if d.fsys.hidden(path.Join(d.name, info.Name()), info.IsDir()) {
+			continue
+		}
+
+		filtered = append(filtered, info)
+	}
+
+	return filtered
+}
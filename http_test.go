@@ -0,0 +1,268 @@
+package gitignore_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestFileServer(t *testing.T) {
+	t.Parallel()
+
+	root, matcher := newServedTree(t)
+
+	testServer(t, httptest.NewServer(gitignore.FileServer(root, matcher)))
+}
+
+func TestFileServer_Symlinks(t *testing.T) {
+	t.Parallel()
+
+	root, matcher := newServedTree(t)
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+
+	if err := os.WriteFile(outside, []byte("outside"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	if err := os.Mkdir(filepath.Join(root, "public"), 0o700); err != nil {
+		t.Fatalf("failed to create test directory: %v", err)
+	}
+
+	for name, target := range map[string]string{
+		"public/env":       "../.env",
+		"public/build":     "../build",
+		"public/debug":     "../static/debug.log",
+		"public/style.css": "../static/style.css",
+		"public/outside":   outside,
+	} {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Skipf("failed to create symbolic link: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(gitignore.FileServer(root, matcher))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		givePath   string
+		wantStatus int
+	}{
+		{givePath: "/public/style.css", wantStatus: http.StatusOK},
+		{givePath: "/public/env", wantStatus: http.StatusNotFound},
+		{givePath: "/public/debug", wantStatus: http.StatusNotFound},
+		{givePath: "/public/build/", wantStatus: http.StatusNotFound},
+		{givePath: "/public/build/app.js", wantStatus: http.StatusNotFound},
+		{givePath: "/public/outside", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.givePath, func(t *testing.T) {
+			t.Parallel()
+
+			if status, _ := get(t, server, tt.givePath); status != tt.wantStatus {
+				t.Errorf("GET %s status = %d, want %d", tt.givePath, status, tt.wantStatus)
+			}
+		})
+	}
+
+	t.Run("Directory listing", func(t *testing.T) {
+		t.Parallel()
+
+		_, body := get(t, server, "/public/")
+
+		if !strings.Contains(body, "style.css") {
+			t.Errorf("GET /public/ body = %q, want it to contain %q", body, "style.css")
+		}
+
+		for _, name := range []string{">env<", ">debug<", ">build", ">outside<"} {
+			if strings.Contains(body, name) {
+				t.Errorf("GET /public/ body = %q, want it to not contain %q", body, name)
+			}
+		}
+	})
+}
+
+func TestFilterFileSystem(t *testing.T) {
+	t.Parallel()
+
+	root, matcher := newServedTree(t)
+
+	testServer(t, httptest.NewServer(http.FileServer(gitignore.NewFilterFileSystem(http.Dir(root), matcher))))
+}
+
+func TestFilterFileSystem_Readdir(t *testing.T) {
+	t.Parallel()
+
+	root, matcher := newServedTree(t)
+	fsys := gitignore.NewFilterFileSystem(http.Dir(root), matcher)
+
+	dir, err := fsys.Open("/")
+	if err != nil {
+		t.Fatalf("Open(/) unexpected error: %v", err)
+	}
+	defer dir.Close()
+
+	var got []string
+
+	for {
+		infos, err := dir.Readdir(1)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Readdir(1) unexpected error: %v", err)
+		}
+
+		if len(infos) != 1 {
+			t.Fatalf("Readdir(1) returned %d entries, want 1", len(infos))
+		}
+
+		got = append(got, infos[0].Name())
+	}
+
+	slices.Sort(got)
+
+	want := []string{"index.html", "static"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Readdir(1) entries = %v, want %v", got, want)
+	}
+
+	if _, err := fsys.Open("/static/../.env"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open(/static/../.env) error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+// newServedTree creates a tree to serve and returns its root along with a
+// matcher ignoring some of its files.
+func newServedTree(t *testing.T) (string, gitignore.Matcher) {
+	t.Helper()
+
+	root := t.TempDir()
+
+	for name, data := range map[string]string{
+		"index.html":       "<h1>Hello</h1>",
+		".env":             "SECRET=hunter2",
+		"build/app.js":     "console.log('built')",
+		"static/style.css": "body {}",
+		"static/debug.log": "debug",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("failed to create test directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	matcher, err := gitignore.NewFromLines([]string{
+		".env",
+		"build/",
+		"*.log",
+	})
+	if err != nil {
+		t.Fatalf("failed to create matcher: %v", err)
+	}
+
+	return root, matcher
+}
+
+// testServer checks that server, serving the tree created by newServedTree,
+// hides the ignored files.
+func testServer(t *testing.T, server *httptest.Server) {
+	t.Helper()
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name         string
+		givePath     string
+		wantStatus   int
+		wantContains string
+		wantMissing  string
+	}{
+		{
+			name:         "Included file",
+			givePath:     "/static/style.css",
+			wantStatus:   http.StatusOK,
+			wantContains: "body {}",
+		},
+		{
+			name:       "Ignored file",
+			givePath:   "/.env",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Ignored directory",
+			givePath:   "/build/",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "File inside ignored directory",
+			givePath:   "/build/app.js",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Directory listing",
+			givePath:     "/static/",
+			wantStatus:   http.StatusOK,
+			wantContains: "style.css",
+			wantMissing:  "debug.log",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, body := get(t, server, tt.givePath)
+			if status != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d", tt.givePath, status, tt.wantStatus)
+			}
+
+			if tt.wantContains != "" && !strings.Contains(body, tt.wantContains) {
+				t.Errorf("GET %s body = %q, want it to contain %q", tt.givePath, body, tt.wantContains)
+			}
+
+			if tt.wantMissing != "" && strings.Contains(body, tt.wantMissing) {
+				t.Errorf("GET %s body = %q, want it to not contain %q", tt.givePath, body, tt.wantMissing)
+			}
+		})
+	}
+}
+
+// get requests path from server and returns the status code and body of the
+// response.
+func get(t *testing.T, server *httptest.Server, path string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+path, http.NoBody)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET %s unexpected error: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response body: %v", err)
+	}
+
+	return resp.StatusCode, string(data)
+}
@@ -0,0 +1,189 @@
+// Package httpapi provides an embeddable [http.Handler] exposing a gitignore
+// rule set over a small JSON API.
+//
+// The handler serves three endpoints:
+//
+//	GET /match?path=a&path=b  reports whether each path is ignored.
+//	GET /explain?path=a       reports every rule matching a path and the final decision.
+//	GET /rules                lists the rules of the rule set.
+//
+// Paths are slash-separated and relative to the directory the rules apply to,
+// with a trailing slash for directories. To mount the handler below a prefix,
+// wrap it with [http.StripPrefix].
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrMissingPath is returned to clients that do not give any path to match or
+// explain.
+const ErrMissingPath xerrors.Error = "missing path query parameter"
+
+// Option configures a Handler.
+type Option func(h *Handler)
+
+// WithAuth sets a function that authorizes every request before it is served.
+// If fn returns an error, the request is answered with "401 Unauthorized" and
+// the error message.
+func WithAuth(fn func(r *http.Request) error) Option {
+	return func(h *Handler) {
+		h.auth = fn
+	}
+}
+
+// Handler serves the JSON API for a rule set.
+type Handler struct {
+	file *gitignore.File
+	auth func(r *http.Request) error
+	mux  *http.ServeMux
+}
+
+// New returns a Handler serving the rule set defined by the given lines of a
+// .gitignore file.
+func New(lines []string, opts ...Option) (*Handler, error) {
+	file, err := gitignore.NewFromLines(lines)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Already wrapped by NewFromLines.
+	}
+
+	h := &Handler{
+		file: file,
+		mux:  http.NewServeMux(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("GET /match", h.match)
+	h.mux.HandleFunc("GET /explain", h.explain)
+	h.mux.HandleFunc("GET /rules", h.listRules)
+
+	return h, nil
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.auth != nil {
+		if err := h.auth(r); err != nil {
+			writeError(w, http.StatusUnauthorized, err)
+
+			return
+		}
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// Rule is the JSON representation of a gitignore rule.
+type Rule struct {
+	Pattern string `json:"pattern"`
+	Line    int    `json:"line"`
+	Negate  bool   `json:"negate"`
+}
+
+// MatchResult is the JSON representation of the decision for a single path.
+type MatchResult struct {
+	Path    string `json:"path"`
+	Ignored bool   `json:"ignored"`
+}
+
+// MatchResponse is the response of the /match endpoint.
+type MatchResponse struct {
+	Results []MatchResult `json:"results"`
+}
+
+// ExplainResponse is the response of the /explain endpoint.
+type ExplainResponse struct {
+	Path    string `json:"path"`
+	Rules   []Rule `json:"rules"`
+	Ignored bool   `json:"ignored"`
+}
+
+// RulesResponse is the response of the /rules endpoint.
+type RulesResponse struct {
+	Rules []Rule `json:"rules"`
+}
+
+// ErrorResponse is the response sent when a request fails.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *Handler) match(w http.ResponseWriter, r *http.Request) {
+	paths := r.URL.Query()["path"]
+	if len(paths) == 0 {
+		writeError(w, http.StatusBadRequest, ErrMissingPath)
+
+		return
+	}
+
+	resp := MatchResponse{
+		Results: make([]MatchResult, 0, len(paths)),
+	}
+
+	for _, path := range paths {
+		resp.Results = append(resp.Results, MatchResult{
+			Path:    path,
+			Ignored: h.file.Match(path),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) explain(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		writeError(w, http.StatusBadRequest, ErrMissingPath)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ExplainResponse{
+		Path:    path,
+		Rules:   toRules(h.file.MatchAll(path)),
+		Ignored: h.file.Match(path),
+	})
+}
+
+func (h *Handler) listRules(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, RulesResponse{
+		Rules: toRules(h.file.Patterns()),
+	})
+}
+
+// toRules converts rules into their JSON representation.
+func toRules(rules []gitignore.PatternInfo) []Rule {
+	converted := make([]Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		converted = append(converted, Rule{
+			Pattern: rule.Raw,
+			Line:    rule.Line,
+			Negate:  rule.Negate,
+		})
+	}
+
+	return converted
+}
+
+// writeError writes err as a JSON error response with the given status code.
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, ErrorResponse{
+		Error: err.Error(),
+	})
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	json.NewEncoder(w).Encode(v) //nolint:errcheck,errchkjson // Nothing to do if the client went away.
+}
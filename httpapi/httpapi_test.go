@@ -0,0 +1,173 @@
+package httpapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/httpapi"
+)
+
+func newTestHandler(t *testing.T, opts ...httpapi.Option) *httpapi.Handler {
+	t.Helper()
+
+	handler, err := httpapi.New([]string{
+		"# Logs",
+		"*.log",
+		"!keep.log",
+		"build/",
+	}, opts...)
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	return handler
+}
+
+func serve(t *testing.T, handler http.Handler, target string, v any) int {
+	t.Helper()
+
+	var (
+		req = httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		rec = httptest.NewRecorder()
+	)
+
+	handler.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s Content-Type = %q, want %q", target, ct, "application/json")
+	}
+
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("GET %s failed to decode response: %v", target, err)
+	}
+
+	return rec.Code
+}
+
+func TestHandler_Match(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler(t)
+
+	var resp httpapi.MatchResponse
+
+	code := serve(t, handler, "/match?path=debug.log&path=keep.log&path=build/&path=main.go", &resp)
+	if code != http.StatusOK {
+		t.Fatalf("GET /match status = %d, want %d", code, http.StatusOK)
+	}
+
+	want := []httpapi.MatchResult{
+		{Path: "debug.log", Ignored: true},
+		{Path: "keep.log", Ignored: false},
+		{Path: "build/", Ignored: true},
+		{Path: "main.go", Ignored: false},
+	}
+
+	if len(resp.Results) != len(want) {
+		t.Fatalf("GET /match returned %d results, want %d", len(resp.Results), len(want))
+	}
+
+	for i := range want {
+		if resp.Results[i] != want[i] {
+			t.Errorf("GET /match result %d = %+v, want %+v", i, resp.Results[i], want[i])
+		}
+	}
+
+	var errResp httpapi.ErrorResponse
+
+	if code = serve(t, handler, "/match", &errResp); code != http.StatusBadRequest {
+		t.Errorf("GET /match without path status = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestHandler_Explain(t *testing.T) {
+	t.Parallel()
+
+	var resp httpapi.ExplainResponse
+
+	code := serve(t, newTestHandler(t), "/explain?path=keep.log", &resp)
+	if code != http.StatusOK {
+		t.Fatalf("GET /explain status = %d, want %d", code, http.StatusOK)
+	}
+
+	if resp.Ignored {
+		t.Errorf("GET /explain ignored = true, want false")
+	}
+
+	want := []httpapi.Rule{
+		{Pattern: "*.log", Line: 2},
+		{Pattern: "!keep.log", Line: 3, Negate: true},
+	}
+
+	if len(resp.Rules) != len(want) {
+		t.Fatalf("GET /explain returned %d rules, want %d", len(resp.Rules), len(want))
+	}
+
+	for i := range want {
+		if resp.Rules[i] != want[i] {
+			t.Errorf("GET /explain rule %d = %+v, want %+v", i, resp.Rules[i], want[i])
+		}
+	}
+}
+
+func TestHandler_Rules(t *testing.T) {
+	t.Parallel()
+
+	var resp httpapi.RulesResponse
+
+	code := serve(t, newTestHandler(t), "/rules", &resp)
+	if code != http.StatusOK {
+		t.Fatalf("GET /rules status = %d, want %d", code, http.StatusOK)
+	}
+
+	if len(resp.Rules) != 3 {
+		t.Errorf("GET /rules returned %d rules, want %d", len(resp.Rules), 3)
+	}
+}
+
+func TestHandler_Auth(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler(t, httpapi.WithAuth(func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			return errors.New("invalid token")
+		}
+
+		return nil
+	}))
+
+	var resp httpapi.ErrorResponse
+
+	code := serve(t, handler, "/rules", &resp)
+	if code != http.StatusUnauthorized {
+		t.Errorf("GET /rules without token status = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	if resp.Error != "invalid token" {
+		t.Errorf("GET /rules without token error = %q, want %q", resp.Error, "invalid token")
+	}
+
+	var (
+		req = httptest.NewRequest(http.MethodGet, "/rules", http.NoBody)
+		rec = httptest.NewRecorder()
+	)
+
+	req.Header.Set("Authorization", "Bearer secret")
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /rules with token status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := httpapi.New([]string{"[invalid-regex"}); err == nil {
+		t.Error("New() = nil error, want error")
+	}
+}
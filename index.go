@@ -0,0 +1,336 @@
+package gitignore
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+)
+
+// index finds the pattern deciding whether a path is ignored without
+// evaluating every pattern of a File. Literal rules, such as node_modules/ or
+// .DS_Store, and extension rules, such as *.log, which are common, are looked
+// up by the name and extensions of every component of the path instead, so
+// only the other rules coming after the last rule looked up matching the path
+// are evaluated, as the last matching rule decides. Anchored rules starting
+// with literal directories, such as /build/ or docs/_site/*.html, are kept in
+// a trie of path components, so only the ones a path starts like are
+// evaluated. Runs of the other rules that are not negated are combined into a
+// single regular expression, so a path matching none of them is rejected in a
+// single pass.
+type index struct {
+	patterns []*pattern.Pattern
+
+	// names maps the names of the literal rules to their positions, in
+	// order.
+	names map[string][]int
+
+	// suffixes maps the extensions of the extension rules, with their
+	// leading dot, to their positions, in order.
+	suffixes map[string][]int
+
+	// prefixes holds the anchored rules with a literal prefix, by the
+	// components of the prefix.
+	prefixes *trie
+
+	// rest holds the groups of the other rules, in order.
+	rest []group
+}
+
+// maxGroupSize is the largest number of rules combined into a group, which
+// keeps the combined regular expressions small enough to be fast.
+const maxGroupSize int = 16
+
+// group is a run of rules evaluated together.
+type group struct {
+	// regex matches the paths any of the rules matches, if they are several
+	// regular expression rules that are not negated, or is nil otherwise.
+	regex *regexp.Regexp
+
+	// positions holds the positions of the rules, in order.
+	positions []int
+}
+
+// newIndex returns the index of patterns, the rules of a File.
+func newIndex(patterns []*pattern.Pattern) *index {
+	x := &index{
+		patterns: patterns,
+		names:    make(map[string][]int),
+		suffixes: make(map[string][]int),
+		prefixes: newTrie(),
+		rest:     make([]group, 0),
+	}
+
+	rest := make([]int, 0, len(patterns))
+
+	for i, pat := range patterns {
+		name, isName := pat.Name()
+		suffix, isSuffix := pat.Suffix()
+
+		// Re-anchored rules also match the components of their prefix.
+		switch {
+		case pat.Prefix != "":
+			rest = append(rest, i)
+		case isName:
+			x.names[name] = append(x.names[name], i)
+		case isSuffix:
+			x.suffixes[suffix] = append(x.suffixes[suffix], i)
+		case len(pat.LiteralPrefix()) > 0:
+			x.prefixes.add(pat.LiteralPrefix(), i)
+		default:
+			rest = append(rest, i)
+		}
+	}
+
+	x.group(rest)
+
+	return x
+}
+
+// group splits rest, the positions of the rules that are not looked up, into
+// groups, combining the runs of regular expression rules that are not negated.
+func (x *index) group(rest []int) {
+	var run []int
+
+	flush := func() {
+		if len(run) > 0 {
+			x.rest = append(x.rest, newGroup(x.patterns, run))
+		}
+
+		run = nil
+	}
+
+	for _, i := range rest {
+		if !combinable(x.patterns[i]) {
+			flush()
+
+			x.rest = append(x.rest, group{positions: []int{i}})
+
+			continue
+		}
+
+		if len(run) == maxGroupSize {
+			flush()
+		}
+
+		run = append(run, i)
+	}
+
+	flush()
+}
+
+// combinable reports whether pat can be combined with other rules into a
+// single regular expression.
+func combinable(pat *pattern.Pattern) bool {
+	return pat.Regex != nil && !pat.Wildmatch && !pat.Negate && pat.Prefix == ""
+}
+
+// newGroup returns the group of the rules of patterns at positions, which are
+// combinable.
+func newGroup(patterns []*pattern.Pattern, positions []int) group {
+	g := group{positions: positions}
+
+	if len(positions) == 1 {
+		return g
+	}
+
+	// Most expressions share a leading part, matching the directories the
+	// rule may match from, which is factored out so it is matched once.
+	var (
+		prefixes = make([]string, 0)
+		bodies   = make(map[string][]string)
+	)
+
+	for _, i := range positions {
+		prefix, body := splitExpr(patterns[i].Regex.String())
+
+		if _, ok := bodies[prefix]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+
+		bodies[prefix] = append(bodies[prefix], body)
+	}
+
+	exprs := make([]string, 0, len(prefixes))
+
+	for _, prefix := range prefixes {
+		exprs = append(exprs, "(?:"+prefix+"(?:"+strings.Join(bodies[prefix], "|")+"))")
+	}
+
+	// Every expression compiles on its own, so this only fails for
+	// expressions too large, which are then evaluated one by one.
+	if regex, err := regexp.Compile(strings.Join(exprs, "|")); err == nil {
+		g.regex = regex
+	}
+
+	return g
+}
+
+// deciding returns the pattern deciding whether path, a clean path, is
+// ignored, or nil if no pattern matches it.
+func (x *index) deciding(path string) *pattern.Pattern {
+	last := x.lookup(path)
+
+	for i := len(x.rest) - 1; i >= 0; i-- {
+		g := x.rest[i]
+
+		if g.positions[len(g.positions)-1] <= last {
+			break
+		}
+
+		if g.regex != nil && !g.regex.MatchString(path) {
+			continue
+		}
+
+		// The group matches, so one of its rules does.
+		for j := len(g.positions) - 1; j >= 0 && g.positions[j] > last; j-- {
+			if pat := x.patterns[g.positions[j]]; pat.Match(path) {
+				return pat
+			}
+		}
+	}
+
+	if last < 0 {
+		return nil
+	}
+
+	return x.patterns[last]
+}
+
+// lookup returns the position of the last literal, extension or anchored rule
+// matching path, or -1 if none does.
+func (x *index) lookup(path string) int {
+	last := x.anchored(path)
+
+	if len(x.names) == 0 && len(x.suffixes) == 0 {
+		return last
+	}
+
+	for {
+		component, rest, found := strings.Cut(path, "/")
+
+		last = x.latest(x.names[component], last, found)
+
+		if len(x.suffixes) > 0 {
+			for i := range len(component) {
+				if component[i] == '.' {
+					last = x.latest(x.suffixes[component[i:]], last, found)
+				}
+			}
+		}
+
+		if !found {
+			return last
+		}
+
+		path = rest
+	}
+}
+
+// anchored returns the position of the last anchored rule of the trie
+// matching path, or -1 if none does. Only the rules whose literal prefix path
+// starts with are evaluated.
+func (x *index) anchored(path string) int {
+	last := -1
+
+	if len(x.prefixes.children) == 0 {
+		return last
+	}
+
+	// Regular expressions allow a leading slash before anchored rules.
+	rest := strings.TrimPrefix(path, "/")
+
+	for node, found := x.prefixes, true; found; {
+		var component string
+
+		component, rest, found = strings.Cut(rest, "/")
+
+		if node = node.children[component]; node == nil {
+			break
+		}
+
+		for i := len(node.positions) - 1; i >= 0 && node.positions[i] > last; i-- {
+			if x.patterns[node.positions[i]].Match(path) {
+				last = node.positions[i]
+
+				break
+			}
+		}
+	}
+
+	return last
+}
+
+// latest returns the last of positions, the positions of rules matching a
+// component of a path, if it comes after last, or last otherwise. Rules only
+// matching directories only match components followed by a slash, which dir
+// reports.
+func (x *index) latest(positions []int, last int, dir bool) int {
+	for i := len(positions) - 1; i >= 0 && positions[i] > last; i-- {
+		if dir || !x.patterns[positions[i]].DirOnly {
+			return positions[i]
+		}
+	}
+
+	return last
+}
+
+// exprPrefixes holds the leading parts of the regular expressions of rules,
+// matching the directories a rule may match from.
+//
+//nolint:gochecknoglobals // Read-only.
+var exprPrefixes = []string{"^(|.*/)", "^(|/)"}
+
+// splitExpr splits expr, the regular expression of a rule, into its leading
+// part, matching the directories the rule may match from along with its
+// flags, and the rest. Expressions made of alternatives, which rules holding a
+// "|" turn into, are not split, as the leading part only belongs to the first
+// alternative.
+func splitExpr(expr string) (string, string) {
+	if re, err := syntax.Parse(expr, syntax.Perl); err != nil || re.Op == syntax.OpAlternate {
+		return "", expr
+	}
+
+	flags := ""
+	if rest, ok := strings.CutPrefix(expr, "(?i)"); ok {
+		flags, expr = "(?i)", rest
+	}
+
+	for _, prefix := range exprPrefixes {
+		if rest, ok := strings.CutPrefix(expr, prefix); ok {
+			return flags + prefix, rest
+		}
+	}
+
+	return flags, expr
+}
+
+// trie is a node of a tree of path components, holding the positions of the
+// rules whose literal prefix leads to it, in order.
+type trie struct {
+	children  map[string]*trie
+	positions []int
+}
+
+// newTrie returns an empty trie.
+func newTrie() *trie {
+	return &trie{children: make(map[string]*trie)}
+}
+
+// add records the rule at position i, whose literal prefix is prefix.
+func (t *trie) add(prefix []string, i int) {
+	node := t
+
+	for _, component := range prefix {
+		child, ok := node.children[component]
+		if !ok {
+			child = newTrie()
+			node.children[component] = child
+		}
+
+		node = child
+	}
+
+	node.positions = append(node.positions, i)
+}
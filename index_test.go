@@ -0,0 +1,226 @@
+package gitignore_test
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestFile_Match_Literal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveLines []string
+		givePath  string
+		want      bool
+	}{
+		{name: "Name at the root", giveLines: []string{".DS_Store"}, givePath: ".DS_Store", want: true},
+		{name: "Name at any depth", giveLines: []string{".DS_Store"}, givePath: "a/b/.DS_Store", want: true},
+		{name: "Directory contents", giveLines: []string{"node_modules/"}, givePath: "a/node_modules/x/y.js", want: true},
+		{name: "Directory rule and file", giveLines: []string{"build/"}, givePath: "src/build", want: false},
+		{name: "Directory rule and directory", giveLines: []string{"build/"}, givePath: "src/build/", want: true},
+		{name: "Partial name", giveLines: []string{"build"}, givePath: "builder", want: false},
+		{name: "Later wildcard negation", giveLines: []string{"logs", "!*.keep"}, givePath: "logs/a.keep", want: false},
+		{name: "Earlier wildcard rule", giveLines: []string{"!*.keep", "logs"}, givePath: "logs/a.keep", want: true},
+		{name: "Later literal negation", giveLines: []string{"*.log", "!debug.log"}, givePath: "debug.log", want: false},
+		{name: "Later literal rule", giveLines: []string{"tmp", "!keep", "keep"}, givePath: "tmp/keep", want: true},
+		{name: "Deepest component is not the last rule", giveLines: []string{"b", "!a"}, givePath: "a/b", want: false},
+		{name: "Newline", giveLines: []string{"b"}, givePath: "a\nx/b", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := gitignore.NewFromLines(tt.giveLines)
+			if err != nil {
+				t.Fatalf("NewFromLines() error = %v", err)
+			}
+
+			if got := f.Match(tt.givePath); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFile_Match_LiteralAfterChanges(t *testing.T) {
+	t.Parallel()
+
+	f, err := gitignore.NewFromLines([]string{"vendor/", "!vendor/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	if f.Match("vendor/") {
+		t.Fatal("Match() = true, want false")
+	}
+
+	if err = f.RemoveAt(1); err != nil {
+		t.Fatalf("RemoveAt() error = %v", err)
+	}
+
+	if !f.Match("vendor/") {
+		t.Error("Match() after RemoveAt = false, want true")
+	}
+
+	if err = f.AddLines("!vendor"); err != nil {
+		t.Fatalf("AddLines() error = %v", err)
+	}
+
+	if f.Match("vendor/") {
+		t.Error("Match() after AddLines = true, want false")
+	}
+
+	merged := gitignore.Merge(f, f.WithPrefix("vendor"))
+	if merged.Match("vendor/") {
+		t.Error("Match() of merged = true, want false")
+	}
+}
+
+func TestFile_Match_Extension(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveLines []string
+		givePath  string
+		want      bool
+	}{
+		{name: "Extension", giveLines: []string{"*.log"}, givePath: "a/debug.log", want: true},
+		{name: "Hidden file", giveLines: []string{"*.log"}, givePath: ".log", want: true},
+		{name: "Other extension", giveLines: []string{"*.log"}, givePath: "debug.logs", want: false},
+		{name: "Double extension", giveLines: []string{"*.tar.gz"}, givePath: "dist/a.tar.gz", want: true},
+		{name: "Last extension only", giveLines: []string{"*.tar.gz"}, givePath: "a.gz", want: false},
+		{name: "Directory contents", giveLines: []string{"*.egg-info/"}, givePath: "a.egg-info/PKG-INFO", want: true},
+		{name: "Directory rule and file", giveLines: []string{"*.egg-info/"}, givePath: "a.egg-info", want: false},
+		{name: "Later literal negation", giveLines: []string{"*.log", "!keep.log"}, givePath: "keep.log", want: false},
+		{name: "Later extension rule", giveLines: []string{"!keep.log", "*.log"}, givePath: "keep.log", want: true},
+		{name: "Later wildcard negation", giveLines: []string{"*.log", "!logs/**"}, givePath: "logs/a.log", want: false},
+		{name: "Several extensions", giveLines: []string{"*.gz", "!*.tar.gz"}, givePath: "a.tar.gz", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := gitignore.NewFromLines(tt.giveLines)
+			if err != nil {
+				t.Fatalf("NewFromLines() error = %v", err)
+			}
+
+			if got := f.Match(tt.givePath); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFile_MatchResult_Combined(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"/dist-*",
+		"src/**/gen",
+		"*.py[co]",
+		"!src/**/gen",
+		"tmp-*",
+		"cache-?",
+		"q|z",
+		"out/*.o",
+	}
+
+	f, err := gitignore.NewFromLines(lines)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath    string
+		wantRaw     string
+		wantIgnored bool
+		wantMatch   bool
+	}{
+		{givePath: "dist-1/a.js", wantRaw: "/dist-*", wantIgnored: true, wantMatch: true},
+		{givePath: "src/a/gen", wantRaw: "!src/**/gen", wantIgnored: false, wantMatch: true},
+		{givePath: "src/a/gen/tmp-1", wantRaw: "tmp-*", wantIgnored: true, wantMatch: true},
+		{givePath: "m.pyc", wantRaw: "*.py[co]", wantIgnored: true, wantMatch: true},
+		{givePath: "xz", wantRaw: "q|z", wantIgnored: true, wantMatch: true},
+		{givePath: "out/main.o", wantRaw: "out/*.o", wantIgnored: true, wantMatch: true},
+		{givePath: "main.go", wantMatch: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := f.MatchResult(tt.givePath)
+		if ok != tt.wantMatch {
+			t.Errorf("MatchResult(%q) ok = %v, want %v", tt.givePath, ok, tt.wantMatch)
+
+			continue
+		}
+
+		if got.Pattern.Raw != tt.wantRaw || got.Ignored != tt.wantIgnored {
+			t.Errorf("MatchResult(%q) = %q, %v, want %q, %v",
+				tt.givePath, got.Pattern.Raw, got.Ignored, tt.wantRaw, tt.wantIgnored)
+		}
+	}
+}
+
+// TestFile_MatchResult_Index checks that the rule deciding whether a path is
+// ignored, which is looked up through the index of the File, is the last one
+// matching it, which MatchAll evaluates one by one, and that a DirMemo of the
+// File agrees with it.
+func TestFile_MatchResult_Index(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rules = []string{
+			"build", "build/", "/build", "/build/", "!build", "*.log", "*.log/", "!*.log",
+			"docs/_site", "/docs/_site/", "docs/*.md", "!docs/*.md", "/docs/**/x", "/docs/a*",
+			"x*", "!x*", "**/x", "a/**", "/a/b.log", "*.tar.gz", "a", "!a/",
+		}
+		components = []string{"build", "docs", "_site", "a", "b.log", "x", "x.tar.gz", "r.md"}
+		rng        = rand.New(rand.NewPCG(1, 2)) //nolint:gosec // Deterministic test data.
+	)
+
+	for _, opts := range [][]gitignore.Option{nil, {gitignore.WithWildmatch()}} {
+		for range 500 {
+			lines := make([]string, 1+rng.IntN(8))
+			for i := range lines {
+				lines[i] = rules[rng.IntN(len(rules))]
+			}
+
+			f, err := gitignore.NewFromLines(lines, opts...)
+			if err != nil {
+				t.Fatalf("NewFromLines(%q) error = %v", lines, err)
+			}
+
+			memo := gitignore.NewDirMemo(f)
+
+			for range 20 {
+				parts := make([]string, 1+rng.IntN(4))
+				for i := range parts {
+					parts[i] = components[rng.IntN(len(components))]
+				}
+
+				path := strings.Join(parts, "/")
+				if rng.IntN(2) == 0 {
+					path += "/"
+				}
+
+				all := f.MatchAll(path)
+				got, ok := f.MatchResult(path)
+
+				if ok != (len(all) > 0) || ok && got.Pattern != all[len(all)-1] {
+					t.Fatalf("rules %q: MatchResult(%q) = %+v, %v, want the last of %+v", lines, path, got, ok, all)
+				}
+
+				if got, want := memo.Match(path), f.Match(path); got != want {
+					t.Fatalf("rules %q: DirMemo.Match(%q) = %v, want %v", lines, path, got, want)
+				}
+			}
+		}
+	}
+}
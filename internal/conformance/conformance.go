@@ -0,0 +1,183 @@
+// Package conformance runs the git binary against generated trees, so the
+// decisions of this module can be compared with the ones of git itself.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrUnexpectedOutput is returned when the output of git check-ignore cannot be
+// parsed.
+const ErrUnexpectedOutput xerrors.Error = "unexpected git check-ignore output"
+
+// Case is a rule file and the paths to check against it.
+type Case struct {
+	// Name identifies the case.
+	Name string
+
+	// Rules holds the lines of the root .gitignore file.
+	Rules []string
+
+	// Files holds the lines of additional files, such as nested .gitignore
+	// files, .git/info/exclude, or .git/config, keyed by their slash-separated
+	// path relative to the root of the tree.
+	Files map[string][]string
+
+	// Paths holds the slash-separated paths to check, relative to the root of
+	// the tree, with a trailing slash for directories.
+	Paths []string
+}
+
+// Result is the decision of git for a single path.
+type Result struct {
+	// Path is the path as given in the case.
+	Path string
+
+	// Source is the path of the file Pattern was read from, as reported by
+	// git, or empty if no pattern matched.
+	Source string
+
+	// Pattern is the last pattern matching the path, or empty if none did.
+	Pattern string
+
+	// Line is the 1-based line number of Pattern in its source file.
+	Line int
+
+	// Ignored reports whether git ignores the path.
+	Ignored bool
+}
+
+// Materialize creates the tree described by c inside dir: a git repository
+// with the rules of c in its root .gitignore file, the additional files of c,
+// and an empty file or directory for every path of c.
+func Materialize(ctx context.Context, dir string, c Case) error {
+	if _, err := git(ctx, dir, nil, "init", "--quiet"); err != nil {
+		return err
+	}
+
+	data := []byte(strings.Join(c.Rules, "\n") + "\n")
+
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), data, 0o600); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	for _, p := range c.Paths {
+		target := filepath.Join(dir, filepath.FromSlash(p))
+
+		if strings.HasSuffix(p, "/") {
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if err := os.WriteFile(target, nil, 0o600); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	for name, lines := range c.Files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if err := os.WriteFile(target, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	return nil
+}
+
+// CheckIgnore runs git check-ignore --verbose on the given paths of the
+// repository in dir and returns the decision of git for each of them, in
+// order. Paths must exist, as created by Materialize.
+func CheckIgnore(ctx context.Context, dir string, paths []string) ([]Result, error) {
+	// Directories are given without their trailing slash, as git finds out
+	// they are directories from the file system, and would otherwise match
+	// patterns such as "dir/*" against the directory itself.
+	trimmed := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		trimmed = append(trimmed, strings.TrimSuffix(p, "/"))
+	}
+
+	stdin := []byte(strings.Join(trimmed, "\x00") + "\x00")
+
+	out, err := git(ctx, dir, stdin, "check-ignore", "--verbose", "--non-matching", "--no-index", "--stdin", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each record is made of four NUL-terminated fields: source, line number,
+	// pattern, and path. Fields are empty for paths matching no pattern.
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	if len(fields) != 4*len(paths) {
+		return nil, fmt.Errorf("%w: got %d fields for %d paths", ErrUnexpectedOutput, len(fields), len(paths))
+	}
+
+	results := make([]Result, 0, len(paths))
+
+	for i := 0; i < len(fields); i += 4 {
+		result := Result{
+			Path:    paths[i/4],
+			Source:  fields[i],
+			Pattern: fields[i+2],
+		}
+
+		if fields[i+1] != "" {
+			result.Line, err = strconv.Atoi(fields[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrUnexpectedOutput, err)
+			}
+		}
+
+		result.Ignored = result.Pattern != "" && !strings.HasPrefix(result.Pattern, "!")
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// git runs the git binary with the given arguments in dir, isolated from the
+// configuration of the user running it.
+func git(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"HOME="+dir,
+		"XDG_CONFIG_HOME="+dir,
+	)
+
+	// git check-ignore exits with 1 when no path is ignored, which is not an
+	// error with --non-matching.
+	if err := cmd.Run(); err != nil && cmd.ProcessState.ExitCode() != 1 {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,246 @@
+package differential_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/property"
+	gogitignore "github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	sabhiram "github.com/sabhiram/go-gitignore"
+)
+
+// propertyCases is the number of generated cases added to the corpus.
+const propertyCases = 2000
+
+// decision is the decision of a matcher for a path, with the rules it was
+// made by when the matcher reports them.
+type decision struct {
+	provenance string
+	ignored    bool
+}
+
+// library is a gitignore matcher being compared.
+type library struct {
+	compile func(rules []string) (func(path string, isDir bool) decision, error)
+	name    string
+}
+
+// sample is a rule set, a path, and the decision git makes for it.
+type sample struct {
+	source string
+	rules  []string
+	path   string
+	isDir  bool
+	want   bool
+}
+
+func TestDifferential(t *testing.T) {
+	t.Parallel()
+
+	var (
+		samples   = append(corpusSamples(t), propertySamples()...)
+		libraries = []library{
+			{name: "gitignore-go", compile: compileGitignoreGo},
+			{name: "gitignore-go (wildmatch)", compile: compileWildmatch},
+			{name: "go-git", compile: compileGoGit},
+			{name: "sabhiram", compile: compileSabhiram},
+		}
+	)
+
+	for _, lib := range libraries {
+		var (
+			agreed   int
+			compiled = make(map[string]func(string, bool) decision)
+		)
+
+		for _, s := range samples {
+			key := strings.Join(s.rules, "\n")
+
+			match, ok := compiled[key]
+			if !ok {
+				var err error
+
+				match, err = lib.compile(s.rules)
+				if err != nil {
+					t.Logf("%s: %s: failed to compile rules: %v", lib.name, s.source, err)
+
+					continue
+				}
+
+				compiled[key] = match
+			}
+
+			got := match(s.path, s.isDir)
+			if got.ignored == s.want {
+				agreed++
+
+				continue
+			}
+
+			t.Logf("%s: %s: %s: ignored = %t, git ignored = %t%s",
+				lib.name, s.source, displayPath(s.path, s.isDir), got.ignored, s.want, got.provenance)
+		}
+
+		t.Logf("%s agrees with git on %d of %d paths (%.1f%%)",
+			lib.name, agreed, len(samples), 100*float64(agreed)/float64(len(samples)))
+	}
+}
+
+// corpusSamples returns the paths of the golden corpus of this module, with
+// the decisions recorded from git.
+func corpusSamples(t *testing.T) []sample {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "testdata", "corpus", "*.gitignore"))
+	if err != nil {
+		t.Fatalf("failed to list corpus: %v", err)
+	}
+
+	samples := make([]sample, 0)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read rules: %v", err)
+		}
+
+		rules := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+		golden, err := os.Open(strings.TrimSuffix(file, ".gitignore") + ".golden")
+		if err != nil {
+			t.Fatalf("failed to open golden file: %v", err)
+		}
+
+		scanner := bufio.NewScanner(golden)
+
+		for scanner.Scan() {
+			decision, path, ok := strings.Cut(scanner.Text(), "\t")
+			if !ok || strings.HasPrefix(decision, "#") {
+				continue
+			}
+
+			samples = append(samples, sample{
+				source: "corpus " + filepath.Base(file),
+				rules:  rules,
+				path:   strings.TrimSuffix(path, "/"),
+				isDir:  strings.HasSuffix(path, "/"),
+				want:   decision == "ignored",
+			})
+		}
+
+		golden.Close()
+
+		if err = scanner.Err(); err != nil {
+			t.Fatalf("failed to read golden file: %v", err)
+		}
+	}
+
+	return samples
+}
+
+// propertySamples returns generated cases whose decision is known by
+// construction, using a fixed seed so reports are comparable between runs.
+func propertySamples() []sample {
+	var (
+		gen     = property.NewGenerator(1)
+		samples = make([]sample, 0, propertyCases)
+	)
+
+	for range propertyCases {
+		c := gen.Next()
+
+		samples = append(samples, sample{
+			source: fmt.Sprintf("generated %s %q", c.Kind, c.Rules),
+			rules:  c.Rules,
+			path:   c.Path,
+			isDir:  c.IsDir,
+			want:   c.Ignored,
+		})
+	}
+
+	return samples
+}
+
+func compileGitignoreGo(rules []string) (func(string, bool) decision, error) {
+	matcher, err := gitignore.NewFromLines(rules)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Reported as is.
+	}
+
+	return func(path string, isDir bool) decision {
+		d := decision{ignored: gitignore.IsIgnored(matcher, path, isDir)}
+
+		matching := matcher.MatchAll(displayPath(path, isDir))
+		if len(matching) == 0 {
+			return d
+		}
+
+		lines := make([]string, 0, len(matching))
+
+		for _, rule := range matching {
+			lines = append(lines, fmt.Sprintf("line %d %q", rule.Line, rule.Raw))
+		}
+
+		d.provenance = " (matching " + strings.Join(lines, ", ") + ")"
+
+		return d
+	}, nil
+}
+
+func compileWildmatch(rules []string) (func(string, bool) decision, error) {
+	matcher, err := gitignore.NewFromLines(rules, gitignore.WithWildmatch())
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Reported as is.
+	}
+
+	return func(path string, isDir bool) decision {
+		return decision{ignored: gitignore.IsIgnored(matcher, path, isDir)}
+	}, nil
+}
+
+func compileGoGit(rules []string) (func(string, bool) decision, error) {
+	patterns := make([]gogitignore.Pattern, 0, len(rules))
+
+	for _, rule := range rules {
+		if strings.HasPrefix(rule, "#") || strings.TrimSpace(rule) == "" {
+			continue
+		}
+
+		patterns = append(patterns, gogitignore.ParsePattern(rule, nil))
+	}
+
+	matcher := gogitignore.NewMatcher(patterns)
+
+	return func(path string, isDir bool) decision {
+		return decision{ignored: matcher.Match(strings.Split(path, "/"), isDir)}
+	}, nil
+}
+
+func compileSabhiram(rules []string) (func(string, bool) decision, error) {
+	matcher := sabhiram.CompileIgnoreLines(rules...)
+
+	return func(path string, isDir bool) decision {
+		ignored, how := matcher.MatchesPathHow(displayPath(path, isDir))
+
+		d := decision{ignored: ignored}
+		if how != nil {
+			d.provenance = fmt.Sprintf(" (line %d %q)", how.LineNo, how.Line)
+		}
+
+		return d
+	}, nil
+}
+
+// displayPath returns path with a trailing slash if it is a directory.
+func displayPath(path string, isDir bool) string {
+	if isDir {
+		return path + "/"
+	}
+
+	return path
+}
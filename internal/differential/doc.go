@@ -0,0 +1,9 @@
+// Package differential compares the decisions of this module with the ones of
+// other Go gitignore matchers, to quantify compatibility for users migrating
+// from them.
+//
+// It lives in its own module, so the matchers it compares against are not
+// dependencies of this module. Run it from this directory with:
+//
+//	go test -v ./...
+package differential
@@ -0,0 +1,22 @@
+module git.sr.ht/~jamesponddotco/gitignore-go/internal/differential
+
+go 1.23.0
+
+require (
+	git.sr.ht/~jamesponddotco/gitignore-go v0.0.0
+	github.com/go-git/go-git/v5 v5.16.2
+	github.com/sabhiram/go-gitignore v0.0.0-20210923224102-525f6e181f06
+)
+
+require (
+	git.sr.ht/~jamesponddotco/xstd-go v0.9.0 // indirect
+	github.com/fsnotify/fsnotify v1.10.1 // indirect
+	github.com/go-git/gcfg v1.5.1-0.20230307220236-3a3c6141e376 // indirect
+	github.com/go-git/go-billy/v5 v5.6.2 // indirect
+	github.com/jbenet/go-context v0.0.0-20150711004518-d14ea06fba99 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	gopkg.in/warnings.v0 v0.1.2 // indirect
+)
+
+replace git.sr.ht/~jamesponddotco/gitignore-go => ../..
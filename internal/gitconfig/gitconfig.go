@@ -0,0 +1,278 @@
+// Package gitconfig reads the subset of the git configuration file format
+// needed to honor the settings that affect ignore rules.
+package gitconfig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+const (
+	// ErrSyntax is returned when a configuration file is malformed.
+	ErrSyntax xerrors.Error = "invalid git configuration"
+
+	// ErrReadingFile is returned when a configuration file cannot be read.
+	ErrReadingFile xerrors.Error = "failed to read git configuration"
+)
+
+// Config holds the values of a configuration file, keyed by their fully
+// qualified, normalized name, such as "core.excludesfile".
+type Config map[string][]string
+
+// Get returns the last value of key, which takes precedence over the previous
+// ones, and whether it is set. The section and variable names of key are case
+// insensitive.
+func (c Config) Get(key string) (string, bool) {
+	values := c[normalize(key)]
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[len(values)-1], true
+}
+
+// Bool returns the boolean value of key, which is false if unset. Like git,
+// "true", "yes", "on" and "1" are true, while "false", "no", "off", "0" and
+// the empty string are false, regardless of case.
+func (c Config) Bool(key string) (bool, error) {
+	value, ok := c.Get(key)
+	if !ok {
+		return false, nil
+	}
+
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0", "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: invalid boolean value %q for %s", ErrSyntax, value, key)
+	}
+}
+
+// Merge returns a Config holding the values of c followed by the ones of
+// other, so the values of other take precedence.
+func (c Config) Merge(other Config) Config {
+	merged := make(Config, len(c)+len(other))
+
+	for key, values := range c {
+		merged[key] = append(merged[key], values...)
+	}
+
+	for key, values := range other {
+		merged[key] = append(merged[key], values...)
+	}
+
+	return merged
+}
+
+// ReadFile parses the configuration file at path. A missing file is not an
+// error and yields an empty Config.
+func ReadFile(path string) (Config, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingFile, err)
+	}
+	defer file.Close()
+
+	config, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Parse parses a git configuration file. Includes are not followed.
+func Parse(r io.Reader) (Config, error) {
+	var (
+		config     = make(Config)
+		section    string
+		lineNumber int
+		scanner    = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+
+		// A trailing backslash continues the line.
+		for strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) && scanner.Scan() {
+			lineNumber++
+			line = line[:len(line)-1] + scanner.Text()
+		}
+
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name, rest, err := parseSection(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			section = name
+
+			// A variable may follow the section header on the same line.
+			if line = strings.TrimSpace(rest); line == "" || line[0] == '#' || line[0] == ';' {
+				continue
+			}
+		}
+
+		if section == "" {
+			return nil, fmt.Errorf("%w: line %d: variable outside of a section", ErrSyntax, lineNumber)
+		}
+
+		name, value, err := parseVariable(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		key := section + "." + name
+		config[key] = append(config[key], value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingFile, err)
+	}
+
+	return config, nil
+}
+
+// parseSection parses a section header such as `[core]` or `[remote "origin"]`
+// at the start of line, returning its normalized name and the rest of the line.
+func parseSection(line string) (string, string, error) {
+	end := strings.IndexByte(line, ']')
+	if end < 0 {
+		return "", "", fmt.Errorf("%w: unterminated section header %q", ErrSyntax, line)
+	}
+
+	header := strings.TrimSpace(line[1:end])
+
+	name, sub, found := strings.Cut(header, " ")
+	if !found {
+		// The deprecated [section.subsection] syntax is case insensitive.
+		return strings.ToLower(header), line[end+1:], nil
+	}
+
+	sub = strings.TrimSpace(sub)
+	if len(sub) < 2 || sub[0] != '"' || sub[len(sub)-1] != '"' {
+		return "", "", fmt.Errorf("%w: invalid subsection in %q", ErrSyntax, line)
+	}
+
+	// Subsection names are case sensitive.
+	return strings.ToLower(name) + "." + strings.ReplaceAll(sub[1:len(sub)-1], `\"`, `"`), line[end+1:], nil
+}
+
+// parseVariable parses a `name = value` line, returning the normalized name
+// and the unquoted value. A name without a value is a true boolean.
+func parseVariable(line string) (string, string, error) {
+	name, value, found := strings.Cut(line, "=")
+
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t\"") {
+		return "", "", fmt.Errorf("%w: invalid variable %q", ErrSyntax, line)
+	}
+
+	if !found {
+		if i := strings.IndexAny(name, "#;"); i >= 0 {
+			name = strings.TrimSpace(name[:i])
+		}
+
+		return strings.ToLower(name), "true", nil
+	}
+
+	value, err := parseValue(value)
+	if err != nil {
+		return "", "", err
+	}
+
+	return strings.ToLower(name), value, nil
+}
+
+// parseValue unquotes value, removing comments and the spaces surrounding it,
+// and interpreting escape sequences.
+func parseValue(value string) (string, error) {
+	var (
+		b      strings.Builder
+		quoted bool
+		spaces int
+	)
+
+	value = strings.TrimSpace(value)
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case c == '"':
+			b.WriteString(strings.Repeat(" ", spaces))
+			spaces = 0
+
+			quoted = !quoted
+		case c == '\\':
+			if i == len(value)-1 {
+				return "", fmt.Errorf("%w: incomplete escape sequence in %q", ErrSyntax, value)
+			}
+
+			i++
+
+			switch value[i] {
+			case 'n':
+				c = '\n'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case '"', '\\':
+				c = value[i]
+			default:
+				return "", fmt.Errorf("%w: invalid escape sequence in %q", ErrSyntax, value)
+			}
+
+			b.WriteString(strings.Repeat(" ", spaces))
+			spaces = 0
+			b.WriteByte(c)
+		case !quoted && (c == '#' || c == ';'):
+			return b.String(), nil
+		case !quoted && (c == ' ' || c == '\t'):
+			// Like git, inner blanks are kept as spaces, while trailing
+			// ones are dropped.
+			spaces++
+		default:
+			b.WriteString(strings.Repeat(" ", spaces))
+			spaces = 0
+			b.WriteByte(c)
+		}
+	}
+
+	if quoted {
+		return "", fmt.Errorf("%w: unterminated quote in %q", ErrSyntax, value)
+	}
+
+	return b.String(), nil
+}
+
+// normalize returns key with its section and variable names lowercased,
+// keeping the case of its subsection, if any.
+func normalize(key string) string {
+	first := strings.IndexByte(key, '.')
+	last := strings.LastIndexByte(key, '.')
+
+	if first < 0 {
+		return strings.ToLower(key)
+	}
+
+	return strings.ToLower(key[:first]) + key[first:last] + strings.ToLower(key[last:])
+}
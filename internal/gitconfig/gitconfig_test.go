@@ -0,0 +1,169 @@
+package gitconfig_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/gitconfig"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	const config = `# Global configuration.
+[user]
+	name = Jane Doe ; a comment
+[core]
+	excludesFile = ~/.gitignore_global
+	ignoreCase
+	bare = false # another comment
+[Core]
+	ExcludesFile = "/path with spaces/ignore"
+[remote "Origin"]
+	url = https://example.com/repo.git
+[alias]
+	quoted = "say \"hi\"" \
+	and more
+[section.Sub] key = inline
+`
+
+	tests := []struct {
+		giveKey   string
+		wantValue string
+		wantOK    bool
+	}{
+		{giveKey: "user.name", wantValue: "Jane Doe", wantOK: true},
+		{giveKey: "core.excludesfile", wantValue: "/path with spaces/ignore", wantOK: true},
+		{giveKey: "CORE.EXCLUDESFILE", wantValue: "/path with spaces/ignore", wantOK: true},
+		{giveKey: "core.ignorecase", wantValue: "true", wantOK: true},
+		{giveKey: "core.bare", wantValue: "false", wantOK: true},
+		{giveKey: "remote.Origin.url", wantValue: "https://example.com/repo.git", wantOK: true},
+		{giveKey: "remote.origin.url", wantValue: "", wantOK: false},
+		{giveKey: "alias.quoted", wantValue: `say "hi"  and more`, wantOK: true},
+		{giveKey: "section.sub.key", wantValue: "inline", wantOK: true},
+		{giveKey: "core.missing", wantValue: "", wantOK: false},
+	}
+
+	parsed, err := gitconfig.Parse(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.giveKey, func(t *testing.T) {
+			t.Parallel()
+
+			value, ok := parsed.Get(tt.giveKey)
+			if value != tt.wantValue || ok != tt.wantOK {
+				t.Errorf("Get(%q) = %q, %v, want %q, %v", tt.giveKey, value, ok, tt.wantValue, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		giveConfig string
+	}{
+		{name: "Unterminated section", giveConfig: "[core\n"},
+		{name: "Invalid subsection", giveConfig: "[remote origin]\n"},
+		{name: "Variable outside section", giveConfig: "key = value\n"},
+		{name: "Unterminated quote", giveConfig: "[core]\nkey = \"value\n"},
+		{name: "Invalid escape", giveConfig: "[core]\nkey = \\x\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := gitconfig.Parse(strings.NewReader(tt.giveConfig)); !errors.Is(err, gitconfig.ErrSyntax) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.giveConfig, err, gitconfig.ErrSyntax)
+			}
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	config, err := gitconfig.ReadFile(filepath.Join(dir, "missing"))
+	if err != nil || len(config) != 0 {
+		t.Errorf("ReadFile() = %v, %v, want an empty config", config, err)
+	}
+
+	global := filepath.Join(dir, "global")
+	if err = os.WriteFile(global, []byte("[core]\nexcludesFile = global\nbare = true\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	local := filepath.Join(dir, "local")
+	if err = os.WriteFile(local, []byte("[core]\nexcludesFile = local\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	globalConfig, err := gitconfig.ReadFile(global)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) error = %v", global, err)
+	}
+
+	localConfig, err := gitconfig.ReadFile(local)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) error = %v", local, err)
+	}
+
+	merged := globalConfig.Merge(localConfig)
+
+	if value, _ := merged.Get("core.excludesFile"); value != "local" {
+		t.Errorf("Get(core.excludesFile) = %q, want %q", value, "local")
+	}
+
+	if value, _ := merged.Get("core.bare"); value != "true" {
+		t.Errorf("Get(core.bare) = %q, want %q", value, "true")
+	}
+}
+
+func TestConfig_Bool(t *testing.T) {
+	t.Parallel()
+
+	config, err := gitconfig.Parse(strings.NewReader("[core]\n\tyes = Yes\n\tflag\n\toff = off\n\tzero = 0\n\tempty =\n\tbad = maybe\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		giveKey string
+		want    bool
+		wantErr bool
+	}{
+		{giveKey: "core.yes", want: true},
+		{giveKey: "core.flag", want: true},
+		{giveKey: "core.off", want: false},
+		{giveKey: "core.zero", want: false},
+		{giveKey: "core.empty", want: false},
+		{giveKey: "core.unset", want: false},
+		{giveKey: "core.bad", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := config.Bool(tt.giveKey)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Bool(%q) error = %v, want error %v", tt.giveKey, err, tt.wantErr)
+		}
+
+		if tt.wantErr && !errors.Is(err, gitconfig.ErrSyntax) {
+			t.Errorf("Bool(%q) error = %v, want %v", tt.giveKey, err, gitconfig.ErrSyntax)
+		}
+
+		if got != tt.want {
+			t.Errorf("Bool(%q) = %v, want %v", tt.giveKey, got, tt.want)
+		}
+	}
+}
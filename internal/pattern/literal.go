@@ -0,0 +1,104 @@
+package pattern
+
+import "strings"
+
+// specialChars holds the characters that make a rule more than a literal name
+// for either engine, including the ones the regular expression engine passes
+// through to the expression.
+const specialChars string = `\*?[]{}()+|^$`
+
+// Name returns the name of the files and directories p matches, along with
+// the contents of the directories, at any depth, and true, if p is a literal
+// rule without wildcards nor slashes other than a trailing one, such as
+// node_modules/ or .DS_Store. Such rules only match the paths with a
+// component of that name, followed by a slash if p is DirOnly, so callers can
+// look them up rather than evaluating them. Case-insensitive rules are never
+// literal.
+func (p *Pattern) Name() (string, bool) {
+	return p.name, p.name != ""
+}
+
+// Suffix returns the extension of the files and directories p matches, along
+// with the contents of the directories, at any depth, and true, if p is a rule
+// made of a single "*" followed by a literal extension, such as *.log or
+// *.tar.gz. Like with Name, such rules only match the paths with a component
+// ending with the extension, which starts with a dot, so callers can look them
+// up by the extensions of a path rather than evaluating them.
+func (p *Pattern) Suffix() (string, bool) {
+	return p.suffix, p.suffix != ""
+}
+
+// LiteralPrefix returns the leading components every path p matches starts
+// with, ignoring a leading slash, if p is anchored, such as build and docs for
+// /build/ and docs/_site/*.html, or nil if there are none. Callers can skip p
+// for the paths starting otherwise without evaluating it. Case-insensitive
+// rules have no literal prefix.
+func (p *Pattern) LiteralPrefix() []string {
+	return p.prefix
+}
+
+// literalPrefix returns the leading components of line, an anchored rule
+// without its leading "!", that are literal.
+func literalPrefix(line string) []string {
+	line = strings.TrimSuffix(line, "/")
+
+	// Like for names, escaped characters are not literal, and neither is the
+	// "!" the regular expression engine would strip.
+	if line == "" || line[0] == '#' || line[0] == '!' {
+		return nil
+	}
+
+	var prefix []string
+
+	for _, component := range strings.Split(strings.TrimPrefix(line, "/"), "/") {
+		if component == "" || strings.ContainsAny(component, specialChars) {
+			break
+		}
+
+		prefix = append(prefix, component)
+	}
+
+	return prefix
+}
+
+// literal returns the name line, a rule without its leading "!", matches at any
+// depth, or the extension it matches if it is a "*" followed by an extension.
+// Both are empty if line is neither.
+func literal(line string) (string, string) {
+	name := strings.TrimSuffix(line, "/")
+
+	if suffix, ok := strings.CutPrefix(name, "*"); ok {
+		if !strings.HasPrefix(suffix, ".") || strings.ContainsAny(suffix, "/"+specialChars) {
+			return "", ""
+		}
+
+		return "", suffix
+	}
+
+	if name == "" || name[0] == '#' || name[0] == '!' || strings.ContainsAny(name, "/"+specialChars) {
+		return "", ""
+	}
+
+	return name, ""
+}
+
+// matchComponent reports whether path, a slash-separated path, has a component
+// named like the literal pattern p, or ending with its extension, followed by a
+// slash if p is DirOnly.
+func (p *Pattern) matchComponent(path string) bool {
+	for {
+		component, rest, found := strings.Cut(path, "/")
+
+		if found || !p.DirOnly {
+			if p.name != "" && component == p.name || p.suffix != "" && strings.HasSuffix(component, p.suffix) {
+				return true
+			}
+		}
+
+		if !found {
+			return false
+		}
+
+		path = rest
+	}
+}
@@ -3,9 +3,12 @@ package pattern
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
+	"regexp/syntax"
 	"strings"
 
 	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
@@ -16,54 +19,352 @@ import (
 // .gitignore files without over-allocating.
 const defaultPatternCapacity int = 20
 
+// maxLineLength is the length of the longest line the parser accepts, unless
+// set otherwise with Parser.MaxLineLength.
+const maxLineLength int = bufio.MaxScanTokenSize
+
+// caseInsensitive is the flag prepended to the regular expressions of patterns
+// matching paths regardless of case.
+const caseInsensitive string = "(?i)"
+
+// subdirGlob matches the rules naming files by extension inside a directory,
+// such as foo/*.blah, which are anchored. It is compiled once, rather than for
+// every line parsed.
+var subdirGlob = regexp.MustCompile(`([^/+])/.*\*\.`) //nolint:gochecknoglobals // Read-only, compiled once.
+
 const (
 	// ErrInvalidRegex is returned when a regular expression fails to compile.
 	ErrInvalidRegex xerrors.Error = "invalid regex"
 
 	// ErrScanningFile is returned when scanning a file fails for any reason.
 	ErrScanningFile xerrors.Error = "failed to scan file"
+
+	// ErrLineTooLong is returned for a line longer than Parser.MaxLineLength.
+	ErrLineTooLong xerrors.Error = "line too long"
+
+	// ErrTooManyPatterns is returned when a file holds more patterns than
+	// Parser.MaxPatterns.
+	ErrTooManyPatterns xerrors.Error = "too many patterns"
+
+	// ErrRegexTooLarge is returned when the regular expressions of a file are
+	// larger than Parser.MaxRegexSize in total.
+	ErrRegexTooLarge xerrors.Error = "regular expressions too large"
 )
 
+// Error describes a line of a .gitignore file that could not be parsed.
+type Error struct {
+	// Err is the underlying cause.
+	Err error
+
+	// Text is the offending line, as read.
+	Text string
+
+	// Pattern is the offending rule, without surrounding spaces.
+	Pattern string
+
+	// Line is the 1-based line number of the offending line.
+	Line int
+
+	// Column is the 1-based column of the offending part of the line, or of
+	// the start of the pattern when it cannot be located.
+	Column int
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %q: %v", e.Line, e.Column, e.Text, e.Err)
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 // Pattern represents a parsed gitignore pattern.
 type Pattern struct {
 	// Regex is the compiled regular expression for this pattern.
 	Regex *regexp.Regexp
 
+	// Raw is the line the pattern was parsed from, without surrounding
+	// spaces.
+	Raw string
+
+	// Text is the line the pattern was parsed from, as read, without its line
+	// ending.
+	Text string
+
+	// Source is the name of the file the pattern was parsed from, or empty if
+	// unknown.
+	Source string
+
+	// Line is the 1-based line number the pattern was parsed from.
+	Line int
+
+	// Layer is the index of the rule set the pattern belongs to when several
+	// are merged. Patterns of later layers take precedence.
+	Layer int
+
 	// Negate indicates whether the pattern should be negated.
 	Negate bool
+
+	// Anchored indicates whether the pattern only matches relative to the
+	// directory of the .gitignore file.
+	Anchored bool
+
+	// DirOnly indicates whether the pattern only matches directories.
+	DirOnly bool
+
+	// IgnoreCase indicates whether the pattern matches paths regardless of
+	// case.
+	IgnoreCase bool
+
+	// Prefix is the slash-separated path of the directory the pattern is
+	// matched from, relative to the one paths are given relative to, or empty
+	// if they are the same. It is set for patterns of an ancestor directory
+	// re-anchored to a subdirectory.
+	Prefix string
+
+	// Wildmatch indicates whether the pattern matches paths with git's
+	// wildmatch algorithm, in which case Regex is nil.
+	Wildmatch bool
+
+	// glob is the pattern matched with wildmatch, without its leading "!"
+	// and "/" and its trailing slash.
+	glob string
+
+	// basename is set for wildmatch patterns without a slash, which match
+	// the name of a path at any depth.
+	basename bool
+
+	// exact is set for wildmatch patterns only matching the paths they name,
+	// not the contents of the directories they name.
+	exact bool
+
+	// name is the name of the files or directories the pattern matches at any
+	// depth, if it is a literal rule, which is matched by comparing names
+	// rather than with Regex or wildmatch.
+	name string
+
+	// suffix is the extension of the files or directories the pattern
+	// matches at any depth, if it is a "*" followed by a literal extension,
+	// which is matched by comparing the end of names.
+	suffix string
+
+	// prefix holds the literal leading components of an anchored pattern.
+	prefix []string
+}
+
+// RegexSize returns the length of the regular expression of p, as counted
+// towards Parser.MaxRegexSize, or 0 for a wildmatch pattern.
+func (p *Pattern) RegexSize() int {
+	if p.Regex == nil {
+		return 0
+	}
+
+	return len(strings.TrimPrefix(p.Regex.String(), caseInsensitive))
 }
 
-// Parse parses a .gitignore file into a list of patterns.
+// Match reports whether the pattern matches path, given relative to the
+// directory it applies to, joined with Prefix if set.
+func (p *Pattern) Match(path string) bool {
+	if p.Prefix != "" {
+		path = p.Prefix + "/" + path
+	}
+
+	if (p.name != "" || p.suffix != "") && !strings.Contains(path, "\n") {
+		return p.matchComponent(path)
+	}
+
+	if p.Wildmatch {
+		return p.wildmatch(path)
+	}
+
+	return p.Regex.MatchString(path)
+}
+
+// WarningKind identifies why a line was skipped or adjusted while parsing.
+type WarningKind int
+
+const (
+	// WarningInvalidPattern reports a line skipped in permissive mode because
+	// its pattern could not be compiled or was rejected in strict mode.
+	WarningInvalidPattern WarningKind = iota + 1
+
+	// WarningLineTooLong reports a line skipped in permissive mode because it
+	// is longer than the parser accepts.
+	WarningLineTooLong
+
+	// WarningTrailingSpaces reports a line whose trailing spaces were
+	// stripped.
+	WarningTrailingSpaces
+)
+
+// Warning describes a line that was skipped or adjusted while parsing.
+type Warning struct {
+	// Text is the line, as read. It is empty for lines too long to be read.
+	Text string
+
+	// Line is the 1-based line number of the line.
+	Line int
+
+	// Kind identifies why the line was skipped or adjusted.
+	Kind WarningKind
+}
+
+// Counts holds the number of lines skipped while parsing because they hold no
+// pattern.
+type Counts struct {
+	// Comments is the number of comment lines.
+	Comments int
+
+	// Blanks is the number of blank lines, including the ones holding only
+	// spaces.
+	Blanks int
+}
+
+// Parser parses .gitignore files. The zero value fails on the first line that
+// cannot be parsed.
+type Parser struct {
+	// Warn, if set, is called for every line that is skipped or adjusted.
+	Warn func(w Warning)
+
+	// Counts, if set, is incremented for every comment and blank line.
+	Counts *Counts
+
+	// Permissive makes the parser skip the lines that cannot be parsed instead
+	// of failing, returning the remaining patterns along with every problem
+	// joined into a single error.
+	Permissive bool
+
+	// Strict makes the parser reject the lines using constructs git would
+	// silently ignore or treat differently than they read, such as stray
+	// backslashes, as if they could not be parsed.
+	Strict bool
+
+	// MaxPatterns, if positive, is the largest number of patterns a file may
+	// hold. Exceeding it is fatal, even in permissive mode.
+	MaxPatterns int
+
+	// MaxLineLength, if positive, is the length of the longest line accepted,
+	// in bytes. Longer lines cannot be parsed.
+	MaxLineLength int
+
+	// MaxRegexSize, if positive, is the largest total length of the regular
+	// expressions compiled from a file, in bytes. Exceeding it is fatal, even
+	// in permissive mode.
+	MaxRegexSize int
+
+	// Held and HeldRegexSize are the number of patterns and the total length
+	// of their regular expressions already held by a file being extended.
+	// They count towards MaxPatterns and MaxRegexSize.
+	Held          int
+	HeldRegexSize int
+
+	// IgnoreCase makes the patterns match paths regardless of case, like git
+	// does when core.ignoreCase is set.
+	IgnoreCase bool
+
+	// Wildmatch makes the patterns match paths with git's wildmatch
+	// algorithm rather than with regular expressions, which are then not
+	// compiled, so they cannot fail to compile nor count towards
+	// MaxRegexSize.
+	Wildmatch bool
+}
+
+// Parse parses a .gitignore file into a list of patterns, failing on the first
+// line that cannot be parsed.
 func Parse(r io.Reader) ([]*Pattern, error) {
+	return (&Parser{}).Parse(r)
+}
+
+// Parse parses a .gitignore file into a list of patterns. Errors reading r are
+// always fatal, even in permissive mode.
+func (p *Parser) Parse(r io.Reader) ([]*Pattern, error) {
 	var (
 		lineNumber int
-		builder    strings.Builder
+		regexSize  = p.HeldRegexSize
+		errs       []error
+		tooLong    bool
 		patterns   = make([]*Pattern, 0, defaultPatternCapacity)
-		scanner    = bufio.NewScanner(r)
+		scanner    = p.scanner(r, &tooLong)
 	)
 
 	for scanner.Scan() {
 		lineNumber++
 
+		if tooLong {
+			tooLong = false
+
+			if !p.Permissive {
+				return nil, &Error{
+					Err:    fmt.Errorf("%w: longer than %d bytes", ErrLineTooLong, p.lineLimit()),
+					Line:   lineNumber,
+					Column: p.lineLimit() + 1,
+				}
+			}
+
+			p.warn(WarningLineTooLong, "", lineNumber)
+
+			continue
+		}
+
 		line := scanner.Text()
 
 		// Trim OS-specific carriage returns.
 		line = strings.TrimRight(line, "\r")
+		text := line
 
 		// Strip comments [Rule 2].
 		if strings.HasPrefix(line, `#`) {
+			if p.Counts != nil {
+				p.Counts.Comments++
+			}
+
 			continue
 		}
 
-		// Trim string [Rule 3].
-		line = strings.Trim(line, " ")
+		// Trim string [Rule 3]. Like git, the wildmatch engine keeps escaped
+		// trailing spaces.
+		if p.Wildmatch {
+			line = trimTrailingSpaces(strings.TrimLeft(line, " "))
+		} else {
+			line = strings.Trim(line, " ")
+		}
+
+		if line != "" && !strings.HasSuffix(text, line) {
+			p.warn(WarningTrailingSpaces, text, lineNumber)
+		}
 
 		// Exit for no-ops and return nil which will prevent us from
 		// appending a pattern against this line.
 		if line == "" {
+			if p.Counts != nil {
+				p.Counts.Blanks++
+			}
+
 			continue
 		}
 
+		raw := line
+
+		if p.Strict {
+			if col, err := validate(text); err != nil {
+				lineErr := &Error{
+					Err:     err,
+					Text:    text,
+					Pattern: raw,
+					Line:    lineNumber,
+					Column:  col,
+				}
+
+				if err = p.reject(lineErr, &errs); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+		}
+
 		// Handle [Rule 4] which negates the match for patterns leading with "!".
 		negatePattern := false
 		if strings.HasPrefix(line, "!") {
@@ -72,71 +373,298 @@ func Parse(r io.Reader) ([]*Pattern, error) {
 			line = line[1:]
 		}
 
-		// Handle [Rule 2, 4], when # or ! is escaped with a \.
-		if regexp.MustCompile(`^([#!])`).MatchString(line) {
-			line = line[1:]
+		var pat *Pattern
+
+		if p.Wildmatch {
+			pat = newWildmatch(line, false, p.IgnoreCase)
+		} else {
+			var err error
+
+			pat, err = p.regexPattern(line, &Error{Text: text, Pattern: raw, Line: lineNumber}, &regexSize, &errs)
+			if err != nil {
+				return nil, err
+			}
+
+			if pat == nil {
+				continue
+			}
 		}
 
-		// If we encounter a foo/*.blah in a folder, prepend the / char.
-		if regexp.MustCompile(`([^/+])/.*\*\.`).MatchString(line) && !strings.HasPrefix(line, "/") {
-			line = "/" + line
+		if p.MaxPatterns > 0 && p.Held+len(patterns) >= p.MaxPatterns {
+			return nil, &Error{
+				Err:     fmt.Errorf("%w: more than %d", ErrTooManyPatterns, p.MaxPatterns),
+				Text:    text,
+				Pattern: raw,
+				Line:    lineNumber,
+				Column:  column(text, nil),
+			}
 		}
 
-		// Handle escaping the "." char.
-		line = regexp.MustCompile(`\.`).ReplaceAllString(line, `\.`)
+		pat.Raw = raw
+		pat.Text = text
+		pat.Line = lineNumber
+		pat.Negate = negatePattern
+		pat.DirOnly = strings.HasSuffix(raw, "/")
 
-		const magicStar = "#$~"
+		patterns = append(patterns, pat)
+	}
 
-		// Handle "/**/" usage.
-		if strings.HasPrefix(line, "/**/") {
-			line = line[1:]
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrScanningFile, err)
+	}
+
+	return patterns, errors.Join(errs...)
+}
+
+// regexPattern returns the pattern matching the paths line, a rule without its
+// leading "!", matches with a regular expression, adding the length of the
+// expression to regexSize. Errors are reported for the line described by
+// lineErr. A line that cannot be compiled is rejected, and nil is returned if
+// it is skipped, along with a nil error.
+func (p *Parser) regexPattern(line string, lineErr *Error, regexSize *int, errs *[]error) (*Pattern, error) {
+	expr := expression(line, false)
+
+	*regexSize += len(expr)
+
+	if p.MaxRegexSize > 0 && *regexSize > p.MaxRegexSize {
+		lineErr.Err = fmt.Errorf("%w: more than %d bytes", ErrRegexTooLarge, p.MaxRegexSize)
+		lineErr.Column = column(lineErr.Text, nil)
+
+		return nil, lineErr
+	}
+
+	flags := ""
+	if p.IgnoreCase {
+		flags = caseInsensitive
+	}
+
+	regex, err := regexp.Compile(flags + expr)
+	if err != nil {
+		lineErr.Err = fmt.Errorf("%w: %w", ErrInvalidRegex, err)
+		lineErr.Column = column(lineErr.Text, err)
+
+		return nil, p.reject(lineErr, errs)
+	}
+
+	pat := &Pattern{
+		Regex:      regex,
+		Anchored:   strings.HasPrefix(expr, "^(|/)"),
+		IgnoreCase: p.IgnoreCase,
+	}
+
+	if !p.IgnoreCase {
+		pat.name, pat.suffix = literal(line)
+
+		if pat.Anchored {
+			pat.prefix = literalPrefix(line)
 		}
+	}
+
+	return pat, nil
+}
 
-		line = regexp.MustCompile(`/\*\*/`).ReplaceAllString(line, `(/|/.+/)`)
-		line = regexp.MustCompile(`\*\*/`).ReplaceAllString(line, `(|.`+magicStar+`/)`)
-		line = regexp.MustCompile(`/\*\*`).ReplaceAllString(line, `(|/.`+magicStar+`)`)
+// scanner returns a scanner splitting r into lines. In permissive mode, or when
+// the line length is limited, lines longer than the limit are discarded rather
+// than failing the scan, and reported as an empty line with tooLong set.
+func (p *Parser) scanner(r io.Reader, tooLong *bool) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
 
-		// Handle escaping the "*" char.
-		line = regexp.MustCompile(`\\\*`).ReplaceAllString(line, `\`+magicStar)
-		line = regexp.MustCompile(`\*`).ReplaceAllString(line, `([^/]*)`)
+	if !p.Permissive && p.MaxLineLength <= 0 {
+		scanner.Buffer(nil, maxLineLength)
 
-		// Handle escaping the "?" char.
-		line = strings.ReplaceAll(line, "?", `\?`)
+		return scanner
+	}
 
-		line = strings.ReplaceAll(line, magicStar, "*")
+	var (
+		discarding bool
+		limit      = p.lineLimit()
+	)
 
-		builder.Reset()
+	scanner.Buffer(nil, limit+1)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if !discarding {
+			advance, token, err := bufio.ScanLines(data, atEOF)
+			if len(token) > limit {
+				// Only reached for a last line without a line ending.
+				*tooLong = true
 
-		if strings.HasSuffix(line, "/") {
-			builder.WriteString(line)
-			builder.WriteString("(|.*)$")
-		} else {
-			builder.WriteString(line)
-			builder.WriteString("(|/.*)$")
+				return advance, []byte{}, err
+			}
+
+			if token != nil || err != nil || len(data) <= limit {
+				return advance, token, err
+			}
+
+			discarding = true
 		}
 
-		expr := builder.String()
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			discarding, *tooLong = false, true
 
-		if strings.HasPrefix(expr, "/") {
-			expr = "^(|/)" + expr[1:]
-		} else {
-			expr = "^(|.*/)" + expr
+			return i + 1, []byte{}, nil
 		}
 
-		regex, err := regexp.Compile(expr)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %q on line %d: %w", ErrInvalidRegex, expr, lineNumber, err)
+		if atEOF {
+			discarding, *tooLong = false, true
+
+			return len(data), []byte{}, bufio.ErrFinalToken
 		}
 
-		patterns = append(patterns, &Pattern{
-			Regex:  regex,
-			Negate: negatePattern,
-		})
+		return len(data), nil, nil
+	})
+
+	return scanner
+}
+
+// lineLimit returns the length of the longest line the parser accepts.
+func (p *Parser) lineLimit() int {
+	if p.MaxLineLength > 0 {
+		return p.MaxLineLength
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrScanningFile, err)
+	return maxLineLength
+}
+
+// reject handles a line that cannot be parsed. Unless the parser is permissive,
+// it returns the error failing the parse; otherwise, the error is collected
+// into errs and the line is reported as skipped.
+func (p *Parser) reject(lineErr *Error, errs *[]error) error {
+	if !p.Permissive {
+		return lineErr
+	}
+
+	*errs = append(*errs, lineErr)
+
+	p.warn(WarningInvalidPattern, lineErr.Text, lineErr.Line)
+
+	return nil
+}
+
+// warn reports a skipped or adjusted line, if the parser has a Warn function.
+func (p *Parser) warn(kind WarningKind, text string, line int) {
+	if p.Warn == nil {
+		return
+	}
+
+	p.Warn(Warning{
+		Text: text,
+		Line: line,
+		Kind: kind,
+	})
+}
+
+// column returns the 1-based column of the part of text the regular expression
+// error err points at, falling back to the start of the pattern.
+func column(text string, err error) int {
+	var syntaxErr *syntax.Error
+
+	if errors.As(err, &syntaxErr) {
+		// The offending expression may extend past the pattern into the
+		// suffix added during translation, so look for its longest prefix.
+		for n := len(syntaxErr.Expr); n > 0; n-- {
+			if i := strings.Index(text, syntaxErr.Expr[:n]); i >= 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return len(text) - len(strings.TrimLeft(text, " ")) + 1
+}
+
+// Compile compiles rule, a single pattern without surrounding spaces, such as
+// the pattern of a .gitattributes line. A leading "!" negates it. Like in a
+// .gitignore file, the pattern also matches the contents of the directories it
+// names, unless exact is set, in which case it only matches the paths it names,
+// with a trailing slash for directories.
+func Compile(rule string, exact bool) (*Pattern, error) {
+	line := rule
+
+	negate := strings.HasPrefix(line, "!")
+	if negate {
+		line = line[1:]
+	}
+
+	expr := expression(line, exact)
+
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, &Error{
+			Err:     fmt.Errorf("%w: %w", ErrInvalidRegex, err),
+			Text:    rule,
+			Pattern: rule,
+			Line:    1,
+			Column:  column(rule, err),
+		}
+	}
+
+	return &Pattern{
+		Regex:    regex,
+		Raw:      rule,
+		Text:     rule,
+		Line:     1,
+		Negate:   negate,
+		Anchored: strings.HasPrefix(expr, "^(|/)"),
+		DirOnly:  strings.HasSuffix(rule, "/"),
+	}, nil
+}
+
+// expression returns the regular expression matching the paths line, a
+// pattern without its leading "!", matches, along with the contents of the
+// directories it names unless exact is set.
+func expression(line string, exact bool) string {
+	var builder strings.Builder
+
+	// Handle [Rule 2, 4], when # or ! is escaped with a \.
+	if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+		line = line[1:]
+	}
+
+	// If we encounter a foo/*.blah in a folder, prepend the / char.
+	if subdirGlob.MatchString(line) && !strings.HasPrefix(line, "/") {
+		line = "/" + line
+	}
+
+	// Handle escaping the "." char.
+	line = strings.ReplaceAll(line, ".", `\.`)
+
+	const magicStar = "#$~"
+
+	// Handle "/**/" usage.
+	if strings.HasPrefix(line, "/**/") {
+		line = line[1:]
+	}
+
+	line = strings.ReplaceAll(line, "/**/", `(/|/.+/)`)
+	line = strings.ReplaceAll(line, "**/", `(|.`+magicStar+`/)`)
+	line = strings.ReplaceAll(line, "/**", `(|/.`+magicStar+`)`)
+
+	// Handle escaping the "*" char.
+	line = strings.ReplaceAll(line, `\*`, `\`+magicStar)
+	line = strings.ReplaceAll(line, "*", `([^/]*)`)
+
+	// Handle escaping the "?" char.
+	line = strings.ReplaceAll(line, "?", `\?`)
+
+	line = strings.ReplaceAll(line, magicStar, "*")
+
+	builder.WriteString(line)
+
+	switch {
+	case exact:
+		builder.WriteString("$")
+	case strings.HasSuffix(line, "/"):
+		builder.WriteString("(|.*)$")
+	default:
+		builder.WriteString("(|/.*)$")
+	}
+
+	expr := builder.String()
+
+	if strings.HasPrefix(expr, "/") {
+		expr = "^(|/)" + expr[1:]
+	} else {
+		expr = "^(|.*/)" + expr
 	}
 
-	return patterns, nil
+	return expr
 }
@@ -3,6 +3,7 @@ package pattern_test
 import (
 	"errors"
 	"io"
+	"slices"
 	"strings"
 	"testing"
 
@@ -179,3 +180,268 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_Error(t *testing.T) {
+	t.Parallel()
+
+	_, err := pattern.Parse(strings.NewReader("*.log\r\nfoo/[z-a]\r\n"))
+
+	var patternErr *pattern.Error
+	if !errors.As(err, &patternErr) {
+		t.Fatalf("Parse() error = %v, want *pattern.Error", err)
+	}
+
+	if patternErr.Text != "foo/[z-a]" || patternErr.Line != 2 || patternErr.Column != 6 {
+		t.Errorf("Parse() error = %+v, want foo/[z-a] at 2:6", patternErr)
+	}
+
+	if !errors.Is(err, pattern.ErrInvalidRegex) {
+		t.Errorf("errors.Is(err, ErrInvalidRegex) = false, want true")
+	}
+}
+
+func TestParser_Permissive(t *testing.T) {
+	t.Parallel()
+
+	p := &pattern.Parser{Permissive: true}
+
+	patterns, err := p.Parse(strings.NewReader("[invalid\n*.log\nfoo[\n"))
+	if len(patterns) != 1 || patterns[0].Raw != "*.log" {
+		t.Fatalf("Parse() = %v, want the *.log pattern only", patterns)
+	}
+
+	if !errors.Is(err, pattern.ErrInvalidRegex) {
+		t.Fatalf("Parse() error = %v, want %v", err, pattern.ErrInvalidRegex)
+	}
+
+	_, err = p.Parse(&errorReader{})
+	if !errors.Is(err, pattern.ErrScanningFile) {
+		t.Errorf("Parse() error = %v, want %v", err, pattern.ErrScanningFile)
+	}
+}
+
+func TestParser_Strict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		giveLine   string
+		wantErr    error
+		wantColumn int
+	}{
+		{name: "Clean", giveLine: "*.log"},
+		{name: "Leading double star", giveLine: "**/build"},
+		{name: "Middle double star", giveLine: "a/**/b"},
+		{name: "Trailing double star", giveLine: "logs/**"},
+		{name: "Escaped special characters", giveLine: `\#file\*\ \!`},
+		{name: "Negation", giveLine: "!keep.log"},
+		{name: "Trailing backslash", giveLine: `foo\`, wantErr: pattern.ErrTrailingBackslash, wantColumn: 4},
+		{name: "Useless escape", giveLine: `  fo\o`, wantErr: pattern.ErrUselessEscape, wantColumn: 5},
+		{name: "Double star in name", giveLine: "a**b", wantErr: pattern.ErrDoubleStar, wantColumn: 2},
+		{name: "Triple star", giveLine: "a/***/b", wantErr: pattern.ErrDoubleStar, wantColumn: 3},
+		{name: "Slash only", giveLine: "/", wantErr: pattern.ErrNeverMatches, wantColumn: 1},
+		{name: "Lone negation", giveLine: "!", wantErr: pattern.ErrNeverMatches, wantColumn: 2},
+		{name: "Trailing spaces", giveLine: "*.log  ", wantErr: pattern.ErrTrailingSpaces, wantColumn: 6},
+	}
+
+	p := &pattern.Parser{Strict: true}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := p.Parse(strings.NewReader("# comment\n" + tt.giveLine + "\n"))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Parse(%q) error = %v, want nil", tt.giveLine, err)
+				}
+
+				return
+			}
+
+			var lineErr *pattern.Error
+			if !errors.As(err, &lineErr) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.giveLine, err, tt.wantErr)
+			}
+
+			if lineErr.Line != 2 || lineErr.Column != tt.wantColumn {
+				t.Errorf("Parse(%q) error at %d:%d, want 2:%d", tt.giveLine, lineErr.Line, lineErr.Column, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		giveRule   string
+		giveExact  bool
+		givePath   string
+		wantMatch  bool
+		wantNegate bool
+	}{
+		{
+			name:      "Directory contents",
+			giveRule:  "docs",
+			givePath:  "docs/readme.md",
+			wantMatch: true,
+		},
+		{
+			name:      "Exact directory contents",
+			giveRule:  "docs",
+			giveExact: true,
+			givePath:  "docs/readme.md",
+		},
+		{
+			name:      "Exact name",
+			giveRule:  "*.md",
+			giveExact: true,
+			givePath:  "docs/readme.md",
+			wantMatch: true,
+		},
+		{
+			name:       "Negated",
+			giveRule:   "!*.md",
+			givePath:   "readme.md",
+			wantMatch:  true,
+			wantNegate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pat, err := pattern.Compile(tt.giveRule, tt.giveExact)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			if got := pat.Match(tt.givePath); got != tt.wantMatch {
+				t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.wantMatch)
+			}
+
+			if pat.Negate != tt.wantNegate {
+				t.Errorf("Negate = %v, want %v", pat.Negate, tt.wantNegate)
+			}
+		})
+	}
+}
+
+func TestPattern_Name(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		giveLine      string
+		giveWildmatch bool
+		giveFold      bool
+		wantName      string
+		wantOK        bool
+	}{
+		{name: "File", giveLine: ".DS_Store", wantName: ".DS_Store", wantOK: true},
+		{name: "Directory", giveLine: "node_modules/", wantName: "node_modules", wantOK: true},
+		{name: "Negated", giveLine: "!keep", wantName: "keep", wantOK: true},
+		{name: "Wildmatch", giveLine: "build/", giveWildmatch: true, wantName: "build", wantOK: true},
+		{name: "Wildcard", giveLine: "*.log"},
+		{name: "Character class", giveLine: "file[0-9]"},
+		{name: "Escape", giveLine: `\#notes`},
+		{name: "Regular expression syntax", giveLine: "a+b"},
+		{name: "Anchored", giveLine: "/build"},
+		{name: "Middle slash", giveLine: "docs/build"},
+		{name: "Case-insensitive", giveLine: "build", giveFold: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := &pattern.Parser{Wildmatch: tt.giveWildmatch, IgnoreCase: tt.giveFold}
+
+			patterns, err := parser.Parse(strings.NewReader(tt.giveLine))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			name, ok := patterns[0].Name()
+			if name != tt.wantName || ok != tt.wantOK {
+				t.Errorf("Name() = %q, %v, want %q, %v", name, ok, tt.wantName, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPattern_Suffix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		giveLine   string
+		wantSuffix string
+		wantOK     bool
+	}{
+		{name: "Extension", giveLine: "*.log", wantSuffix: ".log", wantOK: true},
+		{name: "Double extension", giveLine: "*.tar.gz", wantSuffix: ".tar.gz", wantOK: true},
+		{name: "Directory", giveLine: "*.egg-info/", wantSuffix: ".egg-info", wantOK: true},
+		{name: "Not an extension", giveLine: "*~"},
+		{name: "Double star", giveLine: "**.log"},
+		{name: "Wildcard in extension", giveLine: "*.py[co]"},
+		{name: "Middle slash", giveLine: "*.d/x"},
+		{name: "Literal", giveLine: "app.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			patterns, err := pattern.Parse(strings.NewReader(tt.giveLine))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			suffix, ok := patterns[0].Suffix()
+			if suffix != tt.wantSuffix || ok != tt.wantOK {
+				t.Errorf("Suffix() = %q, %v, want %q, %v", suffix, ok, tt.wantSuffix, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPattern_LiteralPrefix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		giveLine      string
+		giveWildmatch bool
+		want          []string
+	}{
+		{name: "Anchored name", giveLine: "/build/", want: []string{"build"}},
+		{name: "Anchored path", giveLine: "/docs/_site/", want: []string{"docs", "_site"}},
+		{name: "Wildcard", giveLine: "/docs/**/*.html", want: []string{"docs"}},
+		{name: "Extension in a directory", giveLine: "docs/*.md", want: []string{"docs"}},
+		{name: "Middle slash", giveLine: "docs/_site/", want: nil},
+		{name: "Wildmatch middle slash", giveLine: "docs/_site/", giveWildmatch: true, want: []string{"docs", "_site"}},
+		{name: "Leading wildcard", giveLine: "/build-*", want: nil},
+		{name: "Not anchored", giveLine: "build", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := &pattern.Parser{Wildmatch: tt.giveWildmatch}
+
+			patterns, err := parser.Parse(strings.NewReader(tt.giveLine))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got := patterns[0].LiteralPrefix(); !slices.Equal(got, tt.want) {
+				t.Errorf("LiteralPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package pattern
+
+import (
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+const (
+	// ErrTrailingBackslash is returned in strict mode for a pattern ending
+	// with an unescaped backslash, which git considers invalid and never
+	// matches.
+	ErrTrailingBackslash xerrors.Error = "pattern ends with a backslash"
+
+	// ErrUselessEscape is returned in strict mode for a backslash escaping a
+	// character with no special meaning, which git silently drops.
+	ErrUselessEscape xerrors.Error = "backslash escapes a character with no special meaning"
+
+	// ErrDoubleStar is returned in strict mode for two asterisks not forming a
+	// whole path component, which git treats as a single one.
+	ErrDoubleStar xerrors.Error = `"**" is not a whole path component`
+
+	// ErrNeverMatches is returned in strict mode for a pattern that can never
+	// match a path, such as "/" or a lone "!".
+	ErrNeverMatches xerrors.Error = "pattern can never match"
+
+	// ErrTrailingSpaces is returned in strict mode for a line with unescaped
+	// trailing spaces, which git silently strips.
+	ErrTrailingSpaces xerrors.Error = "unescaped trailing spaces are ignored"
+)
+
+// escapable holds the characters that have a special meaning in a pattern and
+// may thus be escaped with a backslash.
+const escapable = `\*?[]!# `
+
+// validate returns the 1-based column in the line text of the first construct
+// git would silently ignore or treat differently than it reads, along with an
+// error describing it. It returns a nil error for clean lines.
+func validate(text string) (int, error) {
+	trimmed := strings.TrimRight(text, " ")
+
+	// A space escaped with a backslash is kept.
+	if n := len(trimmed) - len(strings.TrimRight(trimmed, `\`)); n%2 == 1 && trimmed != text {
+		trimmed += " "
+	}
+
+	if trimmed != text {
+		return len(trimmed) + 1, ErrTrailingSpaces
+	}
+
+	start := len(text) - len(strings.TrimLeft(text, " "))
+	rule := text[start:]
+
+	if strings.HasPrefix(rule, "!") {
+		start++
+		rule = rule[1:]
+	}
+
+	if rule == "" || strings.Trim(rule, "/") == "" {
+		return start + 1, ErrNeverMatches
+	}
+
+	for i := 0; i < len(rule); i++ {
+		switch rule[i] {
+		case '\\':
+			if i == len(rule)-1 {
+				return start + i + 1, ErrTrailingBackslash
+			}
+
+			// Leading "\#" and "\!" are valid, and so is escaping a
+			// character with a special meaning anywhere else.
+			if !strings.ContainsRune(escapable, rune(rule[i+1])) {
+				return start + i + 1, ErrUselessEscape
+			}
+
+			i++
+		case '*':
+			j := i
+			for j < len(rule) && rule[j] == '*' {
+				j++
+			}
+
+			if j-i > 1 && !wholeComponent(rule, i, j) {
+				return start + i + 1, ErrDoubleStar
+			}
+
+			i = j - 1
+		}
+	}
+
+	return 0, nil
+}
+
+// wholeComponent reports whether rule[i:j] is a whole path component of rule,
+// with exactly two characters.
+func wholeComponent(rule string, i, j int) bool {
+	return j-i == 2 && (i == 0 || rule[i-1] == '/') && (j == len(rule) || rule[j] == '/')
+}
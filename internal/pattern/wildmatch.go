@@ -0,0 +1,113 @@
+package pattern
+
+import (
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/wildmatch"
+)
+
+// newWildmatch returns the pattern matching the paths line, a rule without its
+// leading "!", matches with git's wildmatch algorithm, along with the contents
+// of the directories it names unless exact is set.
+//
+// Like with git, a rule ending with a slash only matches directories, a rule
+// without any other slash matches the name of a path at any depth, and any
+// other rule matches the whole path, relative to the directory of the rules.
+func newWildmatch(line string, exact, ignoreCase bool) *Pattern {
+	glob := strings.TrimSuffix(line, "/")
+	basename := !strings.Contains(glob, "/")
+
+	pat := &Pattern{
+		Wildmatch:  true,
+		Anchored:   !basename,
+		IgnoreCase: ignoreCase,
+		glob:       strings.TrimPrefix(glob, "/"),
+		basename:   basename,
+		exact:      exact,
+	}
+
+	if !exact && !ignoreCase {
+		pat.name, pat.suffix = literal(line)
+
+		if pat.Anchored {
+			pat.prefix = literalPrefix(line)
+		}
+	}
+
+	return pat
+}
+
+// wildmatch reports whether the wildmatch pattern p matches path, a
+// slash-separated path with a trailing slash for directories, or, unless the
+// pattern is exact, one of the directories holding it.
+func (p *Pattern) wildmatch(path string) bool {
+	isDir := strings.HasSuffix(path, "/")
+	path = strings.TrimSuffix(path, "/")
+
+	if p.matchPath(path, isDir) {
+		return true
+	}
+
+	if p.exact {
+		return false
+	}
+
+	for i := range len(path) {
+		if path[i] == '/' && p.matchPath(path[:i], true) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchPath reports whether the wildmatch pattern p matches path, a
+// slash-separated path without a trailing slash, which is a directory if isDir
+// is set, like git's match_basename and match_pathname do.
+func (p *Pattern) matchPath(path string, isDir bool) bool {
+	if p.DirOnly && !isDir {
+		return false
+	}
+
+	var flags wildmatch.Flags
+
+	if p.IgnoreCase {
+		flags |= wildmatch.CaseFold
+	}
+
+	if p.basename {
+		return wildmatch.Match(p.glob, path[strings.LastIndexByte(path, '/')+1:], flags)
+	}
+
+	return wildmatch.Match(p.glob, path, flags|wildmatch.Pathname)
+}
+
+// trimTrailingSpaces returns line without its trailing spaces, unless they are
+// escaped with a backslash, like git's trim_trailing_spaces.
+func trimTrailingSpaces(line string) string {
+	end := -1
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			if end < 0 {
+				end = i
+			}
+		case '\\':
+			i++
+			if i == len(line) {
+				return line
+			}
+
+			end = -1
+		default:
+			end = -1
+		}
+	}
+
+	if end < 0 {
+		return line
+	}
+
+	return line[:end]
+}
@@ -0,0 +1,251 @@
+// Package property generates random gitignore rules together with paths whose
+// expected decision is known by construction, to property-test matchers over
+// thousands of cases instead of hand-written tables.
+package property
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	// nameAlphabet is used for the literal parts of patterns.
+	nameAlphabet = "abcdefghij"
+
+	// dirAlphabet is used for directories generated around matched names. It
+	// shares no character with nameAlphabet, so generated directories never
+	// match a pattern by accident.
+	dirAlphabet = "KLMNOPQRST"
+
+	// maxDepth is the maximum number of directories generated around a name.
+	maxDepth = 3
+)
+
+// Case is a rule set and a path whose decision is known by construction.
+type Case struct {
+	// Kind names the construction used to build the case.
+	Kind string
+
+	// Rules holds the lines of the .gitignore file.
+	Rules []string
+
+	// Path is the slash-separated path to match, without trailing slash.
+	Path string
+
+	// IsDir reports whether Path is a directory.
+	IsDir bool
+
+	// Ignored is the expected decision for Path.
+	Ignored bool
+}
+
+// Generator produces random cases.
+type Generator struct {
+	rand *rand.Rand
+}
+
+// NewGenerator returns a Generator producing the same sequence of cases for
+// the same seed.
+func NewGenerator(seed uint64) *Generator {
+	return &Generator{
+		rand: rand.New(rand.NewPCG(seed, seed)), //nolint:gosec // Reproducible test data, not security.
+	}
+}
+
+// Next returns a new random case.
+func (g *Generator) Next() Case {
+	constructions := []func() Case{
+		g.basenameGlob,
+		g.anchored,
+		g.directoryOnly,
+		g.leadingDoubleStar,
+		g.trailingDoubleStar,
+		g.negation,
+	}
+
+	return constructions[g.rand.IntN(len(constructions))]()
+}
+
+// basenameGlob builds a pattern without slashes, such as "ab*.cd", which
+// matches names at any depth.
+func (g *Generator) basenameGlob() Case {
+	var (
+		prefix  = g.name()
+		suffix  = g.name()
+		pattern = prefix + "*." + suffix
+		name    = prefix + g.fill() + "." + suffix
+	)
+
+	c := Case{
+		Kind:    "basename glob",
+		Rules:   []string{pattern},
+		Path:    g.join(g.dirs(), name),
+		Ignored: true,
+	}
+
+	if g.rand.IntN(2) == 0 {
+		// Extending the suffix keeps every other part of the name intact, but
+		// the pattern must match up to the end of the name.
+		c.Path += g.name()
+		c.Ignored = false
+	}
+
+	return c
+}
+
+// anchored builds a pattern with a leading slash, which only matches relative
+// to the root.
+func (g *Generator) anchored() Case {
+	var (
+		dir  = g.name()
+		name = g.name()
+	)
+
+	c := Case{
+		Kind:    "anchored",
+		Rules:   []string{"/" + dir + "/" + name},
+		Path:    dir + "/" + name,
+		Ignored: true,
+	}
+
+	if g.rand.IntN(2) == 0 {
+		c.Path = g.join(g.dirs1(), c.Path)
+		c.Ignored = false
+	}
+
+	return c
+}
+
+// directoryOnly builds a pattern with a trailing slash, which matches
+// directories and everything inside them, but not files.
+func (g *Generator) directoryOnly() Case {
+	name := g.name()
+
+	c := Case{
+		Kind:  "directory only",
+		Rules: []string{name + "/"},
+	}
+
+	switch g.rand.IntN(3) {
+	case 0:
+		c.Path, c.IsDir, c.Ignored = g.join(g.dirs(), name), true, true
+	case 1:
+		c.Path, c.Ignored = g.join(g.dirs(), name, g.name()), true
+	default:
+		c.Path, c.Ignored = g.join(g.dirs(), name), false
+	}
+
+	return c
+}
+
+// leadingDoubleStar builds a "**/name" pattern, which matches name at any
+// depth.
+func (g *Generator) leadingDoubleStar() Case {
+	name := g.name()
+
+	return Case{
+		Kind:    "leading double star",
+		Rules:   []string{"**/" + name},
+		Path:    g.join(g.dirs(), name),
+		IsDir:   g.rand.IntN(2) == 0,
+		Ignored: true,
+	}
+}
+
+// trailingDoubleStar builds an anchored "/name/**" pattern, which matches
+// everything inside name but not name itself.
+func (g *Generator) trailingDoubleStar() Case {
+	name := g.name()
+
+	c := Case{
+		Kind:    "trailing double star",
+		Rules:   []string{"/" + name + "/**"},
+		Path:    g.join(name, g.dirs(), g.name()),
+		Ignored: true,
+	}
+
+	if g.rand.IntN(2) == 0 {
+		c.Path = g.join(g.dirs1(), c.Path)
+		c.Ignored = false
+	}
+
+	return c
+}
+
+// negation builds a glob followed by a negated rule re-including one name.
+func (g *Generator) negation() Case {
+	var (
+		ext  = g.name()
+		keep = g.name() + "." + ext
+	)
+
+	c := Case{
+		Kind:    "negation",
+		Rules:   []string{"*." + ext, "!" + keep},
+		Path:    g.join(g.dirs(), keep),
+		Ignored: false,
+	}
+
+	if g.rand.IntN(2) == 0 {
+		c.Path = g.join(g.dirs(), keep+g.name()+"."+ext)
+		c.Ignored = true
+	}
+
+	return c
+}
+
+// name returns a random non-empty literal made of nameAlphabet.
+func (g *Generator) name() string {
+	return g.word(nameAlphabet, 1+g.rand.IntN(4))
+}
+
+// fill returns a random, possibly empty, string matched by a "*" wildcard.
+func (g *Generator) fill() string {
+	return g.word(nameAlphabet+dirAlphabet, g.rand.IntN(5))
+}
+
+// dirs returns between zero and maxDepth random directories joined by
+// slashes.
+func (g *Generator) dirs() string {
+	return g.dirsN(g.rand.IntN(maxDepth + 1))
+}
+
+// dirs1 is like dirs, but returns at least one directory.
+func (g *Generator) dirs1() string {
+	return g.dirsN(1 + g.rand.IntN(maxDepth))
+}
+
+// dirsN returns n random directories joined by slashes.
+func (g *Generator) dirsN(n int) string {
+	parts := make([]string, 0, n)
+
+	for range n {
+		parts = append(parts, g.word(dirAlphabet, 1+g.rand.IntN(4)))
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// word returns a random string of length n made of the given alphabet.
+func (g *Generator) word(alphabet string, n int) string {
+	var b strings.Builder
+
+	for range n {
+		b.WriteByte(alphabet[g.rand.IntN(len(alphabet))])
+	}
+
+	return b.String()
+}
+
+// join joins the non-empty elements with slashes.
+func (g *Generator) join(elems ...string) string {
+	parts := make([]string, 0, len(elems))
+
+	for _, elem := range elems {
+		if elem != "" {
+			parts = append(parts, elem)
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
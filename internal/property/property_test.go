@@ -0,0 +1,22 @@
+package property_test
+
+import (
+	"slices"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/property"
+)
+
+func TestGenerator_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	a, b := property.NewGenerator(42), property.NewGenerator(42)
+
+	for range 100 {
+		x, y := a.Next(), b.Next()
+
+		if x.Kind != y.Kind || x.Path != y.Path || x.IsDir != y.IsDir || x.Ignored != y.Ignored || !slices.Equal(x.Rules, y.Rules) {
+			t.Fatalf("generators with the same seed diverged: %+v != %+v", x, y)
+		}
+	}
+}
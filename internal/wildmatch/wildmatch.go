@@ -0,0 +1,352 @@
+// Package wildmatch implements the wildmatch algorithm git uses to match paths
+// against glob patterns, character by character, without compiling patterns
+// into regular expressions. It follows git's wildmatch.c, so edge cases such
+// as "**" next to characters other than slashes, escaped characters, and
+// character classes behave exactly like in git.
+package wildmatch
+
+// Flags modify how a pattern matches.
+type Flags uint8
+
+const (
+	// Pathname makes wildcards stop at slashes: "*" and "?" and character
+	// classes never match a slash, while "**" between slashes, or at either
+	// end of the pattern next to a slash, matches any number of directories.
+	Pathname Flags = 1 << iota
+
+	// CaseFold makes the pattern match regardless of case.
+	CaseFold
+)
+
+// result is the outcome of matching part of a pattern. Like in git, matches
+// that cannot succeed however the text is consumed by the wildcards above
+// abort early.
+type result int
+
+const (
+	match result = iota
+	noMatch
+	abortAll
+	abortToStarStar
+)
+
+// Match reports whether text matches pattern. Both are compared byte by byte.
+func Match(pattern, text string, flags Flags) bool {
+	return dowild(pattern, text, flags) == match
+}
+
+// dowild matches text against pattern, returning how the match failed, if it
+// did, so callers can stop trying other splits of the text.
+//
+//nolint:gocognit,gocyclo,cyclop,funlen,maintidx // Kept close to git's wildmatch.c.
+func dowild(pattern, text string, flags Flags) result {
+	var p, t int
+
+	for ; p < len(pattern); p, t = p+1, t+1 {
+		pch := pattern[p]
+
+		if t == len(text) && pch != '*' {
+			return abortAll
+		}
+
+		var tch byte
+		if t < len(text) {
+			tch = fold(text[t], flags)
+		}
+
+		switch pch {
+		case '\\':
+			// Literal match with the following character. Like with git,
+			// it is not folded, so an upper case letter never matches
+			// folded text.
+			p++
+			if p == len(pattern) || tch != pattern[p] {
+				return noMatch
+			}
+		case '?':
+			// Match anything but a slash.
+			if flags&Pathname != 0 && tch == '/' {
+				return noMatch
+			}
+		case '*':
+			var matchSlash bool
+
+			p++
+
+			if p < len(pattern) && pattern[p] == '*' {
+				prev := p - 2
+
+				for p < len(pattern) && pattern[p] == '*' {
+					p++
+				}
+
+				switch {
+				case flags&Pathname == 0:
+					// Without Pathname, "*" is the same as "**".
+					matchSlash = true
+				case (prev < 0 || pattern[prev] == '/') &&
+					(p == len(pattern) || pattern[p] == '/' || pattern[p] == '\\' && p+1 < len(pattern) && pattern[p+1] == '/'):
+					// Assuming "foo/" already matched and the pattern
+					// is at "**/", match nothing and go ahead with the
+					// rest of the pattern, so foo/**/bar matches both
+					// foo/bar and foo/a/bar.
+					if p < len(pattern) && pattern[p] == '/' && dowild(pattern[p+1:], text[t:], flags) == match {
+						return match
+					}
+
+					matchSlash = true
+				default:
+					// A "**" not next to slashes is a "*".
+					matchSlash = false
+				}
+			} else {
+				// Without Pathname, "*" is the same as "**".
+				matchSlash = flags&Pathname == 0
+			}
+
+			if p == len(pattern) {
+				// A trailing "**" matches everything, and a trailing
+				// "*" everything but a slash.
+				if !matchSlash && indexSlash(text[t:]) >= 0 {
+					return noMatch
+				}
+
+				return match
+			}
+
+			if !matchSlash && pattern[p] == '/' {
+				// A single "*" followed by a slash matches up to the
+				// next slash, which the loop consumes.
+				slash := indexSlash(text[t:])
+				if slash < 0 {
+					return noMatch
+				}
+
+				t += slash
+
+				continue
+			}
+
+			for t < len(text) {
+				matched := dowild(pattern[p:], text[t:], flags)
+				if matched != noMatch {
+					if !matchSlash || matched != abortToStarStar {
+						return matched
+					}
+				} else if !matchSlash && text[t] == '/' {
+					return abortToStarStar
+				}
+
+				t++
+			}
+
+			return abortAll
+		case '[':
+			p++
+			if p == len(pattern) {
+				return abortAll
+			}
+
+			pch = pattern[p]
+
+			negated := pch == '!' || pch == '^'
+			if negated {
+				p++
+				if p == len(pattern) {
+					return abortAll
+				}
+
+				pch = pattern[p]
+			}
+
+			var (
+				prev    byte
+				matched bool
+			)
+
+			for {
+				switch {
+				case pch == '\\':
+					p++
+					if p == len(pattern) {
+						return abortAll
+					}
+
+					pch = pattern[p]
+
+					if tch == pch {
+						matched = true
+					}
+				case pch == '-' && prev != 0 && p+1 < len(pattern) && pattern[p+1] != ']':
+					p++
+					pch = pattern[p]
+
+					if pch == '\\' {
+						p++
+						if p == len(pattern) {
+							return abortAll
+						}
+
+						pch = pattern[p]
+					}
+
+					if inRange(tch, prev, pch, flags) {
+						matched = true
+					}
+
+					// Ranges cannot start a range.
+					pch = 0
+				case pch == '[' && p+1 < len(pattern) && pattern[p+1] == ':':
+					start := p + 2
+
+					end := start
+					for end < len(pattern) && pattern[end] != ']' {
+						end++
+					}
+
+					if end == len(pattern) {
+						return abortAll
+					}
+
+					if end-start < 1 || pattern[end-1] != ':' {
+						// Not a "[:class:]", so a literal "[".
+						if tch == '[' {
+							matched = true
+						}
+
+						break
+					}
+
+					in, ok := inClass(pattern[start:end-1], tch, flags)
+					if !ok {
+						// Malformed class name.
+						return abortAll
+					}
+
+					if in {
+						matched = true
+					}
+
+					p = end
+
+					// Classes cannot start a range.
+					pch = 0
+				default:
+					// Like with git, the characters of a class are not
+					// folded.
+					if tch == pch {
+						matched = true
+					}
+				}
+
+				prev = pch
+
+				p++
+				if p == len(pattern) {
+					return abortAll
+				}
+
+				pch = pattern[p]
+				if pch == ']' {
+					break
+				}
+			}
+
+			if matched == negated || flags&Pathname != 0 && tch == '/' {
+				return noMatch
+			}
+		default:
+			if tch != fold(pch, flags) {
+				return noMatch
+			}
+		}
+	}
+
+	if t < len(text) {
+		return noMatch
+	}
+
+	return match
+}
+
+// fold returns c in lower case if the match is case-insensitive.
+func fold(c byte, flags Flags) byte {
+	if flags&CaseFold != 0 && 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+
+	return c
+}
+
+// inRange reports whether c, folded with flags, is within the range from low to
+// high, matching the upper case form of c too if the match is
+// case-insensitive.
+func inRange(c, low, high byte, flags Flags) bool {
+	if low <= c && c <= high {
+		return true
+	}
+
+	if flags&CaseFold != 0 && 'a' <= c && c <= 'z' {
+		upper := c - 'a' + 'A'
+
+		return low <= upper && upper <= high
+	}
+
+	return false
+}
+
+// inClass reports whether c, folded with flags, belongs to the POSIX character
+// class name, such as "alpha", and whether the class exists.
+func inClass(name string, c byte, flags Flags) (bool, bool) {
+	switch name {
+	case "alnum":
+		return isAlpha(c) || isDigit(c), true
+	case "alpha":
+		return isAlpha(c), true
+	case "blank":
+		return c == ' ' || c == '\t', true
+	case "cntrl":
+		return c < ' ' || c == 0x7f, true
+	case "digit":
+		return isDigit(c), true
+	case "graph":
+		return '!' <= c && c <= '~', true
+	case "lower":
+		return 'a' <= c && c <= 'z', true
+	case "print":
+		return ' ' <= c && c <= '~', true
+	case "punct":
+		return '!' <= c && c <= '~' && !isAlpha(c) && !isDigit(c), true
+	case "space":
+		return c == ' ' || '\t' <= c && c <= '\r', true
+	case "upper":
+		// Folded text is in lower case.
+		return 'A' <= c && c <= 'Z' || flags&CaseFold != 0 && 'a' <= c && c <= 'z', true
+	case "xdigit":
+		return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F', true
+	default:
+		return false, false
+	}
+}
+
+// isAlpha reports whether c is an ASCII letter.
+func isAlpha(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// indexSlash returns the index of the first slash of s, or -1 if there is
+// none.
+func indexSlash(s string) int {
+	for i := range len(s) {
+		if s[i] == '/' {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,88 @@
+package wildmatch_test
+
+import (
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/wildmatch"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		givePattern string
+		giveText    string
+		giveFlags   wildmatch.Flags
+		want        bool
+	}{
+		// Literals and basic wildcards.
+		{name: "Literal", givePattern: "foo", giveText: "foo", want: true},
+		{name: "Literal mismatch", givePattern: "foo", giveText: "bar", want: false},
+		{name: "Empty", givePattern: "", giveText: "", want: true},
+		{name: "Question marks", givePattern: "???", giveText: "foo", want: true},
+		{name: "Too few question marks", givePattern: "??", giveText: "foo", want: false},
+		{name: "Star", givePattern: "*", giveText: "foo", want: true},
+		{name: "Leading literal and star", givePattern: "f*", giveText: "foo", want: true},
+		{name: "Star and trailing literal", givePattern: "*f", giveText: "foo", want: false},
+		{name: "Surrounding stars", givePattern: "*foo*", giveText: "foo", want: true},
+		{name: "Several stars", givePattern: "*ob*a*r*", giveText: "foobar", want: true},
+		{name: "Backtracking star", givePattern: "*ab", giveText: "aaaaaaabababab", want: true},
+		{name: "Escaped star", givePattern: `foo\*`, giveText: "foo*", want: true},
+		{name: "Escaped star is literal", givePattern: `foo\*bar`, giveText: "foobar", want: false},
+		{name: "Escaped backslash", givePattern: `f\\oo`, giveText: `f\oo`, want: true},
+		{name: "Trailing backslash", givePattern: `foo\`, giveText: `foo\`, want: false},
+
+		// Character classes.
+		{name: "Class", givePattern: "*[al]?", giveText: "ball", want: true},
+		{name: "Class matches one character", givePattern: "[ten]", giveText: "ten", want: false},
+		{name: "Negated class", givePattern: "[!a-c]", giveText: "d", want: true},
+		{name: "Caret negated class", givePattern: "[^a-c]", giveText: "b", want: false},
+		{name: "Range", givePattern: "[a-c]x", giveText: "bx", want: true},
+		{name: "Leading bracket is literal", givePattern: "[]]", giveText: "]", want: true},
+		{name: "Leading dash is literal", givePattern: "[-x]", giveText: "-", want: true},
+		{name: "Trailing dash is literal", givePattern: "[x-]", giveText: "-", want: true},
+		{name: "Escaped in class", givePattern: `[\]]`, giveText: "]", want: true},
+		{name: "Unterminated class", givePattern: "[ab", giveText: "a", want: false},
+		{name: "Named class", givePattern: "[[:digit:]]x", giveText: "7x", want: true},
+		{name: "Named class mismatch", givePattern: "[[:alpha:]]", giveText: "7", want: false},
+		{name: "Several named classes", givePattern: "[[:digit:][:upper:]]", giveText: "Q", want: true},
+		{name: "Unknown named class", givePattern: "[[:nope:]]", giveText: "a", want: false},
+		{name: "Not a named class", givePattern: "[[:]", giveText: ":", want: true},
+
+		// Slashes.
+		{name: "Star crosses slashes without Pathname", givePattern: "foo*bar", giveText: "foo/baz/bar", want: true},
+		{name: "Star stops at slashes", givePattern: "foo*bar", giveText: "foo/baz/bar", giveFlags: wildmatch.Pathname, want: false},
+		{name: "Question mark stops at slashes", givePattern: "foo?bar", giveText: "foo/bar", giveFlags: wildmatch.Pathname, want: false},
+		{name: "Class stops at slashes", givePattern: "foo[/]bar", giveText: "foo/bar", giveFlags: wildmatch.Pathname, want: false},
+		{name: "Star and slash", givePattern: "*/bar", giveText: "foo/bar", giveFlags: wildmatch.Pathname, want: true},
+		{name: "Star and slash stop at one directory", givePattern: "*/bar", giveText: "a/foo/bar", giveFlags: wildmatch.Pathname, want: false},
+		{name: "Double star matches no directory", givePattern: "foo/**/bar", giveText: "foo/bar", giveFlags: wildmatch.Pathname, want: true},
+		{name: "Double star matches directories", givePattern: "foo/**/bar", giveText: "foo/a/b/bar", giveFlags: wildmatch.Pathname, want: true},
+		{name: "Leading double star", givePattern: "**/foo", giveText: "a/b/foo", giveFlags: wildmatch.Pathname, want: true},
+		{name: "Leading double star at the root", givePattern: "**/foo", giveText: "foo", giveFlags: wildmatch.Pathname, want: true},
+		{name: "Trailing double star", givePattern: "foo/**", giveText: "foo/a/b", giveFlags: wildmatch.Pathname, want: true},
+		{name: "Trailing double star needs contents", givePattern: "foo/**", giveText: "foo", giveFlags: wildmatch.Pathname, want: false},
+		{name: "Double star inside a name is a star", givePattern: "foo**bar", giveText: "foo/bar", giveFlags: wildmatch.Pathname, want: false},
+		{name: "Double star inside a name", givePattern: "foo**bar", giveText: "fooxbar", giveFlags: wildmatch.Pathname, want: true},
+		{name: "Triple star", givePattern: "***/foo", giveText: "a/foo", giveFlags: wildmatch.Pathname, want: true},
+
+		// Case folding.
+		{name: "Case-sensitive", givePattern: "Foo", giveText: "foo", want: false},
+		{name: "Case-insensitive", givePattern: "Foo", giveText: "fOO", giveFlags: wildmatch.CaseFold, want: true},
+		{name: "Case-insensitive range", givePattern: "[A-Z]", giveText: "q", giveFlags: wildmatch.CaseFold, want: true},
+		{name: "Case-insensitive upper class", givePattern: "[[:upper:]]", giveText: "q", giveFlags: wildmatch.CaseFold, want: true},
+		{name: "Class characters are not folded", givePattern: "[A]", giveText: "A", giveFlags: wildmatch.CaseFold, want: false},
+		{name: "Escaped characters are not folded", givePattern: `\B`, giveText: "b", giveFlags: wildmatch.CaseFold, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := wildmatch.Match(tt.givePattern, tt.giveText, tt.giveFlags); got != tt.want {
+				t.Errorf("Match(%q, %q, %d) = %t, want %t", tt.givePattern, tt.giveText, tt.giveFlags, got, tt.want)
+			}
+		})
+	}
+}
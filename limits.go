@@ -0,0 +1,53 @@
+package gitignore
+
+import (
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+const (
+	// ErrLimitExceeded is wrapped by the *ParseError returned when a limit set
+	// with WithLimits is exceeded, along with one of the errors below naming
+	// the limit.
+	ErrLimitExceeded xerrors.Error = "limit exceeded"
+
+	// ErrTooManyPatterns reports a file holding more rules than
+	// Limits.MaxPatterns.
+	ErrTooManyPatterns = pattern.ErrTooManyPatterns
+
+	// ErrLineTooLong reports a line longer than Limits.MaxLineLength.
+	ErrLineTooLong = pattern.ErrLineTooLong
+
+	// ErrRegexTooLarge reports rules whose compiled regular expressions are
+	// larger than Limits.MaxRegexSize in total.
+	ErrRegexTooLarge = pattern.ErrRegexTooLarge
+)
+
+// Limits bounds the resources used to parse a .gitignore file, protecting
+// services parsing untrusted content against memory exhaustion. A zero field
+// sets no limit.
+type Limits struct {
+	// MaxPatterns is the largest number of rules a file may hold.
+	MaxPatterns int
+
+	// MaxLineLength is the length of the longest line accepted, in bytes.
+	// Without it, lines are limited to 64 KiB.
+	MaxLineLength int
+
+	// MaxRegexSize is the largest total length, in bytes, of the regular
+	// expressions the rules of a file compile to.
+	MaxRegexSize int
+}
+
+// WithLimits sets limits enforced while parsing. The constructors fail with a
+// *ParseError wrapping ErrLimitExceeded and the limit exceeded, such as
+// ErrTooManyPatterns, pointing at the line exceeding it.
+//
+// Along with WithPermissive, lines longer than Limits.MaxLineLength are skipped
+// and reported like other lines that cannot be parsed, while exceeding the
+// other limits is still fatal.
+func WithLimits(limits Limits) Option {
+	return func(o *options) {
+		o.limits = limits
+	}
+}
@@ -0,0 +1,200 @@
+package gitignore_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestWithLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		giveLines  []string
+		giveLimits gitignore.Limits
+		wantErr    error
+		wantLine   int
+	}{
+		{
+			name:       "No limits",
+			giveLines:  []string{"*.log", "*.tmp", strings.Repeat("a", 1024)},
+			giveLimits: gitignore.Limits{},
+		},
+		{
+			name:       "Within limits",
+			giveLines:  []string{"# comment", "*.log", "*.tmp"},
+			giveLimits: gitignore.Limits{MaxPatterns: 2, MaxLineLength: 9, MaxRegexSize: 100},
+		},
+		{
+			name:       "Too many patterns",
+			giveLines:  []string{"*.log", "", "# comment", "*.tmp", "*.bak"},
+			giveLimits: gitignore.Limits{MaxPatterns: 2},
+			wantErr:    gitignore.ErrTooManyPatterns,
+			wantLine:   5,
+		},
+		{
+			name:       "Line too long",
+			giveLines:  []string{"*.log", strings.Repeat("a", 11), "*.tmp"},
+			giveLimits: gitignore.Limits{MaxLineLength: 10},
+			wantErr:    gitignore.ErrLineTooLong,
+			wantLine:   2,
+		},
+		{
+			name:       "Last line too long",
+			giveLines:  []string{"*.log", strings.Repeat("a", 11)},
+			giveLimits: gitignore.Limits{MaxLineLength: 10},
+			wantErr:    gitignore.ErrLineTooLong,
+			wantLine:   2,
+		},
+		{
+			name:       "Regex too large",
+			giveLines:  []string{"*.log", "**/a/**/b/**/c/**/d"},
+			giveLimits: gitignore.Limits{MaxRegexSize: 40},
+			wantErr:    gitignore.ErrRegexTooLarge,
+			wantLine:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := gitignore.NewFromLines(tt.giveLines, gitignore.WithLimits(tt.giveLimits))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("NewFromLines() error = %v, want nil", err)
+				}
+
+				if !file.Match("debug.log") {
+					t.Errorf("Match(%q) = false, want true", "debug.log")
+				}
+
+				return
+			}
+
+			if !errors.Is(err, gitignore.ErrLimitExceeded) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewFromLines() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var parseErr *gitignore.ParseError
+			if !errors.As(err, &parseErr) || parseErr.Line != tt.wantLine {
+				t.Errorf("NewFromLines() error = %v, want a *ParseError at line %d", err, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestWithLimits_Permissive(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"*.log", strings.Repeat("a", 11), "*.tmp"}
+
+	var warnings []gitignore.Warning
+
+	file, err := gitignore.NewFromLines(lines,
+		gitignore.WithLimits(gitignore.Limits{MaxLineLength: 10}),
+		gitignore.WithPermissive(),
+		gitignore.WithWarnings(func(w gitignore.Warning) {
+			warnings = append(warnings, w)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v, want nil", err)
+	}
+
+	if file.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", file.Len())
+	}
+
+	if len(warnings) != 1 || warnings[0].Kind != gitignore.WarningLineTooLong || warnings[0].Line != 2 {
+		t.Errorf("warnings = %v, want a single line too long warning at line 2", warnings)
+	}
+
+	_, err = gitignore.NewFromLines([]string{"*.log", "*.tmp"},
+		gitignore.WithLimits(gitignore.Limits{MaxPatterns: 1}),
+		gitignore.WithPermissive(),
+	)
+	if !errors.Is(err, gitignore.ErrTooManyPatterns) {
+		t.Errorf("NewFromLines() error = %v, want %v even in permissive mode", err, gitignore.ErrTooManyPatterns)
+	}
+}
+
+func TestWithLimits_AddLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		giveLines  []string
+		giveAdded  []string
+		giveLimits gitignore.Limits
+		wantErr    error
+		wantLine   int
+	}{
+		{
+			name:       "Within limits",
+			giveLines:  []string{"*.log"},
+			giveAdded:  []string{"*.tmp"},
+			giveLimits: gitignore.Limits{MaxPatterns: 2},
+		},
+		{
+			name:       "Too many patterns",
+			giveLines:  []string{"*.log"},
+			giveAdded:  []string{"", "*.tmp"},
+			giveLimits: gitignore.Limits{MaxPatterns: 1},
+			wantErr:    gitignore.ErrTooManyPatterns,
+			wantLine:   2,
+		},
+		{
+			name:       "Line too long",
+			giveLines:  []string{"*.log"},
+			giveAdded:  []string{strings.Repeat("a", 11)},
+			giveLimits: gitignore.Limits{MaxLineLength: 10},
+			wantErr:    gitignore.ErrLineTooLong,
+			wantLine:   1,
+		},
+		{
+			name:       "Regex too large",
+			giveLines:  []string{"**/a/**/b"},
+			giveAdded:  []string{"**/c/**/d"},
+			giveLimits: gitignore.Limits{MaxRegexSize: 40},
+			wantErr:    gitignore.ErrRegexTooLarge,
+			wantLine:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := gitignore.NewFromLines(tt.giveLines, gitignore.WithLimits(tt.giveLimits))
+			if err != nil {
+				t.Fatalf("NewFromLines() error = %v", err)
+			}
+
+			err = file.AddLines(tt.giveAdded...)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("AddLines() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, gitignore.ErrLimitExceeded) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddLines() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var parseErr *gitignore.ParseError
+			if !errors.As(err, &parseErr) || parseErr.Line != tt.wantLine {
+				t.Errorf("AddLines() error = %v, want a *ParseError at line %d", err, tt.wantLine)
+			}
+
+			if file.Len() != len(tt.giveLines) {
+				t.Errorf("Len() = %d after a failed AddLines(), want %d", file.Len(), len(tt.giveLines))
+			}
+		})
+	}
+}
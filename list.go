@@ -0,0 +1,300 @@
+package gitignore
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ListIgnored returns the slash-separated paths of the files and directories
+// below root that f ignores, relative to root, in lexical order, so cleanup
+// tools can remove them like git clean -X does. Only the rules of f apply, with
+// paths relative to root; use [Repository.ListIgnored] to also apply the
+// .gitignore files of the directories below.
+//
+// Like git status --ignored=matching, an ignored directory is reported as a single
+// entry with a trailing slash, without being read, unless WithExpandedDirs is
+// given, in which case every file inside it is reported instead. Like with
+// git, the .git directories are never reported.
+//
+// Directories that cannot be read are handled according to the error policy set
+// by opts, failing on the first one by default.
+func (f *File) ListIgnored(root string, opts ...WalkOption) ([]string, error) {
+	w, err := newTreeWalker(root, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.listIgnored(".", f)
+}
+
+// ListIncluded returns the slash-separated paths of the files below root that
+// f does not ignore, relative to root, in lexical order, which are the files
+// packagers, uploaders and archivers should pick. Ignored directories are
+// pruned without being read, and the .git directories are skipped. Only the
+// rules of f apply, with paths relative to root; use
+// [Repository.ListIncluded] to also apply the .gitignore files of the
+// directories below.
+//
+// Directories that cannot be read are handled according to the error policy set
+// by opts, failing on the first one by default.
+func (f *File) ListIncluded(root string, opts ...WalkOption) ([]string, error) {
+	w, err := newTreeWalker(root, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.listIncluded(".", f)
+}
+
+// ListIgnored is like [File.ListIgnored], listing the ignored paths below dir,
+// a slash-separated path relative to the root of the working tree of r, such as
+// ".". The paths are relative to the root of the working tree. It returns
+// [ErrOutsideBase] if dir is not within the working tree, and [ErrBare] if r
+// has none.
+func (r *Repository) ListIgnored(dir string, opts ...WalkOption) ([]string, error) {
+	dir, err := r.walkRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWalker(r.root, opts).listIgnored(dir, r)
+}
+
+// ListIncluded is like [File.ListIncluded], listing the files below dir, a
+// slash-separated path relative to the root of the working tree of r, such as
+// ".", that r does not ignore. The paths are relative to the root of the
+// working tree. It returns [ErrOutsideBase] if dir is not within the working
+// tree, and [ErrBare] if r has none.
+func (r *Repository) ListIncluded(dir string, opts ...WalkOption) ([]string, error) {
+	dir, err := r.walkRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWalker(r.root, opts).listIncluded(dir, r)
+}
+
+// walkRoot returns the clean form of dir, a directory of the working tree of r
+// to walk from.
+func (r *Repository) walkRoot(dir string) (string, error) {
+	if r.bare {
+		return "", ErrBare
+	}
+
+	dir = path.Clean(filepath.ToSlash(dir))
+
+	if dir == ".." || strings.HasPrefix(dir, "../") || path.IsAbs(dir) {
+		return "", fmt.Errorf("%w: %s", ErrOutsideBase, dir)
+	}
+
+	return dir, nil
+}
+
+// listIgnored returns the paths below dir that m ignores.
+func (w *walker) listIgnored(dir string, m Matcher) ([]string, error) {
+	defer w.opts.startStats()()
+
+	w.start = dir
+
+	ignored := make([]string, 0)
+
+	// every records every file inside an ignored directory, skipping the
+	// nested repositories it holds.
+	every := func(name string, d fs.DirEntry) (bool, error) {
+		if d.Name() == gitDir {
+			return false, nil
+		}
+
+		if d.IsDir() {
+			return !w.opts.deepest(w.depth(name)), nil
+		}
+
+		w.opts.count(d, true)
+
+		ignored = append(ignored, w.path(name))
+
+		return false, nil
+	}
+
+	err := w.walk(dir, func(name string, d fs.DirEntry) (bool, error) {
+		if d.Name() == gitDir {
+			return false, nil
+		}
+
+		if !d.IsDir() {
+			match := m.Match(name)
+			if match {
+				ignored = append(ignored, w.path(name))
+			}
+
+			w.opts.count(d, match)
+
+			return false, nil
+		}
+
+		if !m.Match(name + "/") {
+			return true, nil
+		}
+
+		if w.opts.expandDirs {
+			if w.opts.deepest(w.depth(name)) {
+				return false, nil
+			}
+
+			return false, w.walk(name, every)
+		}
+
+		w.opts.count(d, true)
+
+		ignored = append(ignored, w.path(name+"/"))
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ignored, w.opts.err()
+}
+
+// listIncluded returns the files below dir that m does not ignore.
+func (w *walker) listIncluded(dir string, m Matcher) ([]string, error) {
+	defer w.opts.startStats()()
+
+	w.start = dir
+
+	included := make([]string, 0)
+
+	err := w.walk(dir, func(name string, d fs.DirEntry) (bool, error) {
+		if d.Name() == gitDir {
+			return false, nil
+		}
+
+		if d.IsDir() {
+			prune := m.Match(name + "/")
+
+			w.opts.count(d, prune)
+
+			return !prune, nil
+		}
+
+		match := m.Match(name)
+		if !match {
+			included = append(included, w.path(name))
+		}
+
+		w.opts.count(d, match)
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return included, w.opts.err()
+}
+
+// walker walks the directories of a file system depth first, in lexical order,
+// handling the errors reading them according to its options.
+type walker struct {
+	fsys fs.FS
+	opts *walkOptions
+
+	// root is the absolute path of the directory fsys is rooted at, which
+	// paths are resolved against when absolute paths are requested.
+	root string
+
+	// start is the directory the listing starts from, which depths are
+	// counted from.
+	start string
+}
+
+// newWalker returns a walker of the directory root, an absolute path,
+// configured by opts.
+func newWalker(root string, opts []WalkOption) *walker {
+	return &walker{
+		fsys: os.DirFS(root),
+		opts: newWalkOptions(opts),
+		root: root,
+	}
+}
+
+// newTreeWalker is like newWalker, for a root that may be relative to the
+// current working directory.
+func newTreeWalker(root string, opts []WalkOption) (*walker, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return newWalker(abs, opts), nil
+}
+
+// path returns name, a slash-separated path relative to the root of w, in the
+// form requested by the options of w. Directories keep their trailing slash,
+// as a trailing separator.
+func (w *walker) path(name string) string {
+	if !w.opts.absolute {
+		return name
+	}
+
+	abs := filepath.Join(w.root, filepath.FromSlash(name))
+
+	if strings.HasSuffix(name, "/") {
+		abs += string(filepath.Separator)
+	}
+
+	return abs
+}
+
+// depth returns the depth of name below the directory the listing starts
+// from.
+func (w *walker) depth(name string) int {
+	if w.start != "." {
+		name = strings.TrimPrefix(name, w.start+"/")
+	}
+
+	return strings.Count(name, "/") + 1
+}
+
+// walk calls fn for every entry below dir, with its slash-separated path, and
+// descends into the directories fn returns true for.
+func (w *walker) walk(dir string, fn func(name string, d fs.DirEntry) (bool, error)) error {
+	if err := w.opts.canceled(); err != nil {
+		return err
+	}
+
+	entries, err := fs.ReadDir(w.fsys, dir)
+	if err != nil {
+		return w.opts.handle(dir, fmt.Errorf("%w", err))
+	}
+
+	w.opts.sort(entries)
+
+	for _, d := range entries {
+		name := path.Join(dir, d.Name())
+
+		if w.opts.pruned(name, d) {
+			continue
+		}
+
+		descend, err := fn(name, d)
+		if err != nil {
+			return err
+		}
+
+		if !descend || !d.IsDir() || w.opts.deepest(w.depth(name)) {
+			continue
+		}
+
+		if err = w.walk(name, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
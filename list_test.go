@@ -0,0 +1,302 @@
+package gitignore_test
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+// listTree is a working tree whose ignored paths were checked against git
+// status --ignored=matching.
+//
+//nolint:gochecknoglobals // Read-only test table.
+var listTree = map[string]string{
+	".git/config":         "",
+	".gitignore":          "*.log\nbuild/\nnode_modules/\n!keep.log\n",
+	"main.go":             "",
+	"a.log":               "",
+	"keep.log":            "",
+	"build/app":           "",
+	"build/sub/o":         "",
+	"node_modules/x/i.js": "",
+	"pkg/.gitignore":      "*.tmp\ngen/\n",
+	"pkg/a.tmp":           "",
+	"pkg/b.go":            "",
+	"pkg/gen/z.go":        "",
+	"src/c.log":           "",
+}
+
+func TestRepository_ListIgnored(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, listTree)
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		giveDir  string
+		giveOpts []gitignore.WalkOption
+		want     []string
+	}{
+		{
+			name:    "Root",
+			giveDir: ".",
+			want:    []string{"a.log", "build/", "node_modules/", "pkg/a.tmp", "pkg/gen/", "src/c.log"},
+		},
+		{
+			name:     "Expanded directories",
+			giveDir:  ".",
+			giveOpts: []gitignore.WalkOption{gitignore.WithExpandedDirs()},
+			want: []string{
+				"a.log", "build/app", "build/sub/o", "node_modules/x/i.js", "pkg/a.tmp", "pkg/gen/z.go", "src/c.log",
+			},
+		},
+		{
+			name:    "Subdirectory",
+			giveDir: "pkg/",
+			want:    []string{"pkg/a.tmp", "pkg/gen/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repo.ListIgnored(tt.giveDir, tt.giveOpts...)
+			if err != nil {
+				t.Fatalf("ListIgnored() error = %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListIgnored() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err = repo.ListIgnored("../other"); !errors.Is(err, gitignore.ErrOutsideBase) {
+		t.Errorf("ListIgnored() error = %v, want %v", err, gitignore.ErrOutsideBase)
+	}
+}
+
+func TestFile_ListIgnored(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, listTree)
+
+	file, err := gitignore.New(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got, err := file.ListIgnored(root)
+	if err != nil {
+		t.Fatalf("ListIgnored() error = %v", err)
+	}
+
+	// The .gitignore file of pkg does not apply.
+	want := []string{"a.log", "build/", "node_modules/", "src/c.log"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("ListIgnored() = %q, want %q", got, want)
+	}
+
+	if _, err = file.ListIgnored(filepath.Join(root, "missing")); err == nil {
+		t.Error("ListIgnored() error = nil, want an error")
+	}
+
+	got, err = file.ListIgnored(filepath.Join(root, "missing"), gitignore.WithSkipErrors())
+	if err == nil || len(got) != 0 {
+		t.Errorf("ListIgnored() = %q, %v, want no path and an error", got, err)
+	}
+}
+
+func TestFile_ListIgnored_NestedGitDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"vendor/foo/.git/HEAD":         "ref: refs/heads/main\n",
+		"vendor/foo/.git/objects/pack": "",
+		"vendor/foo/foo.go":            "",
+		"vendor/foo/sub/bar.go":        "",
+	})
+
+	file, err := gitignore.NewFromLines([]string{"vendor/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		giveOpts []gitignore.WalkOption
+		want     []string
+	}{
+		{
+			name:     "Expanded directories",
+			giveOpts: []gitignore.WalkOption{gitignore.WithExpandedDirs()},
+			want:     []string{"vendor/foo/foo.go", "vendor/foo/sub/bar.go"},
+		},
+		{
+			name:     "Expanded directories with maximum depth",
+			giveOpts: []gitignore.WalkOption{gitignore.WithExpandedDirs(), gitignore.WithMaxDepth(3)},
+			want:     []string{"vendor/foo/foo.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := file.ListIgnored(root, tt.giveOpts...)
+			if err != nil {
+				t.Fatalf("ListIgnored() error = %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListIgnored() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_ListIgnored_Bare(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"HEAD":             "ref: refs/heads/main\n",
+		"objects/.keep":    "",
+		"refs/heads/.keep": "",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	if _, err = repo.ListIgnored("."); !errors.Is(err, gitignore.ErrBare) {
+		t.Errorf("ListIgnored() error = %v, want %v", err, gitignore.ErrBare)
+	}
+}
+
+func TestRepository_ListIncluded(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, listTree)
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		giveDir  string
+		giveOpts []gitignore.WalkOption
+		want     []string
+	}{
+		{
+			name:    "Root",
+			giveDir: ".",
+			want:    []string{".gitignore", "keep.log", "main.go", "pkg/.gitignore", "pkg/b.go"},
+		},
+		{
+			name:    "Subdirectory",
+			giveDir: "pkg",
+			want:    []string{"pkg/.gitignore", "pkg/b.go"},
+		},
+		{
+			name:     "Absolute paths",
+			giveDir:  "pkg",
+			giveOpts: []gitignore.WalkOption{gitignore.WithAbsolutePaths()},
+			want: []string{
+				filepath.Join(root, "pkg", ".gitignore"),
+				filepath.Join(root, "pkg", "b.go"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repo.ListIncluded(tt.giveDir, tt.giveOpts...)
+			if err != nil {
+				t.Fatalf("ListIncluded() error = %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListIncluded() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFile_ListIncluded(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, listTree)
+
+	file, err := gitignore.New(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got, err := file.ListIncluded(root)
+	if err != nil {
+		t.Fatalf("ListIncluded() error = %v", err)
+	}
+
+	// The .gitignore file of pkg does not apply.
+	want := []string{".gitignore", "keep.log", "main.go", "pkg/.gitignore", "pkg/a.tmp", "pkg/b.go", "pkg/gen/z.go"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("ListIncluded() = %q, want %q", got, want)
+	}
+}
+
+func TestFile_ListIgnored_AbsolutePaths(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, listTree)
+
+	file, err := gitignore.New(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got, err := file.ListIgnored(root, gitignore.WithAbsolutePaths())
+	if err != nil {
+		t.Fatalf("ListIgnored() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(root, "a.log"),
+		filepath.Join(root, "build") + string(filepath.Separator),
+		filepath.Join(root, "node_modules") + string(filepath.Separator),
+		filepath.Join(root, "src", "c.log"),
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("ListIgnored() = %q, want %q", got, want)
+	}
+}
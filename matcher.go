@@ -0,0 +1,161 @@
+package gitignore
+
+import "reflect"
+
+// Matcher is the interface implemented by types that can decide whether a path
+// is ignored.
+//
+// Paths given to Match are slash-separated and relative to the directory the
+// rules apply to. Directories are identified by a trailing slash, so "build/"
+// refers to a directory and "build" to a file.
+type Matcher interface {
+	// Match reports whether the given path is ignored.
+	Match(path string) bool
+}
+
+// IsIgnored reports whether name, or any of its parent directories, is ignored
+// by m. Git never looks inside an excluded directory, so anything below one is
+// ignored as well, even if a negated pattern matches it.
+//
+// The name must be slash-separated, relative, and without a trailing slash;
+// isDir tells whether it refers to a directory.
+func IsIgnored(m Matcher, name string, isDir bool) bool {
+	for i := range len(name) {
+		if name[i] == '/' && m.Match(name[:i+1]) {
+			return true
+		}
+	}
+
+	if isDir {
+		name += "/"
+	}
+
+	return m.Match(name)
+}
+
+// MatcherFunc is an adapter to allow the use of ordinary functions as matchers,
+// such as predicates ignoring files over a size limit or binary files.
+type MatcherFunc func(path string) bool
+
+// Match implements [Matcher] by calling fn(path).
+func (fn MatcherFunc) Match(path string) bool {
+	return fn(path)
+}
+
+// decider is implemented by matchers that can tell a path that is not ignored
+// apart from one they have no opinion on, like gitignore rules where a negated
+// pattern re-includes a path.
+type decider interface {
+	// decide reports whether path is ignored and whether the matcher had an
+	// opinion on it at all.
+	decide(path string) (bool, bool)
+}
+
+// Chain returns a matcher that layers matchers the way git layers ignore files:
+// later matchers take precedence over earlier ones, so a negated pattern in a
+// later [File] re-includes a path an earlier matcher ignored.
+//
+// Matchers without a notion of negation, such as a [MatcherFunc], can only ever
+// add ignored paths. Nil matchers are skipped, including typed nils such as a
+// nil *File.
+func Chain(matchers ...Matcher) Matcher { //nolint:ireturn // The combined matcher is only useful behind the Matcher interface.
+	return chain(compact(matchers))
+}
+
+// Any returns a matcher that ignores a path if at least one of the given
+// matchers ignores it. Nil matchers are skipped, including typed nils.
+func Any(matchers ...Matcher) Matcher { //nolint:ireturn // The combined matcher is only useful behind the Matcher interface.
+	return anyMatcher(compact(matchers))
+}
+
+// All returns a matcher that ignores a path only if every one of the given
+// matchers ignores it. Nil matchers are skipped, including typed nils, and a
+// matcher with no matchers ignores nothing.
+func All(matchers ...Matcher) Matcher { //nolint:ireturn // The combined matcher is only useful behind the Matcher interface.
+	return allMatcher(compact(matchers))
+}
+
+type chain []Matcher
+
+// Match implements [Matcher].
+func (c chain) Match(path string) bool {
+	ignored, _ := c.decide(path)
+
+	return ignored
+}
+
+func (c chain) decide(path string) (bool, bool) {
+	for i := len(c) - 1; i >= 0; i-- {
+		if d, ok := c[i].(decider); ok {
+			if ignored, matched := d.decide(path); matched {
+				return ignored, true
+			}
+
+			continue
+		}
+
+		if c[i].Match(path) {
+			return true, true
+		}
+	}
+
+	return false, false
+}
+
+type anyMatcher []Matcher
+
+// Match implements [Matcher].
+func (a anyMatcher) Match(path string) bool {
+	for _, m := range a {
+		if m.Match(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type allMatcher []Matcher
+
+// Match implements [Matcher].
+func (a allMatcher) Match(path string) bool {
+	if len(a) == 0 {
+		return false
+	}
+
+	for _, m := range a {
+		if !m.Match(path) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compact returns a copy of matchers without nil values.
+func compact(matchers []Matcher) []Matcher {
+	compacted := make([]Matcher, 0, len(matchers))
+
+	for _, m := range matchers {
+		if !isNil(m) {
+			compacted = append(compacted, m)
+		}
+	}
+
+	return compacted
+}
+
+// isNil reports whether m is nil, or holds a nil pointer, function, map or
+// slice, such as a nil *File, whose methods would panic.
+func isNil(m Matcher) bool {
+	if m == nil {
+		return true
+	}
+
+	switch v := reflect.ValueOf(m); v.Kind() { //nolint:exhaustive // Other kinds cannot be nil.
+	case reflect.Pointer, reflect.Func, reflect.Map, reflect.Slice, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
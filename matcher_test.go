@@ -0,0 +1,188 @@
+package gitignore_test
+
+import (
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func newTestMatcher(t *testing.T, lines ...string) *gitignore.File {
+	t.Helper()
+
+	matcher, err := gitignore.NewFromLines(lines)
+	if err != nil {
+		t.Fatalf("failed to create matcher: %v", err)
+	}
+
+	return matcher
+}
+
+func TestIsIgnored(t *testing.T) {
+	t.Parallel()
+
+	matcher := newTestMatcher(t, "build/", "*.log", "!keep.log")
+
+	tests := []struct {
+		name      string
+		givePath  string
+		giveIsDir bool
+		want      bool
+	}{
+		{
+			name:     "Included file",
+			givePath: "main.go",
+			want:     false,
+		},
+		{
+			name:     "Ignored file",
+			givePath: "debug.log",
+			want:     true,
+		},
+		{
+			name:      "Directory-only pattern on directory",
+			givePath:  "build",
+			giveIsDir: true,
+			want:      true,
+		},
+		{
+			name:     "Directory-only pattern on file",
+			givePath: "build",
+			want:     false,
+		},
+		{
+			name:     "Negated file inside ignored directory",
+			givePath: "build/keep.log",
+			want:     true,
+		},
+		{
+			name:     "Negated file",
+			givePath: "src/keep.log",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := gitignore.IsIgnored(matcher, tt.givePath, tt.giveIsDir)
+			if got != tt.want {
+				t.Errorf("IsIgnored(%q, %v) = %v, want %v", tt.givePath, tt.giveIsDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logs    = newTestMatcher(t, "*.log")
+		keep    = newTestMatcher(t, "!keep.log")
+		vendor  = gitignore.MatcherFunc(func(path string) bool { return strings.HasPrefix(path, "vendor/") })
+		goFiles = gitignore.MatcherFunc(func(path string) bool { return strings.HasSuffix(path, ".go") })
+	)
+
+	tests := []struct {
+		name        string
+		giveMatcher gitignore.Matcher
+		givePath    string
+		want        bool
+	}{
+		{
+			name:        "Chain with later negation",
+			giveMatcher: gitignore.Chain(logs, keep),
+			givePath:    "keep.log",
+			want:        false,
+		},
+		{
+			name:        "Chain with earlier negation",
+			giveMatcher: gitignore.Chain(keep, logs),
+			givePath:    "keep.log",
+			want:        true,
+		},
+		{
+			name:        "Chain falls through matchers without opinion",
+			giveMatcher: gitignore.Chain(logs, keep, vendor),
+			givePath:    "debug.log",
+			want:        true,
+		},
+		{
+			name:        "Chain with predicate",
+			giveMatcher: gitignore.Chain(logs, nil, vendor),
+			givePath:    "vendor/lib.go",
+			want:        true,
+		},
+		{
+			name:        "Chain with typed nil",
+			giveMatcher: gitignore.Chain(logs, (*gitignore.File)(nil)),
+			givePath:    "debug.log",
+			want:        true,
+		},
+		{
+			name:        "Nested chain keeps negations",
+			giveMatcher: gitignore.Chain(logs, gitignore.Chain(vendor, keep)),
+			givePath:    "keep.log",
+			want:        false,
+		},
+		{
+			name:        "Empty chain",
+			giveMatcher: gitignore.Chain(),
+			givePath:    "debug.log",
+			want:        false,
+		},
+		{
+			name:        "Any with one match",
+			giveMatcher: gitignore.Any(logs, vendor),
+			givePath:    "vendor/lib.go",
+			want:        true,
+		},
+		{
+			name:        "Any with typed nil",
+			giveMatcher: gitignore.Any(gitignore.MatcherFunc(nil), logs),
+			givePath:    "debug.log",
+			want:        true,
+		},
+		{
+			name:        "Any without match",
+			giveMatcher: gitignore.Any(logs, vendor),
+			givePath:    "main.go",
+			want:        false,
+		},
+		{
+			name:        "All with every match",
+			giveMatcher: gitignore.All(vendor, goFiles),
+			givePath:    "vendor/lib.go",
+			want:        true,
+		},
+		{
+			name:        "All with partial match",
+			giveMatcher: gitignore.All(vendor, goFiles),
+			givePath:    "main.go",
+			want:        false,
+		},
+		{
+			name:        "All with typed nil",
+			giveMatcher: gitignore.All(vendor, (*gitignore.File)(nil), goFiles),
+			givePath:    "vendor/lib.go",
+			want:        true,
+		},
+		{
+			name:        "Empty All",
+			giveMatcher: gitignore.All(),
+			givePath:    "main.go",
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.giveMatcher.Match(tt.givePath); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package gitignore
+
+import (
+	"strings"
+	"sync"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+)
+
+// regexSyntax holds the characters of rules the regular expression engine
+// passes through to their expression.
+const regexSyntax string = `\[]{}()+|^$`
+
+// DirMemo is a Matcher answering like a File, remembering the decisions it
+// takes for directories, so the paths below a directory known to be ignored
+// are answered without evaluating any rule. A directory is only remembered as
+// ignored when no negated rule comes after the rule ignoring it, as such a rule
+// could re-include a path below it; the paths below other directories are
+// matched against the rules as usual.
+//
+// Every directory above a path is decided once, the first time a path below it
+// is matched, and remembered until Reset is called, so a DirMemo suits
+// walkers and tools matching many paths below a few directories, such as the
+// files of node_modules or build outputs.
+//
+// A DirMemo is safe for concurrent use.
+type DirMemo struct {
+	file *File
+
+	// dirs maps the clean paths of the directories decided so far, with
+	// their trailing slash, to whether every path below them is ignored.
+	dirs map[string]bool
+	mu   sync.RWMutex
+}
+
+// NewDirMemo returns a DirMemo answering like f. It works on a copy of f, so
+// changes made to f afterwards do not apply.
+func NewDirMemo(f *File) *DirMemo {
+	return &DirMemo{
+		file: f.Clone(),
+		dirs: make(map[string]bool),
+	}
+}
+
+// Match implements [Matcher].
+func (m *DirMemo) Match(path string) bool {
+	ignored, _ := m.decide(path)
+
+	return ignored
+}
+
+// Reset forgets every directory decided so far.
+func (m *DirMemo) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.dirs)
+}
+
+// decide reports whether path is ignored and whether any rule matched it.
+func (m *DirMemo) decide(path string) (bool, bool) {
+	path = m.file.clean(path)
+
+	for i := range len(path) {
+		if path[i] == '/' && i < len(path)-1 && m.ignoredDir(path[:i+1]) {
+			return true, true
+		}
+	}
+
+	pat := m.file.decidingClean(path)
+	if pat == nil {
+		return false, false
+	}
+
+	return !pat.Negate, true
+}
+
+// ignoredDir reports whether every path below dir, a clean path with a
+// trailing slash, is ignored, deciding it the first time.
+func (m *DirMemo) ignoredDir(dir string) bool {
+	m.mu.RLock()
+	ignored, ok := m.dirs[dir]
+	m.mu.RUnlock()
+
+	if ok {
+		return ignored
+	}
+
+	pat := m.file.decidingClean(dir)
+	ignored = pat != nil && !pat.Negate && m.final(pat)
+
+	m.mu.Lock()
+	m.dirs[dir] = ignored
+	m.mu.Unlock()
+
+	return ignored
+}
+
+// final reports whether the paths below a directory pat, a rule of the File of
+// m, ignores, are ignored too, which is when pat matches them as well and no
+// negated rule comes after it.
+func (m *DirMemo) final(pat *pattern.Pattern) bool {
+	if pat == m.file.gitDir {
+		// It takes precedence over every rule.
+		return true
+	}
+
+	// The rules matching a directory match the paths below it, except for
+	// the ones the regular expression engine passes regular expression
+	// syntax through for, such as d[/], which only matches d/ itself.
+	if !pat.Wildmatch && strings.ContainsAny(pat.Raw, regexSyntax) {
+		return false
+	}
+
+	patterns := m.file.patterns
+
+	for i := len(patterns) - 1; i >= 0 && patterns[i] != pat; i-- {
+		if patterns[i].Negate {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,158 @@
+package gitignore_test
+
+import (
+	"sync"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestDirMemo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveLines []string
+		givePaths []string
+		want      []bool
+	}{
+		{
+			name:      "Ignored directory",
+			giveLines: []string{"node_modules/"},
+			givePaths: []string{"node_modules/", "node_modules/a/b.js", "node_modules/c.js", "src/a.js"},
+			want:      []bool{true, true, true, false},
+		},
+		{
+			name:      "Later negation",
+			giveLines: []string{"build/", "!build/keep"},
+			givePaths: []string{"build/a.o", "build/keep", "build/a.o"},
+			want:      []bool{true, false, true},
+		},
+		{
+			name:      "Earlier negation",
+			giveLines: []string{"!build/keep", "build/"},
+			givePaths: []string{"build/a.o", "build/keep"},
+			want:      []bool{true, true},
+		},
+		{
+			name:      "Re-included directory",
+			giveLines: []string{"out/", "!out/"},
+			givePaths: []string{"out/a", "out/"},
+			want:      []bool{false, false},
+		},
+		{
+			name:      "Regular expression syntax",
+			giveLines: []string{"d[/]"},
+			givePaths: []string{"d/", "d/x"},
+			want:      []bool{true, false},
+		},
+		{
+			name:      "Files only",
+			giveLines: []string{"*.log"},
+			givePaths: []string{"a.log", "logs/a.log", "logs/a.txt"},
+			want:      []bool{true, true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := gitignore.NewFromLines(tt.giveLines)
+			if err != nil {
+				t.Fatalf("NewFromLines() error = %v", err)
+			}
+
+			memo := gitignore.NewDirMemo(f)
+
+			for i, path := range tt.givePaths {
+				if got := memo.Match(path); got != tt.want[i] {
+					t.Errorf("Match(%q) = %v, want %v", path, got, tt.want[i])
+				}
+
+				if got := f.Match(path); got != tt.want[i] {
+					t.Errorf("File.Match(%q) = %v, want %v", path, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDirMemo_Copy(t *testing.T) {
+	t.Parallel()
+
+	f, err := gitignore.NewFromLines([]string{"vendor/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	memo := gitignore.NewDirMemo(f)
+
+	if err = f.AddLines("!vendor/"); err != nil {
+		t.Fatalf("AddLines() error = %v", err)
+	}
+
+	if !memo.Match("vendor/a.go") {
+		t.Error("Match() = false after the File changed, want true")
+	}
+
+	memo.Reset()
+
+	if !memo.Match("vendor/a.go") {
+		t.Error("Match() = false after Reset, want true")
+	}
+}
+
+func TestDirMemo_Chain(t *testing.T) {
+	t.Parallel()
+
+	base, err := gitignore.NewFromLines([]string{"dist/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	override, err := gitignore.NewFromLines([]string{"!dist/keep"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	m := gitignore.Chain(gitignore.NewDirMemo(base), override)
+
+	if m.Match("dist/keep") {
+		t.Error(`Match("dist/keep") = true, want false`)
+	}
+
+	if !m.Match("dist/a") {
+		t.Error(`Match("dist/a") = false, want true`)
+	}
+}
+
+func TestDirMemo_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	f, err := gitignore.NewFromLines([]string{"target/", "*.tmp"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	var (
+		memo = gitignore.NewDirMemo(f)
+		wg   sync.WaitGroup
+	)
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for _, path := range []string{"target/a/b", "src/a.tmp", "src/main.rs", "target/"} {
+				if got, want := memo.Match(path), f.Match(path); got != want {
+					t.Errorf("Match(%q) = %v, want %v", path, got, want)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,61 @@
+package gitignore
+
+import (
+	"path"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+)
+
+// Merge returns a File holding the rules of every given file, in order, the
+// way git layers the global excludes file, the repository's exclude file, and
+// .gitignore files: rules of later files take precedence over earlier ones, so
+// a negated rule in a later file re-includes a path an earlier file ignored,
+// and a rule in a later file ignores a path an earlier file re-included.
+//
+// Unlike concatenating lines, every rule keeps its source and line number. The
+// given files are not modified, and nil files are skipped. Every file must
+// apply to the same directory, which the prefix set by WithPrefix changes, so
+// the rules of a parent directory can be merged with the ones of a child
+// directory scoped to it. Other settings, such as options given to the
+// constructors, are not kept.
+func Merge(files ...*File) *File {
+	var (
+		patterns = make([]*pattern.Pattern, 0)
+		skipped  pattern.Counts
+		layer    int
+	)
+
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+
+		skipped.Comments += file.skipped.Comments
+		skipped.Blanks += file.skipped.Blanks
+
+		if len(file.patterns) == 0 {
+			continue
+		}
+
+		for _, pat := range file.patterns {
+			clone := *pat
+			clone.Layer += layer
+
+			// Paths given to the merged File are first joined with the
+			// prefix of file, then with the one of the pattern.
+			if file.prefix != "" {
+				clone.Prefix = path.Join(clone.Prefix, file.prefix)
+			}
+
+			patterns = append(patterns, &clone)
+		}
+
+		layer = patterns[len(patterns)-1].Layer + 1
+	}
+
+	return &File{
+		patterns: patterns,
+		skipped:  skipped,
+		index:    newIndex(patterns),
+	}
+}
@@ -0,0 +1,136 @@
+package gitignore_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"global":     {Data: []byte("*.swp\n*.log\n!important.log\n")},
+		".gitignore": {Data: []byte("important.log\n!debug.log\nbuild/\n")},
+		"local":      {Data: []byte("!build/keep\n")},
+	}
+
+	var files []*gitignore.File
+
+	for _, name := range []string{"global", ".gitignore", "local"} {
+		file, err := gitignore.NewFromFS(fsys, name)
+		if err != nil {
+			t.Fatalf("NewFromFS(%q) error = %v", name, err)
+		}
+
+		files = append(files, file)
+	}
+
+	merged := gitignore.Merge(files[0], nil, files[1], files[2])
+
+	tests := []struct {
+		givePath   string
+		wantMatch  bool
+		wantSource string
+		wantLine   int
+	}{
+		{givePath: "x.swp", wantMatch: true, wantSource: "global", wantLine: 1},
+		{givePath: "trace.log", wantMatch: true, wantSource: "global", wantLine: 2},
+		{givePath: "important.log", wantMatch: true, wantSource: ".gitignore", wantLine: 1},
+		{givePath: "debug.log", wantMatch: false, wantSource: ".gitignore", wantLine: 2},
+		{givePath: "build/out", wantMatch: true, wantSource: ".gitignore", wantLine: 3},
+		{givePath: "build/keep", wantMatch: false, wantSource: "local", wantLine: 1},
+		{givePath: "main.go", wantMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.givePath, func(t *testing.T) {
+			t.Parallel()
+
+			if got := merged.Match(tt.givePath); got != tt.wantMatch {
+				t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.wantMatch)
+			}
+
+			if got, want := merged.Match(tt.givePath), gitignore.Chain(files[0], files[1], files[2]).Match(tt.givePath); got != want {
+				t.Errorf("Match(%q) = %v, disagrees with Chain = %v", tt.givePath, got, want)
+			}
+
+			result, _ := merged.MatchResult(tt.givePath)
+			if result.Pattern.Source != tt.wantSource || result.Pattern.Line != tt.wantLine {
+				t.Errorf("MatchResult(%q) = %s:%d, want %s:%d",
+					tt.givePath, result.Pattern.Source, result.Pattern.Line, tt.wantSource, tt.wantLine)
+			}
+		})
+	}
+
+	if got, want := merged.Len(), 7; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+
+	if files[0].Match("important.log") {
+		t.Error("Merge() modified the given files")
+	}
+}
+
+func TestMerge_Nested(t *testing.T) {
+	t.Parallel()
+
+	var (
+		global = newTestMatcher(t, "*.log")
+		repo   = newTestMatcher(t, "!keep.log")
+		local  = newTestMatcher(t, "keep.log")
+	)
+
+	if !gitignore.Merge(gitignore.Merge(global, repo), local).Match("keep.log") {
+		t.Error("Merge(Merge(global, repo), local).Match(keep.log) = false, want true")
+	}
+
+	if gitignore.Merge(global, gitignore.Merge(repo)).Match("keep.log") {
+		t.Error("Merge(global, Merge(repo)).Match(keep.log) = true, want false")
+	}
+
+	if !gitignore.Merge().IsEmpty() {
+		t.Error("Merge().IsEmpty() = false, want true")
+	}
+}
+
+func TestMerge_Prefix(t *testing.T) {
+	t.Parallel()
+
+	parent, err := gitignore.NewFromLines([]string{"/pkg/gen/", "pkg/*.pb.go", "*.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	child, err := gitignore.NewFromLines([]string{"!keep.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	// The rules of the parent directory, scoped to pkg, apply along with the
+	// ones of pkg.
+	merged := gitignore.Merge(parent.WithPrefix("pkg"), child)
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: "gen/", want: true},
+		{givePath: "api.pb.go", want: true},
+		{givePath: "sub/api.pb.go", want: false},
+		{givePath: "debug.log", want: true},
+		{givePath: "keep.log", want: false},
+		{givePath: "main.go", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := merged.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+
+	if merged.Fingerprint() == gitignore.Merge(parent, child).Fingerprint() {
+		t.Error("Fingerprint() does not depend on the prefix of merged rules")
+	}
+}
@@ -0,0 +1,145 @@
+package gitignore
+
+import (
+	"path/filepath"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+)
+
+// Option configures how New and NewFromLines parse rules and how the
+// resulting File matches paths.
+type Option func(o *options)
+
+// options holds the configuration set by the options given to a constructor.
+type options struct {
+	warn          func(w Warning)
+	normalize     func(path string) string
+	baseDir       string
+	source        string
+	limits        Limits
+	permissive    bool
+	strict        bool
+	prenormalized bool
+	gitDir        bool
+	ignoreCase    bool
+	wildmatch     bool
+}
+
+// WithPermissive makes the constructors skip the lines that cannot be parsed
+// instead of failing, so a single typo does not make a whole tool refuse to
+// run. The File built from the remaining lines is returned along with every
+// problem joined with errors.Join, each one a *ParseError, which ParseErrors
+// returns as a slice.
+//
+// Errors reading the file are still fatal.
+func WithPermissive() Option {
+	return func(o *options) {
+		o.permissive = true
+	}
+}
+
+// WithSource sets the name the rules are attributed to, such as "global" or
+// "lines", in place of the path of the file they are read from, or of nothing
+// for NewFromLines. The name is reported by PatternInfo, ParseError, Warning and
+// Explanation, so the decisions of rule sets combined with Merge can be
+// attributed to their origin even when they were not read from a file.
+func WithSource(name string) Option {
+	return func(o *options) {
+		o.source = name
+	}
+}
+
+// WithPathNormalizer sets a function applied to every path given to Match,
+// before any other processing, so applications with their own path schemes,
+// such as virtual file system prefixes or URL-encoded names, can adapt paths
+// without wrapping every call. The function must be safe for concurrent use.
+func WithPathNormalizer(fn func(path string) string) Option {
+	return func(o *options) {
+		o.normalize = fn
+	}
+}
+
+// WithPrenormalized asserts that every path given to Match is already clean,
+// slash-separated, and relative to the directory the rules apply to, letting
+// Match skip converting separators in hot loops. Matching other paths gives
+// undefined results. A normalizer set with WithPathNormalizer still applies.
+func WithPrenormalized() Option {
+	return func(o *options) {
+		o.prenormalized = true
+	}
+}
+
+// WithIgnoreCase makes the rules match paths regardless of case, like git does
+// when core.ignoreCase is set, as is the default on case-insensitive file
+// systems such as the ones of macOS and Windows. Rules of a File merged with
+// others keep matching the way they were parsed.
+func WithIgnoreCase() Option {
+	return func(o *options) {
+		o.ignoreCase = true
+	}
+}
+
+// WithWildmatch makes the rules match paths with a port of git's wildmatch
+// algorithm instead of compiling them into regular expressions. It follows git
+// where the regular expressions do not: "dir/**" does not match dir itself,
+// escaped trailing spaces are kept, and characters such as "|" and "+" only
+// match themselves. Rules cannot fail to compile and do not count towards
+// Limits.MaxRegexSize. Rules of a File merged with others keep matching the
+// way they were parsed.
+func WithWildmatch() Option {
+	return func(o *options) {
+		o.wildmatch = true
+	}
+}
+
+// WithBaseDir sets the directory the rules apply to, against which
+// MatchAbsolute resolves paths. A relative dir is resolved against the current
+// working directory when the File is created. It defaults to the directory of
+// the .gitignore file for New, and to none otherwise.
+func WithBaseDir(dir string) Option {
+	return func(o *options) {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+
+		o.baseDir = dir
+	}
+}
+
+// newOptions returns the configuration set by opts.
+func newOptions(opts []Option) *options {
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// parser returns the pattern parser configured by o, attributing warnings to
+// source.
+func (o *options) parser(source string) *pattern.Parser {
+	p := &pattern.Parser{
+		Permissive:    o.permissive,
+		Strict:        o.strict,
+		MaxPatterns:   o.limits.MaxPatterns,
+		MaxLineLength: o.limits.MaxLineLength,
+		MaxRegexSize:  o.limits.MaxRegexSize,
+		IgnoreCase:    o.ignoreCase,
+		Wildmatch:     o.wildmatch,
+	}
+
+	if o.warn != nil {
+		p.Warn = func(w pattern.Warning) {
+			o.warn(Warning{
+				Source: source,
+				Text:   w.Text,
+				Line:   w.Line,
+				Kind:   WarningKind(w.Kind),
+			})
+		}
+	}
+
+	return p
+}
@@ -0,0 +1,404 @@
+package gitignore_test
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestWithPermissive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveLines   []string
+		wantLines   []int
+		wantIgnored []string
+		wantKept    []string
+	}{
+		{
+			name:        "No errors",
+			giveLines:   []string{"*.log", "build/"},
+			wantLines:   nil,
+			wantIgnored: []string{"debug.log", "build/out"},
+			wantKept:    []string{"main.go"},
+		},
+		{
+			name:        "Single bad line",
+			giveLines:   []string{"*.log", "[invalid", "*.tmp"},
+			wantLines:   []int{2},
+			wantIgnored: []string{"debug.log", "cache.tmp"},
+			wantKept:    []string{"main.go", "invalid"},
+		},
+		{
+			name:        "Several bad lines",
+			giveLines:   []string{"[z-a]", "*.log", "", "foo[", "!keep.log"},
+			wantLines:   []int{1, 4},
+			wantIgnored: []string{"debug.log"},
+			wantKept:    []string{"keep.log", "foo"},
+		},
+		{
+			name:        "Only bad lines",
+			giveLines:   []string{"[invalid"},
+			wantLines:   []int{1},
+			wantIgnored: nil,
+			wantKept:    []string{"invalid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := gitignore.NewFromLines(tt.giveLines, gitignore.WithPermissive())
+			if file == nil {
+				t.Fatalf("NewFromLines(%q) = nil file, error %v", tt.giveLines, err)
+			}
+
+			if got := parseErrorLines(t, err); !slices.Equal(got, tt.wantLines) {
+				t.Errorf("NewFromLines(%q) error lines = %v, want %v", tt.giveLines, got, tt.wantLines)
+			}
+
+			if len(tt.wantLines) > 0 && !errors.Is(err, gitignore.ErrRegexCompile) {
+				t.Errorf("errors.Is(err, ErrRegexCompile) = false, want true")
+			}
+
+			for _, path := range tt.wantIgnored {
+				if !file.Match(path) {
+					t.Errorf("Match(%q) = false, want true", path)
+				}
+			}
+
+			for _, path := range tt.wantKept {
+				if file.Match(path) {
+					t.Errorf("Match(%q) = true, want false", path)
+				}
+			}
+		})
+	}
+}
+
+func TestWithPermissive_New(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log\n[invalid\n*.tmp\n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	file, err := gitignore.New(path, gitignore.WithPermissive())
+	if file == nil {
+		t.Fatalf("New(%q) = nil file, error %v", path, err)
+	}
+
+	var parseErr *gitignore.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("New(%q) error = %v, want *ParseError", path, err)
+	}
+
+	if parseErr.Source != path || parseErr.Line != 2 {
+		t.Errorf("ParseError = %s:%d, want %s:2", parseErr.Source, parseErr.Line, path)
+	}
+
+	if !file.Match("cache.tmp") {
+		t.Errorf("Match(%q) = false, want true", "cache.tmp")
+	}
+}
+
+func TestWithPermissive_Missing(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	file, err := gitignore.New(path, gitignore.WithPermissive())
+	if file != nil || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("New(%q) = %v, %v, want nil, %v", path, file, err, os.ErrNotExist)
+	}
+}
+
+// parseErrorLines returns the line numbers of the parse errors joined in err.
+func parseErrorLines(t *testing.T, err error) []int {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint // Inspecting the joined errors.
+	if !ok {
+		t.Fatalf("error %v is not a joined error", err)
+	}
+
+	lines := make([]int, 0)
+
+	for _, e := range joined.Unwrap() {
+		var parseErr *gitignore.ParseError
+		if !errors.As(e, &parseErr) {
+			t.Fatalf("error %v is not a *ParseError", e)
+		}
+
+		lines = append(lines, parseErr.Line)
+	}
+
+	return lines
+}
+
+func TestWithPathNormalizer(t *testing.T) {
+	t.Parallel()
+
+	normalize := func(path string) string {
+		path = strings.TrimPrefix(path, "vfs://project/")
+
+		unescaped, err := url.PathUnescape(path)
+		if err != nil {
+			return path
+		}
+
+		return unescaped
+	}
+
+	tests := []struct {
+		name      string
+		givePath  string
+		wantMatch bool
+	}{
+		{
+			name:      "Virtual file system prefix",
+			givePath:  "vfs://project/debug.log",
+			wantMatch: true,
+		},
+		{
+			name:      "URL-encoded name",
+			givePath:  "vfs://project/my%20build/out.bin",
+			wantMatch: true,
+		},
+		{
+			name:      "Plain path",
+			givePath:  "src/main.go",
+			wantMatch: false,
+		},
+		{
+			name:      "Negated path",
+			givePath:  "vfs://project/keep.log",
+			wantMatch: false,
+		},
+	}
+
+	file, err := gitignore.NewFromLines(
+		[]string{"*.log", "!keep.log", "/my build/", "/pkg/gen/"},
+		gitignore.WithPathNormalizer(normalize),
+	)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := file.Match(tt.givePath); got != tt.wantMatch {
+				t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.wantMatch)
+			}
+
+			if got := file.Clone().Match(tt.givePath); got != tt.wantMatch {
+				t.Errorf("Clone().Match(%q) = %v, want %v", tt.givePath, got, tt.wantMatch)
+			}
+		})
+	}
+
+	if !file.WithPrefix("pkg").Match("vfs://project/gen/types.go") {
+		t.Error("WithPrefix().Match() did not apply the normalizer before the prefix")
+	}
+}
+
+func TestWithPrenormalized(t *testing.T) {
+	t.Parallel()
+
+	rules := []string{"*.log", "!keep.log", "/build/", "docs/*.html"}
+
+	paths := []string{
+		"debug.log",
+		"keep.log",
+		"logs/keep.log",
+		"build/out/app",
+		"src/build/out",
+		"docs/index.html",
+		"src/main.go",
+	}
+
+	file, err := gitignore.NewFromLines(rules)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	fast, err := gitignore.NewFromLines(rules, gitignore.WithPrenormalized())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	for _, path := range paths {
+		if got, want := fast.Match(path), file.Match(path); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if !fast.WithPrefix("build").Match("out") {
+		t.Errorf("WithPrefix().Match(%q) = false, want true", "out")
+	}
+}
+
+func TestWithSource(t *testing.T) {
+	t.Parallel()
+
+	global, err := gitignore.NewFromLines([]string{"*.swp", "*.log"}, gitignore.WithSource("global"))
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err = os.WriteFile(path, []byte("!keep.log\n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	repo, err := gitignore.New(path, gitignore.WithSource("repo"))
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", path, err)
+	}
+
+	extra, err := gitignore.NewFromLines([]string{"*.tmp"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	file := gitignore.Merge(global, repo, extra)
+
+	tests := []struct {
+		givePath   string
+		wantSource string
+		wantLine   int
+	}{
+		{givePath: "a.swp", wantSource: "global", wantLine: 1},
+		{givePath: "debug.log", wantSource: "global", wantLine: 2},
+		{givePath: "keep.log", wantSource: "repo", wantLine: 1},
+		{givePath: "a.tmp", wantSource: "", wantLine: 1},
+	}
+
+	for _, tt := range tests {
+		result, ok := file.MatchResult(tt.givePath)
+		if !ok {
+			t.Errorf("MatchResult(%q) matched no rule", tt.givePath)
+
+			continue
+		}
+
+		if result.Pattern.Source != tt.wantSource || result.Pattern.Line != tt.wantLine {
+			t.Errorf("MatchResult(%q) = %s:%d, want %s:%d", tt.givePath, result.Pattern.Source, result.Pattern.Line, tt.wantSource, tt.wantLine)
+		}
+	}
+
+	_, err = gitignore.NewFromLines([]string{"[invalid"}, gitignore.WithSource("lines"))
+
+	var parseErr *gitignore.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Source != "lines" {
+		t.Errorf("NewFromLines() error = %v, want a *ParseError with source %q", err, "lines")
+	}
+}
+
+func TestWithIgnoreCase(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"*.log", "Build/", "!KEEP.log"}
+
+	folded, err := gitignore.NewFromLines(lines, gitignore.WithIgnoreCase())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	exact, err := gitignore.NewFromLines(lines)
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath  string
+		wantFold  bool
+		wantExact bool
+	}{
+		{givePath: "debug.LOG", wantFold: true, wantExact: false},
+		{givePath: "keep.log", wantFold: false, wantExact: true},
+		{givePath: "build/a.o", wantFold: true, wantExact: false},
+		{givePath: "Build/a.o", wantFold: true, wantExact: true},
+		{givePath: "a.txt", wantFold: false, wantExact: false},
+	}
+
+	for _, tt := range tests {
+		if got := folded.Match(tt.givePath); got != tt.wantFold {
+			t.Errorf("Match(%q) with WithIgnoreCase = %v, want %v", tt.givePath, got, tt.wantFold)
+		}
+
+		if got := exact.Match(tt.givePath); got != tt.wantExact {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.wantExact)
+		}
+	}
+
+	if folded.Fingerprint() == exact.Fingerprint() {
+		t.Error("Fingerprint() is the same with and without WithIgnoreCase")
+	}
+}
+
+func TestWithWildmatch(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"doc/*.txt", "build/**", "file?.o", "[!a]*.tmp", "!keep.tmp", `space\ `}
+
+	f, err := gitignore.NewFromLines(lines, gitignore.WithWildmatch())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: "doc/a.txt", want: true},
+		{givePath: "doc/sub/a.txt", want: false},
+		{givePath: "src/doc/a.txt", want: false},
+		{givePath: "build/a/b.o", want: true},
+		{givePath: "build", want: false},
+		{givePath: "file1.o", want: true},
+		{givePath: "file12.o", want: false},
+		{givePath: "x.tmp", want: true},
+		{givePath: "a.tmp", want: false},
+		{givePath: "keep.tmp", want: false},
+		{givePath: "space ", want: true},
+		{givePath: "space", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := f.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) with WithWildmatch = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+
+	regex, err := gitignore.NewFromLines([]string{"doc/*.txt"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	wild, err := gitignore.NewFromLines([]string{"doc/*.txt"}, gitignore.WithWildmatch())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	if regex.Fingerprint() == wild.Fingerprint() {
+		t.Error("Fingerprint() is the same with and without WithWildmatch")
+	}
+}
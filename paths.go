@@ -0,0 +1,105 @@
+package gitignore
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrOutsideBase is returned when converting a path that does not lie within
+// the base directory.
+const ErrOutsideBase xerrors.Error = "path is outside of the base directory"
+
+// AbsPath returns the absolute path, using the separator of the operating
+// system, of rel, a slash-separated path relative to the base directory such
+// as the ones returned by Untracked. A relative base is resolved against the
+// current working directory. The trailing slash marking a directory is kept as
+// a trailing separator, and paths escaping base return [ErrOutsideBase].
+func AbsPath(base, rel string) (string, error) {
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	clean := path.Clean(strings.TrimPrefix(rel, "/"))
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%w: %q", ErrOutsideBase, rel)
+	}
+
+	abs := filepath.Join(base, filepath.FromSlash(clean))
+
+	if strings.HasSuffix(rel, "/") && abs != base {
+		abs += string(filepath.Separator)
+	}
+
+	return abs, nil
+}
+
+// RelPath returns p, an absolute path or one relative to the current working
+// directory, as a slash-separated path relative to the base directory, which
+// is the form matchers expect. The trailing separator marking a directory is
+// kept as a trailing slash, and the base directory itself is returned as ".".
+func RelPath(base, p string) (string, error) {
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	rel, err := filepath.Rel(base, abs)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %w", ErrOutsideBase, p, err)
+	}
+
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q", ErrOutsideBase, p)
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	isDir := strings.HasSuffix(p, "/") || strings.HasSuffix(p, string(filepath.Separator))
+	if isDir && rel != "." {
+		rel += "/"
+	}
+
+	return rel, nil
+}
+
+// AbsPaths is like AbsPath, but converts every path in rels.
+func AbsPaths(base string, rels []string) ([]string, error) {
+	abs := make([]string, 0, len(rels))
+
+	for _, rel := range rels {
+		p, err := AbsPath(base, rel)
+		if err != nil {
+			return nil, err
+		}
+
+		abs = append(abs, p)
+	}
+
+	return abs, nil
+}
+
+// RelPaths is like RelPath, but converts every path in paths.
+func RelPaths(base string, paths []string) ([]string, error) {
+	rels := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		rel, err := RelPath(base, p)
+		if err != nil {
+			return nil, err
+		}
+
+		rels = append(rels, rel)
+	}
+
+	return rels, nil
+}
@@ -0,0 +1,159 @@
+package gitignore_test
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestAbsPath(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+
+	tests := []struct {
+		name    string
+		giveRel string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "File",
+			giveRel: "src/main.go",
+			want:    filepath.Join(base, "src", "main.go"),
+		},
+		{
+			name:    "Directory keeps trailing separator",
+			giveRel: "build/",
+			want:    filepath.Join(base, "build") + string(filepath.Separator),
+		},
+		{
+			name:    "Leading slash and dot segments",
+			giveRel: "/./src/../docs/index.md",
+			want:    filepath.Join(base, "docs", "index.md"),
+		},
+		{
+			name:    "Base itself",
+			giveRel: ".",
+			want:    base,
+		},
+		{
+			name:    "Escaping base",
+			giveRel: "../secret",
+			wantErr: gitignore.ErrOutsideBase,
+		},
+		{
+			name:    "Escaping base through subdirectory",
+			giveRel: "src/../../secret",
+			wantErr: gitignore.ErrOutsideBase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gitignore.AbsPath(base, tt.giveRel)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AbsPath(%q) error = %v, want %v", tt.giveRel, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("AbsPath(%q) = %q, want %q", tt.giveRel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelPath(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+
+	tests := []struct {
+		name     string
+		givePath string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "File",
+			givePath: filepath.Join(base, "src", "main.go"),
+			want:     "src/main.go",
+		},
+		{
+			name:     "Directory keeps trailing slash",
+			givePath: filepath.Join(base, "build") + string(filepath.Separator),
+			want:     "build/",
+		},
+		{
+			name:     "Unclean path",
+			givePath: filepath.Join(base, "src") + "/../docs/./index.md",
+			want:     "docs/index.md",
+		},
+		{
+			name:     "Base itself",
+			givePath: base + string(filepath.Separator),
+			want:     ".",
+		},
+		{
+			name:     "Outside base",
+			givePath: filepath.Dir(base),
+			wantErr:  gitignore.ErrOutsideBase,
+		},
+		{
+			name:     "Sibling sharing a prefix",
+			givePath: base + "-other",
+			wantErr:  gitignore.ErrOutsideBase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gitignore.RelPath(base, tt.givePath)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RelPath(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("RelPath(%q) = %q, want %q", tt.givePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAbsPaths_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		base = t.TempDir()
+		rels = []string{"a.txt", "src/main.go", "build/", "docs/api/"}
+	)
+
+	abs, err := gitignore.AbsPaths(base, rels)
+	if err != nil {
+		t.Fatalf("AbsPaths() error = %v", err)
+	}
+
+	got, err := gitignore.RelPaths(base, abs)
+	if err != nil {
+		t.Fatalf("RelPaths() error = %v", err)
+	}
+
+	if !slices.Equal(got, rels) {
+		t.Errorf("RelPaths(AbsPaths()) = %q, want %q", got, rels)
+	}
+
+	if _, err = gitignore.AbsPaths(base, []string{"ok", "../bad"}); !errors.Is(err, gitignore.ErrOutsideBase) {
+		t.Errorf("AbsPaths() error = %v, want %v", err, gitignore.ErrOutsideBase)
+	}
+
+	if _, err = gitignore.RelPaths(base, []string{filepath.Dir(base)}); !errors.Is(err, gitignore.ErrOutsideBase) {
+		t.Errorf("RelPaths() error = %v, want %v", err, gitignore.ErrOutsideBase)
+	}
+}
@@ -0,0 +1,251 @@
+package gitignore
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrNotWritable is returned by Text and WriteTo for a File whose rules would not
+// match the same paths once written as a .gitignore file.
+const ErrNotWritable xerrors.Error = "rules cannot be written faithfully"
+
+// PatternInfo describes a pattern of a File, as it was compiled.
+type PatternInfo struct {
+	// Raw is the rule as written in the .gitignore file, including its
+	// leading "!" if negated, without surrounding spaces.
+	Raw string
+
+	// Text is the line the rule was read from, as written, including any
+	// surrounding spaces, so the rule set can be displayed or written back
+	// faithfully.
+	Text string
+
+	// Source is the path of the file the pattern was read from, or empty if
+	// it was created from lines, unless named otherwise with WithSource.
+	Source string
+
+	// Line is the 1-based line number of the rule in its source.
+	Line int
+
+	// Negate indicates whether the rule re-includes paths.
+	Negate bool
+
+	// Anchored indicates whether the pattern only matches relative to the
+	// directory of the .gitignore file, rather than at any depth.
+	Anchored bool
+
+	// DirOnly indicates whether the pattern only matches directories, and
+	// what they contain.
+	DirOnly bool
+}
+
+// Patterns returns a description of every pattern of f, in file order. The
+// returned slice is a copy, so modifying it does not affect f.
+func (f *File) Patterns() []PatternInfo {
+	infos := make([]PatternInfo, 0, len(f.patterns))
+
+	for _, pat := range f.patterns {
+		infos = append(infos, f.info(pat))
+	}
+
+	return infos
+}
+
+// Filter returns a File holding only the rules of f for which keep returns
+// true, in the same order, such as every rule but the negated ones. The
+// returned File keeps the other settings of f, such as its prefix.
+func (f *File) Filter(keep func(info PatternInfo) bool) *File {
+	patterns := make([]*pattern.Pattern, 0, len(f.patterns))
+
+	for _, pat := range f.patterns {
+		if keep(f.info(pat)) {
+			clone := *pat
+			patterns = append(patterns, &clone)
+		}
+	}
+
+	return f.derive(patterns)
+}
+
+// Negations returns a File matching the paths explicitly re-included by the
+// negated rules of f, so tools such as archivers can force-include them from
+// otherwise skipped directories. Each negated rule becomes a regular rule, with
+// its leading "!" removed, while the other rules are dropped.
+func (f *File) Negations() *File {
+	negations := f.Filter(func(info PatternInfo) bool {
+		return info.Negate
+	})
+
+	for _, pat := range negations.patterns {
+		pat.Negate = false
+		pat.Raw = strings.TrimPrefix(pat.Raw, "!")
+		pat.Text = pat.Raw
+	}
+
+	// The rules are no longer negated, so they can be combined.
+	negations.index = newIndex(negations.patterns)
+
+	return negations
+}
+
+// info returns the description of pat, one of the patterns of f.
+func (f *File) info(pat *pattern.Pattern) PatternInfo {
+	return PatternInfo{
+		Raw:      pat.Raw,
+		Text:     pat.Text,
+		Source:   pat.Source,
+		Line:     pat.Line,
+		Negate:   pat.Negate,
+		Anchored: pat.Anchored,
+		DirOnly:  pat.DirOnly,
+	}
+}
+
+// String returns the rules of f, one per line, each prefixed with its source
+// and line number like "path/.gitignore:3: *.log", for logging and debugging.
+// The source is omitted for rules created from lines. The result is not a valid
+// .gitignore file; use Text or WriteTo to get one instead.
+func (f *File) String() string {
+	var b strings.Builder
+
+	for i, pat := range f.patterns {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		if pat.Source != "" {
+			b.WriteString(pat.Source)
+			b.WriteByte(':')
+		}
+
+		b.WriteString(strconv.Itoa(pat.Line))
+		b.WriteString(": ")
+		b.WriteString(pat.Raw)
+	}
+
+	return b.String()
+}
+
+// Text returns the rules of f as a valid .gitignore file, one rule per line,
+// using the original text of each line, so rule sets can be generated
+// programmatically and read back with NewFromLines or New. Comments and blank
+// lines of the original files are not kept. When the rules come from several
+// sources, such as with Merge, each group of rules is preceded by a comment
+// naming its source. The rule set with WithGitDirIgnored is written last, so
+// it keeps taking precedence over every other rule.
+//
+// The text of a rule only has its meaning relative to the directory of its
+// .gitignore file, and without WithIgnoreCase, so Text returns
+// ErrNotWritable for a File returned by WithPrefix, one holding the rules of
+// an ancestor directory, such as from MatcherForDir, or one matching paths
+// regardless of case.
+//
+// Use String instead to get the rules annotated with their line numbers.
+func (f *File) Text() (string, error) {
+	if err := f.writable(); err != nil {
+		return "", err
+	}
+
+	var (
+		b       strings.Builder
+		sources = make(map[string]struct{})
+	)
+
+	for _, pat := range f.patterns {
+		sources[pat.Source] = struct{}{}
+	}
+
+	for i, pat := range f.patterns {
+		if len(sources) > 1 && (i == 0 || pat.Source != f.patterns[i-1].Source) {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+
+			if pat.Source == "" {
+				b.WriteString("# Added rules\n")
+			} else {
+				b.WriteString("# " + pat.Source + "\n")
+			}
+		}
+
+		b.WriteString(pat.Text)
+		b.WriteByte('\n')
+	}
+
+	if f.gitDir != nil {
+		if len(sources) > 1 {
+			b.WriteString("\n# Git directories\n")
+		}
+
+		b.WriteString(f.gitDir.Raw + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// WriteTo writes the rules of f to w as a valid .gitignore file, as returned
+// by Text. Nothing is written if Text fails.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	text, err := f.Text()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.WriteString(w, text)
+	if err != nil {
+		return int64(n), fmt.Errorf("%w", err)
+	}
+
+	return int64(n), nil
+}
+
+// writable returns an error wrapping ErrNotWritable if the text of the rules
+// of f does not describe them faithfully.
+func (f *File) writable() error {
+	if f.prefix != "" {
+		return fmt.Errorf("%w: paths are relative to %q", ErrNotWritable, f.prefix)
+	}
+
+	for _, pat := range f.patterns {
+		if pat.Prefix != "" {
+			return fmt.Errorf("%w: rule %q applies to %q", ErrNotWritable, pat.Raw, pat.Prefix)
+		}
+
+		if pat.IgnoreCase {
+			return fmt.Errorf("%w: rule %q ignores case", ErrNotWritable, pat.Raw)
+		}
+	}
+
+	return nil
+}
+
+// GoString returns a Go-syntax representation of f listing its rules, so test
+// failures printed with %#v show what a File contains.
+func (f *File) GoString() string {
+	rules := make([]string, 0, len(f.patterns))
+
+	for _, pat := range f.patterns {
+		rules = append(rules, strconv.Quote(pat.Raw))
+	}
+
+	var b strings.Builder
+
+	b.WriteString("&gitignore.File{")
+
+	if f.source != "" {
+		b.WriteString("Source: " + strconv.Quote(f.source) + ", ")
+	}
+
+	if f.prefix != "" {
+		b.WriteString("Prefix: " + strconv.Quote(f.prefix) + ", ")
+	}
+
+	b.WriteString("Rules: []string{" + strings.Join(rules, ", ") + "}}")
+
+	return b.String()
+}
@@ -0,0 +1,418 @@
+package gitignore_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestFile_Patterns(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"# Build output.",
+		"/build",
+		"",
+		"  *.log  ",
+		"!keep.log",
+		"node_modules/",
+	}
+
+	want := []gitignore.PatternInfo{
+		{Raw: "/build", Text: "/build", Line: 2, Anchored: true},
+		{Raw: "*.log", Text: "  *.log  ", Line: 4},
+		{Raw: "!keep.log", Text: "!keep.log", Line: 5, Negate: true},
+		{Raw: "node_modules/", Text: "node_modules/", Line: 6, DirOnly: true},
+	}
+
+	matcher := newTestMatcher(t, lines...)
+
+	if got := matcher.Patterns(); !slices.Equal(got, want) {
+		t.Errorf("Patterns() = %+v, want %+v", got, want)
+	}
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	file, err := gitignore.New(path)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	if got := file.Patterns(); len(got) != 1 || got[0].Source != path {
+		t.Errorf("Patterns() = %+v, want a single pattern with source %q", got, path)
+	}
+}
+
+func TestFile_String(t *testing.T) {
+	t.Parallel()
+
+	matcher := newTestMatcher(t, "# Comment.", "/build", "", "!keep.log")
+
+	if got, want := matcher.String(), "2: /build\n4: !keep.log"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if got, want := fmt.Sprintf("%#v", matcher), `&gitignore.File{Rules: []string{"/build", "!keep.log"}}`; got != want {
+		t.Errorf("GoString() = %s, want %s", got, want)
+	}
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	file, err := gitignore.New(path)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	if got, want := file.String(), path+":1: *.log"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if got, want := file.GoString(), `&gitignore.File{Source: "`+path+`", Rules: []string{"*.log"}}`; got != want {
+		t.Errorf("GoString() = %s, want %s", got, want)
+	}
+
+	if got, want := file.WithPrefix("pkg").GoString(), `&gitignore.File{Source: "`+path+`", Prefix: "pkg", Rules: []string{"*.log"}}`; got != want {
+		t.Errorf("WithPrefix().GoString() = %s, want %s", got, want)
+	}
+}
+
+func TestFile_Filter(t *testing.T) {
+	t.Parallel()
+
+	rules := []string{"*.log", "!keep.log", "/build/", "docs/*.html", "!/docs/index.html"}
+
+	tests := []struct {
+		name        string
+		giveKeep    func(info gitignore.PatternInfo) bool
+		wantRules   string
+		wantIgnored []string
+		wantKept    []string
+	}{
+		{
+			name: "Drop negations",
+			giveKeep: func(info gitignore.PatternInfo) bool {
+				return !info.Negate
+			},
+			wantRules:   "1: *.log\n3: /build/\n4: docs/*.html",
+			wantIgnored: []string{"keep.log", "docs/index.html", "build/out"},
+		},
+		{
+			name: "Anchored rules only",
+			giveKeep: func(info gitignore.PatternInfo) bool {
+				return info.Anchored
+			},
+			wantRules:   "3: /build/\n4: docs/*.html\n5: !/docs/index.html",
+			wantIgnored: []string{"build/out", "docs/guide.html"},
+			wantKept:    []string{"debug.log", "docs/index.html"},
+		},
+		{
+			name: "Keep everything",
+			giveKeep: func(_ gitignore.PatternInfo) bool {
+				return true
+			},
+			wantRules:   "1: *.log\n2: !keep.log\n3: /build/\n4: docs/*.html\n5: !/docs/index.html",
+			wantIgnored: []string{"debug.log"},
+			wantKept:    []string{"keep.log"},
+		},
+		{
+			name: "Keep nothing",
+			giveKeep: func(_ gitignore.PatternInfo) bool {
+				return false
+			},
+			wantRules: "",
+			wantKept:  []string{"debug.log", "build/out"},
+		},
+	}
+
+	file, err := gitignore.NewFromLines(rules)
+	if err != nil {
+		t.Fatalf("NewFromLines(%q) error = %v", rules, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered := file.Filter(tt.giveKeep)
+
+			if got := filtered.String(); got != tt.wantRules {
+				t.Errorf("Filter().String() = %q, want %q", got, tt.wantRules)
+			}
+
+			for _, path := range tt.wantIgnored {
+				if !filtered.Match(path) {
+					t.Errorf("Filter().Match(%q) = false, want true", path)
+				}
+			}
+
+			for _, path := range tt.wantKept {
+				if filtered.Match(path) {
+					t.Errorf("Filter().Match(%q) = true, want false", path)
+				}
+			}
+		})
+	}
+
+	if file.Len() != len(rules) {
+		t.Errorf("Filter() modified the original File, Len() = %d, want %d", file.Len(), len(rules))
+	}
+}
+
+func TestFile_Negations(t *testing.T) {
+	t.Parallel()
+
+	rules := []string{"*.log", "!keep.log", "/vendor/", "!/vendor/modules.txt", `\!literal`}
+
+	file, err := gitignore.NewFromLines(rules)
+	if err != nil {
+		t.Fatalf("NewFromLines(%q) error = %v", rules, err)
+	}
+
+	negations := file.Negations()
+
+	if got, want := negations.String(), "2: keep.log\n4: /vendor/modules.txt"; got != want {
+		t.Errorf("Negations().String() = %q, want %q", got, want)
+	}
+
+	for _, info := range negations.Patterns() {
+		if info.Negate || info.Text != info.Raw {
+			t.Errorf("Negations().Patterns() has negated rule %q (%q)", info.Raw, info.Text)
+		}
+	}
+
+	tests := []struct {
+		givePath  string
+		wantMatch bool
+	}{
+		{givePath: "keep.log", wantMatch: true},
+		{givePath: "logs/keep.log", wantMatch: true},
+		{givePath: "vendor/modules.txt", wantMatch: true},
+		{givePath: "debug.log", wantMatch: false},
+		{givePath: "vendor/pkg/a.go", wantMatch: false},
+		{givePath: "!literal", wantMatch: false},
+	}
+
+	for _, tt := range tests {
+		if got := negations.Match(tt.givePath); got != tt.wantMatch {
+			t.Errorf("Negations().Match(%q) = %v, want %v", tt.givePath, got, tt.wantMatch)
+		}
+	}
+
+	if file.Match("keep.log") {
+		t.Error("Negations() modified the original File")
+	}
+}
+
+func TestFile_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveFiles [][]string
+		giveAdded []string
+		want      string
+	}{
+		{
+			name:      "Single source",
+			giveFiles: [][]string{{"# Logs.", "*.log", "", "!keep.log", `\#not-a-comment`, "  *.tmp"}},
+			want:      "*.log\n!keep.log\n\\#not-a-comment\n  *.tmp\n",
+		},
+		{
+			name:      "Empty",
+			giveFiles: [][]string{{"# Only a comment."}},
+			want:      "",
+		},
+		{
+			name:      "Added rules",
+			giveFiles: [][]string{{"*.log"}},
+			giveAdded: []string{"*.tmp"},
+			want:      "*.log\n*.tmp\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			files := make([]*gitignore.File, 0, len(tt.giveFiles))
+			for _, lines := range tt.giveFiles {
+				files = append(files, newTestMatcher(t, lines...))
+			}
+
+			file := gitignore.Merge(files...)
+
+			if tt.giveAdded != nil {
+				if err := file.AddLines(tt.giveAdded...); err != nil {
+					t.Fatalf("AddLines() error = %v", err)
+				}
+			}
+
+			var b strings.Builder
+
+			n, err := file.WriteTo(&b)
+			if err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+
+			if got := b.String(); got != tt.want {
+				t.Errorf("WriteTo() wrote %q, want %q", got, tt.want)
+			}
+
+			if n != int64(b.Len()) {
+				t.Errorf("WriteTo() = %d, want %d", n, b.Len())
+			}
+
+			text, err := file.Text()
+			if err != nil || text != tt.want {
+				t.Errorf("Text() = %q, %v, want %q", text, err, tt.want)
+			}
+
+			roundTrip := newTestMatcher(t, strings.Split(b.String(), "\n")...)
+			if roundTrip.Fingerprint() != file.Fingerprint() {
+				t.Errorf("rules written by WriteTo() differ from the original ones:\n%s", b.String())
+			}
+		})
+	}
+}
+
+func TestFile_WriteTo_Sources(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"global":     {Data: []byte("*.swp\n")},
+		".gitignore": {Data: []byte("*.log\n!keep.log\n")},
+	}
+
+	global, err := gitignore.NewFromFS(fsys, "global")
+	if err != nil {
+		t.Fatalf("NewFromFS() error = %v", err)
+	}
+
+	repo, err := gitignore.NewFromFS(fsys, ".gitignore")
+	if err != nil {
+		t.Fatalf("NewFromFS() error = %v", err)
+	}
+
+	file := gitignore.Merge(global, repo)
+
+	if err = file.AddLines("*.tmp"); err != nil {
+		t.Fatalf("AddLines() error = %v", err)
+	}
+
+	var b strings.Builder
+
+	if _, err = file.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	want := "# global\n*.swp\n\n# .gitignore\n*.log\n!keep.log\n\n# Added rules\n*.tmp\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteTo() wrote %q, want %q", got, want)
+	}
+}
+
+func TestFile_WriteTo_GitDir(t *testing.T) {
+	t.Parallel()
+
+	file, err := gitignore.NewFromLines([]string{"*.log", "!.git/"}, gitignore.WithGitDirIgnored())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	var b strings.Builder
+
+	if _, err = file.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	if want := "*.log\n!.git/\n.git\n"; b.String() != want {
+		t.Errorf("WriteTo() wrote %q, want %q", b.String(), want)
+	}
+
+	roundTrip := newTestMatcher(t, strings.Split(b.String(), "\n")...)
+
+	for _, path := range []string{".git/", ".git/config", "sub/.git", "debug.log", "main.go"} {
+		if got, want := roundTrip.Match(path), file.Match(path); got != want {
+			t.Errorf("Match(%q) = %v after WriteTo(), want %v", path, got, want)
+		}
+	}
+}
+
+func TestFile_WriteTo_NotWritable(t *testing.T) {
+	t.Parallel()
+
+	root := newTestMatcher(t, "*.log", "/build/")
+
+	ignoreCase, err := gitignore.NewFromLines([]string{"*.log"}, gitignore.WithIgnoreCase())
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		give *gitignore.File
+	}{
+		{
+			name: "With prefix",
+			give: root.WithPrefix("src"),
+		},
+		{
+			name: "Re-anchored rules",
+			give: gitignore.Merge(root.WithPrefix("src"), newTestMatcher(t, "*.tmp")),
+		},
+		{
+			name: "Ignore case",
+			give: ignoreCase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+
+			n, err := tt.give.WriteTo(&b)
+			if !errors.Is(err, gitignore.ErrNotWritable) {
+				t.Fatalf("WriteTo() error = %v, want %v", err, gitignore.ErrNotWritable)
+			}
+
+			if n != 0 || b.Len() != 0 {
+				t.Errorf("WriteTo() wrote %q, want nothing", b.String())
+			}
+
+			if _, err = tt.give.Text(); !errors.Is(err, gitignore.ErrNotWritable) {
+				t.Errorf("Text() error = %v, want %v", err, gitignore.ErrNotWritable)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestFile_WriteTo_Error(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newTestMatcher(t, "*.log").WriteTo(failingWriter{}); err == nil {
+		t.Error("WriteTo() error = nil, want error")
+	}
+}
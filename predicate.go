@@ -0,0 +1,90 @@
+package gitignore
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// Predicate returns a function deciding what callbacks of [filepath.WalkDir]
+// and [fs.WalkDir] should do with each entry, so existing walkers can honor m
+// in one line:
+//
+//	ignored := gitignore.Predicate(m)
+//
+//	err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+//		if err != nil {
+//			return err
+//		}
+//
+//		if skip, prune := ignored(path, d); prune {
+//			return fs.SkipDir
+//		} else if skip {
+//			return nil
+//		}
+//
+//		// Process path.
+//
+//		return nil
+//	})
+//
+// The returned function reports whether the entry is ignored and should be
+// skipped, and whether it is an ignored directory whose subtree should be
+// pruned, as git never looks inside excluded directories. The path is relative
+// to the directory the rules apply to and may use the separator of the
+// operating system. The root of the walk, ".", is never skipped.
+func Predicate(m Matcher) func(path string, d fs.DirEntry) (bool, bool) {
+	return func(path string, d fs.DirEntry) (bool, bool) {
+		path = filepath.ToSlash(path)
+
+		if path == "." || path == "" {
+			return false, false
+		}
+
+		if d.IsDir() {
+			ignored := m.Match(path + "/")
+
+			return ignored, ignored
+		}
+
+		return m.Match(path), false
+	}
+}
+
+// WrapWalkFunc returns a function calling fn for the entries f does not ignore,
+// and returning [fs.SkipDir] for ignored directories, so existing callbacks of
+// [filepath.WalkDir] and [fs.WalkDir] adopt the rules in one line:
+//
+//	err := filepath.WalkDir(".", f.WrapWalkFunc(walk))
+//
+// Like with Predicate, paths are relative to the directory the rules apply to,
+// which is the case when walking "." from it or an [fs.FS] rooted at it. Errors
+// are passed to fn as is, along with their path.
+func (f *File) WrapWalkFunc(fn fs.WalkDirFunc) fs.WalkDirFunc {
+	return wrapWalkFunc(f, fn)
+}
+
+// WrapWalkFunc is like [File.WrapWalkFunc], with paths relative to the root of
+// the working tree of r.
+func (r *Repository) WrapWalkFunc(fn fs.WalkDirFunc) fs.WalkDirFunc {
+	return wrapWalkFunc(r, fn)
+}
+
+// wrapWalkFunc returns a function calling fn for the entries m does not
+// ignore, and pruning ignored directories.
+func wrapWalkFunc(m Matcher, fn fs.WalkDirFunc) fs.WalkDirFunc {
+	ignored := Predicate(m)
+
+	return func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fn(path, d, err)
+		}
+
+		if skip, prune := ignored(path, d); prune {
+			return fs.SkipDir
+		} else if skip {
+			return nil
+		}
+
+		return fn(path, d, nil)
+	}
+}
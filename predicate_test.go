@@ -0,0 +1,161 @@
+package gitignore_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestPredicate(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"main.go":            {},
+		"debug.log":          {},
+		"keep.log":           {},
+		"build/app":          {},
+		"build/keep.log":     {},
+		"src/lib.go":         {},
+		"src/tmp/cache.bin":  {},
+		"src/build/notes.md": {},
+	}
+
+	m, err := gitignore.NewFromLines([]string{"*.log", "!keep.log", "/build/", "tmp/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	var (
+		ignored = gitignore.Predicate(m)
+		visited []string
+		pruned  []string
+	)
+
+	err = fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if skip, prune := ignored(path, d); prune {
+			pruned = append(pruned, path)
+
+			return fs.SkipDir
+		} else if skip {
+			return nil
+		}
+
+		if !d.IsDir() {
+			visited = append(visited, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+
+	wantVisited := []string{"keep.log", "main.go", "src/build/notes.md", "src/lib.go"}
+	if !slices.Equal(visited, wantVisited) {
+		t.Errorf("visited = %q, want %q", visited, wantVisited)
+	}
+
+	wantPruned := []string{"build", "src/tmp"}
+	if !slices.Equal(pruned, wantPruned) {
+		t.Errorf("pruned = %q, want %q", pruned, wantPruned)
+	}
+}
+
+func TestFile_WrapWalkFunc(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"main.go":           {},
+		"debug.log":         {},
+		"keep.log":          {},
+		"build/app":         {},
+		"src/lib.go":        {},
+		"src/tmp/cache.bin": {},
+	}
+
+	m, err := gitignore.NewFromLines([]string{"*.log", "!keep.log", "/build/", "tmp/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	var visited []string
+
+	err = fs.WalkDir(fsys, ".", m.WrapWalkFunc(func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		visited = append(visited, path)
+
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+
+	want := []string{".", "keep.log", "main.go", "src", "src/lib.go"}
+
+	if !slices.Equal(visited, want) {
+		t.Errorf("visited = %q, want %q", visited, want)
+	}
+
+	err = fs.WalkDir(fsys, "missing", m.WrapWalkFunc(func(_ string, _ fs.DirEntry, err error) error {
+		return err
+	}))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("WalkDir() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestRepository_WrapWalkFunc(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":    "",
+		".gitignore":     "*.log\n",
+		"main.go":        "",
+		"debug.log":      "",
+		"pkg/.gitignore": "gen/\n",
+		"pkg/gen/a.go":   "",
+		"pkg/lib.go":     "",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	var visited []string
+
+	err = fs.WalkDir(os.DirFS(root), ".", repo.WrapWalkFunc(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			visited = append(visited, path)
+		}
+
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+
+	want := []string{".gitignore", "main.go", "pkg/.gitignore", "pkg/lib.go"}
+
+	if !slices.Equal(visited, want) {
+		t.Errorf("visited = %q, want %q", visited, want)
+	}
+}
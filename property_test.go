@@ -0,0 +1,43 @@
+package gitignore_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/property"
+)
+
+//nolint:gochecknoglobals // Test flags.
+var (
+	propertySeed  = flag.Uint64("property.seed", 0, "seed of the property tests, random if zero")
+	propertyCases = flag.Int("property.cases", 5000, "number of cases generated by the property tests")
+)
+
+func TestMatch_Property(t *testing.T) {
+	t.Parallel()
+
+	seed := *propertySeed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+
+	t.Logf("seed %d, rerun with -property.seed=%d to reproduce", seed, seed)
+
+	gen := property.NewGenerator(seed)
+
+	for i := range *propertyCases {
+		tt := gen.Next()
+
+		matcher, err := gitignore.NewFromLines(tt.Rules)
+		if err != nil {
+			t.Fatalf("case %d (%s): NewFromLines(%q) unexpected error: %v", i, tt.Kind, tt.Rules, err)
+		}
+
+		if got := gitignore.IsIgnored(matcher, tt.Path, tt.IsDir); got != tt.Ignored {
+			t.Errorf("case %d (%s): IsIgnored(%q, isDir=%t) with rules %q = %t, want %t",
+				i, tt.Kind, tt.Path, tt.IsDir, tt.Rules, got, tt.Ignored)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Reload describes ignore rules swapped in by a watcher after their file
+// changed, or an error watching them.
+type Reload struct {
+	// Err is the error reading the file or watching it, if any.
+	Err error
+
+	// Source is the path of the ignore file that changed. It is relative to
+	// the root of the working tree for the .gitignore files of a Repository.
+	// It is empty for errors not related to a single file.
+	Source string
+}
+
+// WithWatch makes the Repository watch its ignore files with fsnotify, and
+// atomically swap in their recompiled rules as they change, so long-running
+// processes pick up edits without restarting. The functions set with Subscribe
+// are notified of every reload. Directories are watched as their .gitignore
+// file is first needed, along with the directories of the excludes file and of
+// info/exclude. The git configuration is not watched. The watcher must be
+// stopped with Close.
+func WithWatch() RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.watch = true
+	}
+}
+
+// Subscribe sets a function called with every reload of the ignore files of
+// r, when watched with WithWatch, and returns a function removing it. It is
+// called from the watching goroutine, so it must not block.
+func (r *Repository) Subscribe(fn func(Reload)) func() {
+	return r.subscribers.add(fn)
+}
+
+// Close stops watching the ignore files of r, if enabled with WithWatch, and
+// waits for pending reloads to complete.
+func (r *Repository) Close() error {
+	if r.watcher == nil {
+		return nil
+	}
+
+	err := r.watcher.Close()
+
+	<-r.watching
+
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// startWatching starts watching the excludes files of r, and the directories
+// of the working tree as they are used.
+func (r *Repository) startWatching() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	r.watcher = watcher
+	r.watching = make(chan struct{})
+
+	if r.excludesFile != "" {
+		r.watchDir(filepath.Dir(r.excludesFile))
+	}
+
+	r.watchDir(filepath.Dir(r.infoExclude()))
+
+	go watch(watcher, r.watching, &r.subscribers, r.handle, reloadError)
+
+	return nil
+}
+
+// watchDir adds the directory dir to the watcher of r. Missing directories are
+// skipped, since they cannot hold any file yet.
+func (r *Repository) watchDir(dir string) {
+	err := r.watcher.Add(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, fsnotify.ErrClosed) {
+		r.subscribers.notify(Reload{Source: dir, Err: fmt.Errorf("%w", err)})
+	}
+}
+
+// handle reloads the rules of the ignore file changed by event, if any.
+func (r *Repository) handle(event fsnotify.Event) {
+	name := filepath.Clean(event.Name)
+
+	switch {
+	case name == r.excludesFile || name == r.infoExclude():
+		r.subscribers.notify(Reload{Source: name, Err: r.readExcludes()})
+	case filepath.Base(name) == gitignoreFile:
+		rel, err := filepath.Rel(r.root, filepath.Dir(name))
+		if err != nil {
+			return
+		}
+
+		dir := filepath.ToSlash(rel)
+
+		r.subscribers.notify(Reload{Source: path.Join(dir, gitignoreFile), Err: r.reload(dir)})
+	}
+}
+
+// reload reads the .gitignore file of the directory name again, even if its
+// modification time and size did not change, and returns the error reading
+// it, if any.
+func (r *Repository) reload(name string) error {
+	r.mu.Lock()
+	delete(r.ignores, name)
+	r.mu.Unlock()
+
+	r.load(name)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.ignores[name]; ok {
+		return entry.err
+	}
+
+	return nil
+}
+
+// WatchedFile is a File watched with fsnotify, whose recompiled rules are
+// atomically swapped in as its .gitignore file changes. A removed file has no
+// rules, while a file failing to parse keeps its previous ones.
+//
+// A WatchedFile is safe for concurrent use.
+type WatchedFile struct {
+	file        atomic.Pointer[File]
+	watcher     *fsnotify.Watcher
+	watching    chan struct{}
+	subscribers subscriptions[Reload]
+	name        string
+	path        string
+	opts        []Option
+}
+
+// WatchFile reads the .gitignore file at name, like New, and watches it for
+// changes until Close is called.
+func WatchFile(name string, opts ...Option) (*WatchedFile, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	file, err := New(name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	// The directory is watched rather than the file, so it keeps being
+	// watched when editors replace it.
+	if err = watcher.Add(filepath.Dir(abs)); err != nil {
+		watcher.Close()
+
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	w := &WatchedFile{
+		watcher:  watcher,
+		watching: make(chan struct{}),
+		name:     name,
+		path:     abs,
+		opts:     opts,
+	}
+
+	w.file.Store(file)
+
+	go watch(watcher, w.watching, &w.subscribers, w.handle, reloadError)
+
+	return w, nil
+}
+
+// File returns the current rules of w.
+func (w *WatchedFile) File() *File {
+	return w.file.Load()
+}
+
+// Match reports whether path is ignored by the current rules of w, like
+// [File.Match].
+func (w *WatchedFile) Match(path string) bool {
+	return w.file.Load().Match(path)
+}
+
+// Subscribe sets a function called with every reload of w, and returns a
+// function removing it. It is called from the watching goroutine, so it must
+// not block.
+func (w *WatchedFile) Subscribe(fn func(Reload)) func() {
+	return w.subscribers.add(fn)
+}
+
+// Close stops watching the file of w and waits for pending reloads to
+// complete. The last rules read remain available.
+func (w *WatchedFile) Close() error {
+	err := w.watcher.Close()
+
+	<-w.watching
+
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// handle reloads the rules of w if event changed its file.
+func (w *WatchedFile) handle(event fsnotify.Event) {
+	if filepath.Clean(event.Name) != w.path {
+		return
+	}
+
+	file, err := New(w.name, w.opts...)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		w.file.Store(&File{})
+
+		err = nil
+	case err == nil:
+		w.file.Store(file)
+	}
+
+	w.subscribers.notify(Reload{Source: w.name, Err: err})
+}
+
+// watch calls handle with the events of watcher changing a file, and notifies
+// subscribers of its errors, turned into a notification by fail, until it is
+// closed, then closes done.
+func watch[T any](watcher *fsnotify.Watcher, done chan struct{}, subscribers *subscriptions[T], handle func(fsnotify.Event), fail func(error) T) {
+	defer close(done)
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) ||
+				event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				handle(event)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			subscribers.notify(fail(fmt.Errorf("%w", err)))
+		}
+	}
+}
+
+// reloadError returns the reload notifying of err, an error of a watcher.
+func reloadError(err error) Reload {
+	return Reload{Err: err}
+}
+
+// subscriptions holds the functions notified of reloads or events.
+type subscriptions[T any] struct {
+	fns  map[uint64]func(T)
+	next uint64
+	mu   sync.Mutex
+}
+
+// add adds fn and returns a function removing it.
+func (s *subscriptions[T]) add(fn func(T)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fns == nil {
+		s.fns = make(map[uint64]func(T))
+	}
+
+	id := s.next
+	s.next++
+	s.fns[id] = fn
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.fns, id)
+	}
+}
+
+// notify calls every function with value, outside of the lock so they may
+// subscribe or unsubscribe.
+func (s *subscriptions[T]) notify(value T) {
+	s.mu.Lock()
+
+	fns := make([]func(T), 0, len(s.fns))
+	for _, fn := range s.fns {
+		fns = append(fns, fn)
+	}
+
+	s.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(value)
+	}
+}
@@ -0,0 +1,159 @@
+package gitignore_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+// waitReload waits for a reload of source on reloads.
+func waitReload(t *testing.T, reloads <-chan gitignore.Reload, source string) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case reload := <-reloads:
+			if reload.Err != nil {
+				t.Fatalf("reload of %q error = %v", reload.Source, reload.Err)
+			}
+
+			if reload.Source == source {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for a reload of %q", source)
+		}
+	}
+}
+
+// subscribe returns a channel receiving the reloads of a watcher.
+func subscribe(t *testing.T, fn func(func(gitignore.Reload)) func()) <-chan gitignore.Reload {
+	t.Helper()
+
+	reloads := make(chan gitignore.Reload, 16)
+
+	t.Cleanup(fn(func(reload gitignore.Reload) {
+		reloads <- reload
+	}))
+
+	return reloads
+}
+
+func TestWithWatch(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/info/exclude": "",
+		".gitignore":        "*.log\n",
+		"pkg/.gitignore":    "*.tmp\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithWatch(),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := repo.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+
+	reloads := subscribe(t, repo.Subscribe)
+
+	if !repo.Match("pkg/a.tmp") || repo.Match("pkg/a.txt") {
+		t.Fatal("Match() does not honor the initial rules")
+	}
+
+	writeTree(t, root, map[string]string{"pkg/.gitignore": "*.txt\n"})
+	waitReload(t, reloads, "pkg/.gitignore")
+
+	if repo.Match("pkg/a.tmp") || !repo.Match("pkg/a.txt") {
+		t.Error("Match() does not honor the reloaded .gitignore file")
+	}
+
+	writeTree(t, root, map[string]string{".git/info/exclude": "*.md\n"})
+	waitReload(t, reloads, filepath.Join(root, ".git", "info", "exclude"))
+
+	if !repo.Match("README.md") {
+		t.Error("Match() does not honor the reloaded info/exclude file")
+	}
+
+	if err = os.Remove(filepath.Join(root, ".gitignore")); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	waitReload(t, reloads, ".gitignore")
+
+	if repo.Match("a.log") {
+		t.Error("Match() honors the rules of a removed .gitignore file")
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, ".gitignore")
+
+	if err := os.WriteFile(name, []byte("*.log\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	w, err := gitignore.WatchFile(name)
+	if err != nil {
+		t.Fatalf("WatchFile() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := w.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+
+	reloads := subscribe(t, w.Subscribe)
+
+	if !w.Match("a.log") {
+		t.Fatalf("Match(%q) = false, want true", "a.log")
+	}
+
+	// Replace the file like editors saving atomically do.
+	tmp := filepath.Join(dir, "tmp")
+
+	if err = os.WriteFile(tmp, []byte("*.txt\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if err = os.Rename(tmp, name); err != nil {
+		t.Fatalf("failed to rename file: %v", err)
+	}
+
+	waitReload(t, reloads, name)
+
+	if w.Match("a.log") || !w.Match("a.txt") {
+		t.Error("Match() does not honor the reloaded rules")
+	}
+
+	if w.File() == nil {
+		t.Error("File() = nil")
+	}
+}
+
+func TestWatchFile_Error(t *testing.T) {
+	t.Parallel()
+
+	if _, err := gitignore.WatchFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("WatchFile() error = nil, want an error")
+	}
+}
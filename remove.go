@@ -0,0 +1,57 @@
+package gitignore
+
+import (
+	"fmt"
+	"slices"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrRuleNotFound is returned when removing a rule that does not exist.
+const ErrRuleNotFound xerrors.Error = "rule not found"
+
+// RemoveIf removes every rule of f for which remove returns true, such as the
+// rules a user toggled off in an interactive tool, and returns the number of
+// rules removed. The remaining rules keep their order.
+//
+// Like AddLines, RemoveIf must not be called concurrently with other methods
+// of f.
+func (f *File) RemoveIf(remove func(info PatternInfo) bool) int {
+	n := len(f.patterns)
+
+	f.patterns = slices.DeleteFunc(f.patterns, func(pat *pattern.Pattern) bool {
+		return remove(f.info(pat))
+	})
+	f.index = newIndex(f.patterns)
+
+	return n - len(f.patterns)
+}
+
+// RemoveAt removes the rule at index i, in the order returned by Patterns. It
+// returns [ErrRuleNotFound] if there is no such rule.
+func (f *File) RemoveAt(i int) error {
+	if i < 0 || i >= len(f.patterns) {
+		return fmt.Errorf("%w: index %d of %d rules", ErrRuleNotFound, i, len(f.patterns))
+	}
+
+	f.patterns = slices.Delete(f.patterns, i, i+1)
+	f.index = newIndex(f.patterns)
+
+	return nil
+}
+
+// RemoveLine removes the rules read from the given line of source, which is
+// empty for rules created from lines, including the ones added by separate
+// calls to AddLines. It returns [ErrRuleNotFound] if there is no such rule.
+func (f *File) RemoveLine(source string, line int) error {
+	removed := f.RemoveIf(func(info PatternInfo) bool {
+		return info.Source == source && info.Line == line
+	})
+
+	if removed == 0 {
+		return fmt.Errorf("%w: %s:%d", ErrRuleNotFound, source, line)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package gitignore_test
+
+import (
+	"errors"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestFile_RemoveIf(t *testing.T) {
+	t.Parallel()
+
+	file := newTestMatcher(t, "*.log", "!keep.log", "build/", "!build/keep")
+
+	removed := file.RemoveIf(func(info gitignore.PatternInfo) bool {
+		return info.Negate
+	})
+	if removed != 2 {
+		t.Errorf("RemoveIf() = %d, want 2", removed)
+	}
+
+	if got, want := file.String(), "1: *.log\n3: build/"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if !file.Match("keep.log") {
+		t.Errorf("Match(%q) = false, want true", "keep.log")
+	}
+
+	if removed = file.RemoveIf(func(_ gitignore.PatternInfo) bool { return false }); removed != 0 {
+		t.Errorf("RemoveIf() = %d, want 0", removed)
+	}
+}
+
+func TestFile_RemoveAt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		giveIndex  int
+		wantString string
+		wantErr    error
+	}{
+		{
+			name:       "First rule",
+			giveIndex:  0,
+			wantString: "2: !keep.log\n3: build/",
+		},
+		{
+			name:       "Last rule",
+			giveIndex:  2,
+			wantString: "1: *.log\n2: !keep.log",
+		},
+		{
+			name:       "Negative index",
+			giveIndex:  -1,
+			wantString: "1: *.log\n2: !keep.log\n3: build/",
+			wantErr:    gitignore.ErrRuleNotFound,
+		},
+		{
+			name:       "Index out of range",
+			giveIndex:  3,
+			wantString: "1: *.log\n2: !keep.log\n3: build/",
+			wantErr:    gitignore.ErrRuleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file := newTestMatcher(t, "*.log", "!keep.log", "build/")
+
+			if err := file.RemoveAt(tt.giveIndex); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RemoveAt(%d) error = %v, want %v", tt.giveIndex, err, tt.wantErr)
+			}
+
+			if got := file.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestFile_RemoveLine(t *testing.T) {
+	t.Parallel()
+
+	file := newTestMatcher(t, "# comment", "*.log", "", "build/")
+
+	if err := file.AddLines("*.tmp", "*.bak"); err != nil {
+		t.Fatalf("AddLines() error = %v", err)
+	}
+
+	if err := file.RemoveLine("", 2); err != nil {
+		t.Fatalf("RemoveLine() error = %v", err)
+	}
+
+	if got, want := file.String(), "4: build/\n1: *.tmp"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if err := file.RemoveLine("", 3); !errors.Is(err, gitignore.ErrRuleNotFound) {
+		t.Errorf("RemoveLine() error = %v, want %v", err, gitignore.ErrRuleNotFound)
+	}
+
+	if err := file.RemoveLine(".gitignore", 4); !errors.Is(err, gitignore.ErrRuleNotFound) {
+		t.Errorf("RemoveLine() error = %v, want %v", err, gitignore.ErrRuleNotFound)
+	}
+}
@@ -0,0 +1,778 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/gitconfig"
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// ErrNotRepository is returned when opening a directory that is not the
+	// root of a git working tree.
+	ErrNotRepository xerrors.Error = "not a git repository"
+
+	// ErrInvalidGitFile is returned along with ErrNotRepository when a .git
+	// file does not point to a git directory.
+	ErrInvalidGitFile xerrors.Error = "invalid gitfile format"
+)
+
+// RepositoryOption configures a Repository.
+type RepositoryOption func(o *repositoryOptions)
+
+// repositoryOptions holds the configuration set by the options given to
+// OpenRepository.
+type repositoryOptions struct {
+	getenv            func(key string) string
+	isTracked         func(path string) bool
+	blobs             BlobReader
+	home              string
+	configHome        string
+	patterns          []string
+	noDefaultExcludes bool
+	ignoreSubmodules  bool
+	ignoreCase        *bool
+	watch             bool
+	keepGitDir        bool
+}
+
+// WithHomeDir sets the directory used as the home directory of the user, which
+// holds the global .gitconfig file and which a leading "~/" in the path of
+// core.excludesFile expands to, instead of the one of the current user. It
+// allows tests and hermetic builds to control the global configuration.
+func WithHomeDir(dir string) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.home = dir
+	}
+}
+
+// WithEnv sets the function environment variables, such as GIT_DIR, are read
+// with, instead of [os.Getenv], so callers can open repositories as if run in
+// another environment.
+func WithEnv(getenv func(key string) string) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.getenv = getenv
+	}
+}
+
+// WithConfigHome sets the base directory of user-specific configuration files,
+// which holds git/config and the default git/ignore excludes file, instead of
+// $XDG_CONFIG_HOME, or ~/.config if unset.
+func WithConfigHome(dir string) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.configHome = dir
+	}
+}
+
+// WithoutDefaultExcludes disables the lookup of the default excludes file,
+// git/ignore in the configuration home, when core.excludesFile is not set, so
+// hermetic environments are not affected by the files of the current user.
+func WithoutDefaultExcludes() RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.noDefaultExcludes = true
+	}
+}
+
+// WithCoreIgnoreCase sets whether the rules match paths regardless of case,
+// overriding core.ignoreCase, such as when the configuration does not reflect
+// the file system the tree is read from.
+func WithCoreIgnoreCase(ignoreCase bool) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.ignoreCase = &ignoreCase
+	}
+}
+
+// WithPatterns adds rules that take precedence over every ignore file, like the
+// patterns given to git ls-files with --exclude, so tools can layer their own
+// rules, such as user-supplied excludes, over the ones of the repository. They
+// apply to the whole tree, like the rules of its root .gitignore file.
+func WithPatterns(lines ...string) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.patterns = append(o.patterns, lines...)
+	}
+}
+
+// WithIsTracked sets a function reporting whether the file at path, given in
+// the form taken by Match, is tracked, such as one looking it up in the index
+// or in the output of git ls-files. Like git, Match never reports tracked files
+// as ignored, even if a rule matches them or they are inside an ignored
+// directory, so results agree with git status.
+func WithIsTracked(fn func(path string) bool) RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.isTracked = fn
+	}
+}
+
+// Repository matches paths against every ignore rule git applies to a working
+// tree: the file set by core.excludesFile, $GIT_DIR/info/exclude, and the
+// .gitignore file of every directory.
+//
+// Like git, the rules are consulted from the highest precedence to the lowest,
+// and the first source with a rule matching a path decides whether it is
+// ignored: the rules set with WithPatterns, then the .gitignore files from the
+// deepest directory to the root, then info/exclude, and finally the excludes
+// file. Within a source, the last matching rule decides, so a negated rule only
+// re-includes a path ignored by a lower-precedence source or by an earlier rule
+// of the same source. The .git directories of the working tree are always
+// ignored, unless WithoutGitDirIgnored is given.
+//
+// The .gitignore file of a directory is read the first time a path inside it is
+// matched, so opening a large tree is cheap. Like git, the files of ignored
+// directories are never read.
+//
+// A Repository is safe for concurrent use.
+type Repository struct {
+	// config holds the global configuration merged with the one of the
+	// repository.
+	config gitconfig.Config
+
+	// patterns holds the rules set with WithPatterns, which take precedence
+	// over every ignore file.
+	patterns *File
+
+	// gitDirs ignores the .git directories of the working tree, unless
+	// disabled with WithoutGitDirIgnored, in which case it is nil.
+	gitDirs *File
+
+	// isTracked reports whether a file is tracked, in which case it is never
+	// ignored. It is nil if unknown.
+	isTracked func(path string) bool
+
+	// excludes holds the rules of core.excludesFile and info/exclude, which
+	// apply to the whole tree with the lowest precedence.
+	excludes atomic.Pointer[File]
+
+	// excludesErr holds the errors reading the excludes files the last time
+	// they were read, joined, or nil if there were none.
+	excludesErr error
+
+	// fsys is the working tree, which the .gitignore files are read from.
+	fsys fs.FS
+
+	// submodules holds the paths of the submodules of the working tree,
+	// relative to root, mapped to whether they are registered in .gitmodules.
+	// Nested repositories are added as they are found.
+	submodules map[string]bool
+
+	// probed holds the directories already checked for a nested repository.
+	probed map[string]struct{}
+
+	// ignores caches the .gitignore file of every directory read so far,
+	// keyed by the slash-separated path of the directory relative to root, or
+	// "." for root itself.
+	ignores map[string]*ignoreFile
+
+	// mu guards submodules, probed, ignores and excludesErr.
+	mu sync.Mutex
+
+	// watcher watches the ignore files of the repository, if enabled with
+	// WithWatch, and watching is closed once it stops.
+	watcher  *fsnotify.Watcher
+	watching chan struct{}
+
+	// subscribers holds the functions set with Subscribe.
+	subscribers subscriptions[Reload]
+
+	root         string
+	gitDir       string
+	commonDir    string
+	excludesFile string
+
+	ignoreSubmodules bool
+	ignoreCase       bool
+	bare             bool
+
+	// blobs is set if the files of the tree are read with a BlobReader
+	// rather than from the working tree.
+	blobs bool
+}
+
+// OpenRepository returns a Repository for the git working tree rooted at root.
+//
+// Like git, the git directory is the one named by GIT_DIR, or root/.git, which
+// may also be a file pointing to it, as in linked worktrees and submodules. The
+// working tree is the one named by GIT_WORK_TREE, or root, and the directory
+// holding the configuration and info/exclude is the one named by
+// GIT_COMMON_DIR, or the one the git directory points to in its commondir file,
+// or the git directory itself. Relative paths in the environment are resolved
+// against the current directory.
+//
+// The repository is bare, without a working tree, when root is itself a git
+// directory without a .git entry, as in a bare clone, or when core.bare is set
+// and GIT_WORK_TREE is not. See Bare.
+//
+// The global configuration, $XDG_CONFIG_HOME/git/config and ~/.gitconfig, and
+// the configuration of the repository are read for core.excludesFile and
+// core.ignoreCase, the one of the repository taking precedence. When
+// core.ignoreCase is set, the rules match paths regardless of case, like git on
+// case-insensitive file systems, unless overridden with WithCoreIgnoreCase.
+// When core.excludesFile is not set, git's default of
+// $XDG_CONFIG_HOME/git/ignore is used, or ~/.config/git/ignore if
+// $XDG_CONFIG_HOME is not set, unless WithoutDefaultExcludes is given. A
+// missing excludes file is not an error, like with git, and one that cannot be
+// read or parsed does not contribute any rule and is reported by Err instead of
+// failing. The .gitignore files of the tree are read on demand, and the errors
+// reading them are reported by Err as well.
+func OpenRepository(root string, opts ...RepositoryOption) (*Repository, error) {
+	o := &repositoryOptions{
+		getenv: os.Getenv,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.home == "" {
+		// Without a home directory, there simply is no global configuration.
+		o.home, _ = os.UserHomeDir()
+	}
+
+	if o.configHome == "" {
+		o.configHome = o.getenv("XDG_CONFIG_HOME")
+	}
+
+	if o.configHome == "" && o.home != "" {
+		o.configHome = filepath.Join(o.home, ".config")
+	}
+
+	r, err := locate(root, o.getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.loadConfig(o); err != nil {
+		return nil, err
+	}
+
+	if !r.bare && o.getenv("GIT_WORK_TREE") == "" {
+		if r.bare, err = r.config.Bool("core.bare"); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	if r.bare {
+		r.setBare()
+	}
+
+	if o.blobs != nil {
+		r.fsys = blobFS(o.blobs)
+		r.blobs = true
+	}
+
+	if r.patterns, err = NewFromLines(o.patterns, r.options()...); err != nil {
+		return nil, err
+	}
+
+	if !o.keepGitDir {
+		if r.gitDirs, err = NewFromLines(nil, WithGitDirIgnored()); err != nil {
+			return nil, err
+		}
+	}
+
+	r.isTracked = o.isTracked
+	r.ignoreSubmodules = o.ignoreSubmodules
+
+	if err = r.loadSubmodules(); err != nil {
+		return nil, err
+	}
+
+	// Like git, an excludes file that cannot be read only loses its rules,
+	// and the error is reported by Err.
+	_ = r.loadExcludes(o)
+
+	if o.watch {
+		if err = r.startWatching(); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Root returns the absolute path of the working tree of r, or an empty string
+// if r is bare.
+func (r *Repository) Root() string {
+	return r.root
+}
+
+// Err returns the errors encountered so far while reading the excludes files
+// and the .gitignore files of the working tree, joined, or nil if there were
+// none. Like git, a file that cannot be read or parsed is reported and does not
+// contribute any rule.
+func (r *Repository) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dirs := make([]string, 0, len(r.ignores))
+
+	for dir, entry := range r.ignores {
+		if entry.err != nil {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	slices.Sort(dirs)
+
+	errs := make([]error, 0, len(dirs)+1)
+	errs = append(errs, r.excludesErr)
+
+	for _, dir := range dirs {
+		errs = append(errs, r.ignores[dir].err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Match reports whether path is ignored, implementing [Matcher]. The path is
+// slash-separated and relative to the root of the working tree, with a trailing
+// slash for directories. Like git, anything inside an ignored directory is
+// ignored as well. Files reported as tracked by the function set with
+// WithIsTracked are never ignored.
+func (r *Repository) Match(path string) bool {
+	name := strings.TrimSuffix(path, "/")
+
+	if name == path && r.isTracked != nil && r.isTracked(name) {
+		return false
+	}
+
+	return IsIgnored(MatcherFunc(r.match), name, name != path)
+}
+
+// CheckIgnore is like Match, but also returns the rule deciding whether path is
+// ignored, along with its source file and line number, like git check-ignore
+// -v. For a path inside an ignored directory, it is the rule ignoring the
+// directory. It returns false if no rule matches path, in which case it is not
+// ignored, and for tracked files. Submodules ignored with WithSubmodulesIgnored
+// are reported with a zero Pattern.
+func (r *Repository) CheckIgnore(path string) (Result, bool) {
+	name := strings.TrimSuffix(path, "/")
+
+	if name == path && r.isTracked != nil && r.isTracked(name) {
+		return Result{}, false
+	}
+
+	for i := range len(name) {
+		if name[i] != '/' {
+			continue
+		}
+
+		if result, ok := r.check(name[:i+1]); ok && result.Ignored {
+			return result, true
+		}
+	}
+
+	if name != path {
+		name += "/"
+	}
+
+	return r.check(name)
+}
+
+// MatcherForDir returns a File holding every rule applying to the directory
+// dir, given as a slash-separated path relative to the root of the working
+// tree, or "." for the root itself, answering for paths relative to dir: the
+// rules of the excludes files, of the .gitignore files of dir and of its
+// parents, re-anchored to dir, and the ones set with WithPatterns, merged in
+// order of precedence. It lets callers sharding work per directory match paths
+// without going through the Repository.
+//
+// Like any File, the returned one matches paths inside ignored directories
+// only if the rules do; use IsIgnored to apply git's semantics. Nested
+// .gitignore files, submodules and tracked files below dir are not accounted
+// for. Inside a submodule, the rules of the repository do not apply, so only
+// git directories are ignored. The errors reading the .gitignore files are
+// returned, joined.
+func (r *Repository) MatcherForDir(dir string) (*File, error) {
+	dir = path.Clean(strings.TrimSuffix(filepath.ToSlash(dir), "/"))
+	if dir == ".." || strings.HasPrefix(dir, "../") || path.IsAbs(dir) {
+		return nil, fmt.Errorf("%w: %s", ErrOutsideBase, dir)
+	}
+
+	ancestors := []string{"."}
+
+	for i := range len(dir) {
+		if dir[i] == '/' {
+			ancestors = append(ancestors, dir[:i])
+		}
+	}
+
+	if dir != "." {
+		ancestors = append(ancestors, dir)
+	}
+
+	for _, ancestor := range ancestors[1:] {
+		r.probe(ancestor)
+	}
+
+	_, inside := r.submodule(dir)
+
+	files := make([]*File, 0, len(ancestors)+2)
+
+	if !inside {
+		files = append(files, r.excludes.Load().WithPrefix(dir))
+
+		for _, ancestor := range ancestors {
+			file := r.ignore(ancestor)
+			if file == nil {
+				continue
+			}
+
+			rel := strings.TrimPrefix(strings.TrimPrefix(dir, ancestor), "/")
+			if ancestor == "." {
+				rel = dir
+			}
+
+			files = append(files, file.WithPrefix(rel))
+		}
+
+		files = append(files, r.patterns.WithPrefix(dir))
+	}
+
+	merged := Merge(files...)
+
+	if r.gitDirs != nil {
+		merged.gitDir = r.gitDirs.gitDir
+	}
+
+	return merged, r.ancestorErrors(ancestors)
+}
+
+// ancestorErrors returns the errors reading the .gitignore files of the given
+// directories, joined.
+func (r *Repository) ancestorErrors(dirs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	errs := make([]error, 0)
+
+	for _, dir := range dirs {
+		if entry, ok := r.ignores[dir]; ok && entry.err != nil {
+			errs = append(errs, entry.err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// match reports whether name is ignored, without considering its parent
+// directories, which IsIgnored checks first.
+func (r *Repository) match(name string) bool {
+	result, ok := r.check(name)
+
+	return ok && result.Ignored
+}
+
+// check returns the decision for name and whether there is one, without
+// considering its parent directories, which callers check first. Directories
+// are thus only probed for nested repositories once their parents are known
+// not to be ignored.
+func (r *Repository) check(name string) (Result, bool) {
+	if r.gitDirs != nil {
+		if result, ok := r.gitDirs.MatchResult(name); ok {
+			return result, true
+		}
+	}
+
+	dir := strings.TrimSuffix(name, "/")
+
+	if dir != name {
+		r.probe(dir)
+	}
+
+	if sub, ok := r.submodule(dir); ok {
+		if sub != dir {
+			// The rules of the repository do not apply inside submodules,
+			// and the submodule itself was not ignored.
+			return Result{}, false
+		}
+
+		return r.checkSubmodule(name)
+	}
+
+	return r.checkRules(name)
+}
+
+// checkRules returns the decision of the rules applying to name, and whether
+// any of them matches it.
+func (r *Repository) checkRules(name string) (Result, bool) {
+	if result, ok := r.patterns.MatchResult(name); ok {
+		return result, true
+	}
+
+	dir := path.Dir(strings.TrimSuffix(name, "/"))
+
+	for {
+		if file := r.ignore(dir); file != nil {
+			rel := name
+			if dir != "." {
+				rel = strings.TrimPrefix(name, dir+"/")
+			}
+
+			if result, ok := file.MatchResult(rel); ok {
+				return result, true
+			}
+		}
+
+		if dir == "." {
+			break
+		}
+
+		dir = path.Dir(dir)
+	}
+
+	return r.excludes.Load().MatchResult(name)
+}
+
+// locate returns a Repository for the working tree rooted at root, without any
+// rules, honoring the git environment variables read with getenv.
+func locate(root string, getenv func(key string) string) (*Repository, error) {
+	if workTree := getenv("GIT_WORK_TREE"); workTree != "" {
+		root = workTree
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	var (
+		dir  = getenv("GIT_DIR")
+		bare bool
+	)
+
+	if dir == "" {
+		dir = filepath.Join(root, gitDir)
+
+		// Without a .git directory, root may be the git directory of a bare
+		// repository itself.
+		if _, err = os.Lstat(dir); errors.Is(err, fs.ErrNotExist) && isGitDir(root) {
+			dir, bare = root, true
+		}
+	}
+
+	dir, err = resolveGitDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrNotRepository, root, err)
+	}
+
+	commonDir := getenv("GIT_COMMON_DIR")
+	if commonDir == "" {
+		commonDir = dir
+
+		if data, err := os.ReadFile(filepath.Join(dir, "commondir")); err == nil {
+			commonDir = strings.TrimSpace(string(data))
+
+			// The commondir file is relative to the git directory.
+			if !filepath.IsAbs(commonDir) {
+				commonDir = filepath.Join(dir, commonDir)
+			}
+		}
+	}
+
+	if commonDir, err = filepath.Abs(commonDir); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return &Repository{
+		root:       root,
+		gitDir:     dir,
+		commonDir:  commonDir,
+		bare:       bare,
+		fsys:       os.DirFS(root),
+		ignores:    make(map[string]*ignoreFile),
+		submodules: make(map[string]bool),
+		probed:     make(map[string]struct{}),
+	}, nil
+}
+
+// resolveGitDir returns the absolute path of the git directory at dir, which
+// may be a directory or a file holding a "gitdir: <path>" line pointing to it.
+func resolveGitDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	if info.IsDir() {
+		return dir, nil
+	}
+
+	data, err := os.ReadFile(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrInvalidGitFile, dir)
+	}
+
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(dir), target)
+	}
+
+	// Unlike a .git file, the git directory it points to must be a
+	// directory.
+	if info, err = os.Stat(target); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidGitFile, dir)
+	}
+
+	return target, nil
+}
+
+// loadConfig reads the global configuration and the one of the repository.
+func (r *Repository) loadConfig(o *repositoryOptions) error {
+	// Later files take precedence over earlier ones.
+	names := make([]string, 0, 3)
+
+	if o.configHome != "" {
+		names = append(names, filepath.Join(o.configHome, "git", "config"))
+	}
+
+	if o.home != "" {
+		names = append(names, filepath.Join(o.home, ".gitconfig"))
+	}
+
+	names = append(names, filepath.Join(r.commonDir, "config"))
+
+	config := gitconfig.Config{}
+
+	for _, name := range names {
+		c, err := gitconfig.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		config = config.Merge(c)
+	}
+
+	r.config = config
+
+	if o.ignoreCase != nil {
+		r.ignoreCase = *o.ignoreCase
+
+		return nil
+	}
+
+	ignoreCase, err := config.Bool("core.ignoreCase")
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	r.ignoreCase = ignoreCase
+
+	return nil
+}
+
+// IgnoreCase reports whether the rules of r match paths regardless of case, as
+// set by core.ignoreCase or WithCoreIgnoreCase.
+func (r *Repository) IgnoreCase() bool {
+	return r.ignoreCase
+}
+
+// options returns the options the ignore files of r are parsed with.
+func (r *Repository) options() []Option {
+	if r.ignoreCase {
+		return []Option{WithIgnoreCase()}
+	}
+
+	return nil
+}
+
+// loadExcludes reads the rules of core.excludesFile and info/exclude.
+func (r *Repository) loadExcludes(o *repositoryOptions) error {
+	if name, ok := r.config.Get("core.excludesFile"); ok && name != "" {
+		base := r.root
+		if r.bare {
+			base = r.gitDir
+		}
+
+		r.excludesFile = expandHome(name, o.home, base)
+	} else if !o.noDefaultExcludes && o.configHome != "" {
+		r.excludesFile = filepath.Join(o.configHome, "git", "ignore")
+	}
+
+	return r.readExcludes()
+}
+
+// readExcludes reads the rules of the excludes file and info/exclude, and
+// swaps them in. A file that cannot be read or parsed does not contribute any
+// rule, and the errors are recorded for Err and returned, joined.
+func (r *Repository) readExcludes() error {
+	var (
+		names = []string{r.excludesFile, r.infoExclude()}
+		files = make([]*File, 0, len(names))
+		errs  = make([]error, 0)
+	)
+
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		file, err := readOptional(name, r.options()...)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	err := errors.Join(errs...)
+
+	r.mu.Lock()
+	r.excludesErr = err
+	r.mu.Unlock()
+
+	r.excludes.Store(Merge(files...))
+
+	return err
+}
+
+// infoExclude returns the path of the info/exclude file of r.
+func (r *Repository) infoExclude() string {
+	return filepath.Join(r.commonDir, "info", "exclude")
+}
+
+// readOptional returns the rules of the file at name, or an empty File if it
+// does not exist.
+func readOptional(name string, opts ...Option) (*File, error) {
+	file, err := New(name, opts...)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &File{}, nil
+	}
+
+	return file, err
+}
+
+// expandHome resolves name, a path read from the git configuration, expanding
+// a leading "~/" to home and resolving relative paths against root.
+func expandHome(name, home, root string) string {
+	if rest, ok := strings.CutPrefix(name, "~/"); ok && home != "" {
+		return filepath.Join(home, filepath.FromSlash(rest))
+	}
+
+	if !filepath.IsAbs(name) {
+		return filepath.Join(root, filepath.FromSlash(name))
+	}
+
+	return name
+}
@@ -0,0 +1,772 @@
+package gitignore_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+// writeTree creates the files in files, keyed by their slash-separated path
+// relative to root, along with their parent directories.
+func writeTree(t testing.TB, root string, files map[string]string) {
+	t.Helper()
+
+	for name, data := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+}
+
+func TestOpenRepository(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	root := t.TempDir()
+
+	writeTree(t, home, map[string]string{
+		".gitconfig":        "[core]\n\texcludesFile = ~/.config/ignore\n",
+		".config/ignore":    "*.swp\n.DS_Store\n*.bak\n",
+		"unused/.gitignore": "",
+	})
+
+	writeTree(t, root, map[string]string{
+		".git/config":        "[core]\n\tbare = false\n",
+		".git/info/exclude":  "secret.txt\n!*.bak\n",
+		".gitignore":         "*.log\n!keep.swp\n/build/\n",
+		"build/.gitignore":   "!*.log\n",
+		"pkg/.gitignore":     "!debug.log\n*.tmp\n",
+		"pkg/sub/.gitignore": "!cache.tmp\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(home), gitignore.WithConfigHome(filepath.Join(home, ".config")))
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	if repo.Root() != root {
+		t.Errorf("Root() = %q, want %q", repo.Root(), root)
+	}
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: "main.go", want: false},
+		{givePath: "a.swp", want: true},
+		{givePath: "pkg/.DS_Store", want: true},
+		{givePath: "keep.swp", want: false},
+		{givePath: "secret.txt", want: true},
+		{givePath: "old.bak", want: false},
+		{givePath: "debug.log", want: true},
+		{givePath: "pkg/debug.log", want: false},
+		{givePath: "pkg/other.log", want: true},
+		{givePath: "pkg/a.tmp", want: true},
+		{givePath: "pkg/sub/cache.tmp", want: false},
+		{givePath: "pkg/sub/other.tmp", want: true},
+		{givePath: "build/", want: true},
+		{givePath: "build/out.log", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := repo.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+}
+
+func TestOpenRepository_ExcludesFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveHome    map[string]string
+		giveRepo    map[string]string
+		wantIgnored []string
+		wantKept    []string
+	}{
+		{
+			name:     "Repository configuration takes precedence",
+			giveHome: map[string]string{".gitconfig": "[core]\nexcludesFile = ~/global\n", "global": "*.swp\n"},
+			giveRepo: map[string]string{
+				".git/config":  "[core]\nexcludesfile = local-ignore\n",
+				"local-ignore": "*.tmp\n",
+			},
+			wantIgnored: []string{"a.tmp"},
+			wantKept:    []string{"a.swp"},
+		},
+		{
+			name:        "Missing excludes file",
+			giveHome:    map[string]string{".gitconfig": "[core]\nexcludesFile = ~/missing\n"},
+			giveRepo:    map[string]string{".git/config": ""},
+			wantKept:    []string{"a.swp"},
+			wantIgnored: nil,
+		},
+		{
+			name:        "No configuration",
+			giveHome:    map[string]string{},
+			giveRepo:    map[string]string{".git/info/exclude": "*.swp\n"},
+			wantIgnored: []string{"a.swp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			home := t.TempDir()
+			root := t.TempDir()
+
+			writeTree(t, home, tt.giveHome)
+			writeTree(t, root, tt.giveRepo)
+
+			if err := os.MkdirAll(filepath.Join(root, ".git"), 0o700); err != nil {
+				t.Fatalf("failed to create directory: %v", err)
+			}
+
+			repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(home), gitignore.WithConfigHome(filepath.Join(home, ".config")))
+			if err != nil {
+				t.Fatalf("OpenRepository() error = %v", err)
+			}
+
+			for _, path := range tt.wantIgnored {
+				if !repo.Match(path) {
+					t.Errorf("Match(%q) = false, want true", path)
+				}
+			}
+
+			for _, path := range tt.wantKept {
+				if repo.Match(path) {
+					t.Errorf("Match(%q) = true, want false", path)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenRepository_InvalidExcludesFile(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	root := t.TempDir()
+
+	writeTree(t, home, map[string]string{
+		".gitconfig": "[core]\nexcludesFile = ~/global\n",
+		"global":     "*.swp\n[invalid\n",
+	})
+
+	writeTree(t, root, map[string]string{
+		".git/config":       "",
+		".git/info/exclude": "*.tmp\n",
+		".gitignore":        "*.log\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(home), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	if err = repo.Err(); !errors.Is(err, gitignore.ErrRegexCompile) {
+		t.Errorf("Err() = %v, want %v", err, gitignore.ErrRegexCompile)
+	}
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: "a.log", want: true},
+		{givePath: "a.tmp", want: true},
+		{givePath: "a.swp", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := repo.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+}
+
+func TestOpenRepository_DefaultExcludes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveHome    map[string]string
+		giveOpts    func(home string) []gitignore.RepositoryOption
+		wantIgnored []string
+		wantKept    []string
+	}{
+		{
+			name:     "Configuration home",
+			giveHome: map[string]string{"xdg/git/ignore": "*.swp\n", ".config/git/ignore": "*.tmp\n"},
+			giveOpts: func(home string) []gitignore.RepositoryOption {
+				return []gitignore.RepositoryOption{gitignore.WithConfigHome(filepath.Join(home, "xdg"))}
+			},
+			wantIgnored: []string{"a.swp"},
+			wantKept:    []string{"a.tmp"},
+		},
+		{
+			name:     "Default configuration home",
+			giveHome: map[string]string{".config/git/ignore": "*.tmp\n"},
+			giveOpts: func(home string) []gitignore.RepositoryOption {
+				return []gitignore.RepositoryOption{gitignore.WithConfigHome(filepath.Join(home, ".config"))}
+			},
+			wantIgnored: []string{"a.tmp"},
+		},
+		{
+			name: "Configuration in configuration home",
+			giveHome: map[string]string{
+				".config/git/config": "[core]\nexcludesFile = ~/global\n",
+				".config/git/ignore": "*.tmp\n",
+				"global":             "*.swp\n",
+			},
+			giveOpts: func(home string) []gitignore.RepositoryOption {
+				return []gitignore.RepositoryOption{gitignore.WithConfigHome(filepath.Join(home, ".config"))}
+			},
+			wantIgnored: []string{"a.swp"},
+			wantKept:    []string{"a.tmp"},
+		},
+		{
+			name:     "Disabled",
+			giveHome: map[string]string{".config/git/ignore": "*.tmp\n"},
+			giveOpts: func(home string) []gitignore.RepositoryOption {
+				return []gitignore.RepositoryOption{
+					gitignore.WithConfigHome(filepath.Join(home, ".config")),
+					gitignore.WithoutDefaultExcludes(),
+				}
+			},
+			wantKept: []string{"a.tmp"},
+		},
+		{
+			name: "Disabled with core.excludesFile",
+			giveHome: map[string]string{
+				".gitconfig": "[core]\nexcludesFile = ~/global\n",
+				"global":     "*.swp\n",
+			},
+			giveOpts: func(home string) []gitignore.RepositoryOption {
+				return []gitignore.RepositoryOption{
+					gitignore.WithConfigHome(filepath.Join(home, ".config")),
+					gitignore.WithoutDefaultExcludes(),
+				}
+			},
+			wantIgnored: []string{"a.swp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			home := t.TempDir()
+			root := t.TempDir()
+
+			writeTree(t, home, tt.giveHome)
+			writeTree(t, root, map[string]string{".git/config": ""})
+
+			opts := append([]gitignore.RepositoryOption{gitignore.WithHomeDir(home)}, tt.giveOpts(home)...)
+
+			repo, err := gitignore.OpenRepository(root, opts...)
+			if err != nil {
+				t.Fatalf("OpenRepository() error = %v", err)
+			}
+
+			for _, path := range tt.wantIgnored {
+				if !repo.Match(path) {
+					t.Errorf("Match(%q) = false, want true", path)
+				}
+			}
+
+			for _, path := range tt.wantKept {
+				if repo.Match(path) {
+					t.Errorf("Match(%q) = true, want false", path)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenRepository_Environment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveTree map[string]string
+		giveRoot string
+		giveEnv  map[string]string
+	}{
+		{
+			name: "GIT_DIR",
+			giveTree: map[string]string{
+				"separate.git/config":       "",
+				"separate.git/info/exclude": "*.swp\n",
+				"work/.gitignore":           "*.log\n",
+			},
+			giveRoot: "work",
+			giveEnv:  map[string]string{"GIT_DIR": "separate.git"},
+		},
+		{
+			name: "GIT_WORK_TREE",
+			giveTree: map[string]string{
+				"elsewhere/.keep":        "",
+				"work/.git/info/exclude": "*.swp\n",
+				"work/.gitignore":        "*.log\n",
+			},
+			giveRoot: "elsewhere",
+			giveEnv:  map[string]string{"GIT_WORK_TREE": "work", "GIT_DIR": "work/.git"},
+		},
+		{
+			name: "Linked worktree",
+			giveTree: map[string]string{
+				"main/.git/info/exclude":           "*.swp\n",
+				"main/.git/worktrees/wt/commondir": "../..\n",
+				"work/.git":                        "gitdir: ../main/.git/worktrees/wt\n",
+				"work/.gitignore":                  "*.log\n",
+			},
+			giveRoot: "work",
+		},
+		{
+			name: "GIT_COMMON_DIR",
+			giveTree: map[string]string{
+				"common/info/exclude": "*.swp\n",
+				"work/.git/config":    "",
+				"work/.gitignore":     "*.log\n",
+			},
+			giveRoot: "work",
+			giveEnv:  map[string]string{"GIT_COMMON_DIR": "common"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			writeTree(t, dir, tt.giveTree)
+
+			getenv := func(key string) string {
+				value, ok := tt.giveEnv[key]
+				if ok && !filepath.IsAbs(value) {
+					// Relative paths are resolved against the current
+					// directory, which tests run in parallel cannot change.
+					return filepath.Join(dir, value)
+				}
+
+				return value
+			}
+
+			repo, err := gitignore.OpenRepository(
+				filepath.Join(dir, tt.giveRoot),
+				gitignore.WithEnv(getenv),
+				gitignore.WithHomeDir(dir),
+				gitignore.WithoutDefaultExcludes(),
+			)
+			if err != nil {
+				t.Fatalf("OpenRepository() error = %v", err)
+			}
+
+			if want := filepath.Join(dir, "work"); repo.Root() != want {
+				t.Errorf("Root() = %q, want %q", repo.Root(), want)
+			}
+
+			for _, path := range []string{"a.swp", "a.log"} {
+				if !repo.Match(path) {
+					t.Errorf("Match(%q) = false, want true", path)
+				}
+			}
+
+			if repo.Match("main.go") {
+				t.Errorf("Match(%q) = true, want false", "main.go")
+			}
+		})
+	}
+}
+
+func TestOpenRepository_InvalidGitFile(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{".git": "not a gitfile\n"})
+
+	_, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if !errors.Is(err, gitignore.ErrNotRepository) || !errors.Is(err, gitignore.ErrInvalidGitFile) {
+		t.Errorf("OpenRepository() error = %v, want %v and %v", err, gitignore.ErrNotRepository, gitignore.ErrInvalidGitFile)
+	}
+}
+
+func TestOpenRepository_Patterns(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/info/exclude": "*.swp\n",
+		".gitignore":        "*.log\n!keep.tmp\n",
+		"pkg/.gitignore":    "!debug.log\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithPatterns("*.tmp", "!a.swp"),
+		gitignore.WithPatterns("pkg/debug.log"),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: "keep.tmp", want: true},
+		{givePath: "a.swp", want: false},
+		{givePath: "b.swp", want: true},
+		{givePath: "pkg/debug.log", want: true},
+		{givePath: "pkg/sub/debug.log", want: false},
+		{givePath: "debug.log", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := repo.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+
+	if _, err = gitignore.OpenRepository(root, gitignore.WithPatterns("[invalid")); !errors.Is(err, gitignore.ErrRegexCompile) {
+		t.Errorf("OpenRepository() error = %v, want %v", err, gitignore.ErrRegexCompile)
+	}
+}
+
+func TestOpenRepository_IgnoreCase(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":    "[core]\n\tignoreCase = true\n",
+		".gitignore":     "*.log\nbuild/\n!Keep.LOG\n",
+		"pkg/.gitignore": "Docs\n",
+	})
+
+	tests := []struct {
+		name     string
+		giveOpts []gitignore.RepositoryOption
+		want     map[string]bool
+	}{
+		{
+			name: "Configuration",
+			want: map[string]bool{
+				"A.LOG":      true,
+				"keep.log":   false,
+				"BUILD/":     true,
+				"Build/x":    true,
+				"pkg/docs":   true,
+				"pkg/DOCS/a": true,
+				"x.Txt":      false,
+			},
+		},
+		{
+			name:     "Override",
+			giveOpts: []gitignore.RepositoryOption{gitignore.WithCoreIgnoreCase(false)},
+			want: map[string]bool{
+				"A.LOG":    false,
+				"keep.log": true,
+				"BUILD/":   false,
+				"pkg/docs": false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]gitignore.RepositoryOption{
+				gitignore.WithHomeDir(t.TempDir()),
+				gitignore.WithoutDefaultExcludes(),
+			}, tt.giveOpts...)
+
+			repo, err := gitignore.OpenRepository(root, opts...)
+			if err != nil {
+				t.Fatalf("OpenRepository() error = %v", err)
+			}
+
+			if got, want := repo.IgnoreCase(), tt.giveOpts == nil; got != want {
+				t.Errorf("IgnoreCase() = %v, want %v", got, want)
+			}
+
+			for path, want := range tt.want {
+				if got := repo.Match(path); got != want {
+					t.Errorf("Match(%q) = %v, want %v", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRepository_IsTracked(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config": "",
+		".gitignore":  "*.log\nbuild/\n",
+	})
+
+	tracked := map[string]bool{"debug.log": true, "build/keep.o": true}
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithIsTracked(func(path string) bool {
+			return tracked[path]
+		}),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: "debug.log", want: false},
+		{givePath: "other.log", want: true},
+		{givePath: "build/keep.o", want: false},
+		{givePath: "build/other.o", want: true},
+		{givePath: "build/", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := repo.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+}
+
+func TestOpenRepository_Error(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	if _, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes()); !errors.Is(err, gitignore.ErrNotRepository) {
+		t.Errorf("OpenRepository() error = %v, want %v", err, gitignore.ErrNotRepository)
+	}
+
+	writeTree(t, root, map[string]string{
+		".git/config":     "",
+		"pkg/.gitignore":  "*.log\n[invalid\n",
+		"pkg/main.go":     "",
+		".gitignore":      "",
+		"docs/index.html": "",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	// The .gitignore files are only read as paths inside them are matched.
+	if err = repo.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+
+	if repo.Match("pkg/debug.log") {
+		t.Errorf("Match(%q) = true, want false for a file whose rules failed to parse", "pkg/debug.log")
+	}
+
+	var parseErr *gitignore.ParseError
+	if err = repo.Err(); !errors.As(err, &parseErr) || parseErr.Source != "pkg/.gitignore" || parseErr.Line != 2 {
+		t.Errorf("Err() = %v, want a *ParseError at pkg/.gitignore:2", err)
+	}
+}
+
+func TestOpenRepository_Lazy(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":    "",
+		".gitignore":     "*.log\n",
+		"a/.gitignore":   "*.tmp\n",
+		"a/b/file.txt":   "",
+		"c/.gitignore":   "",
+		"c/d/file.txt":   "",
+		"e/.gitignore":   "*.go\n",
+		"e/f/.gitignore": "!*.go\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	// A .gitignore file created after opening the repository is picked up
+	// if its directory was not matched yet.
+	writeTree(t, root, map[string]string{"c/d/.gitignore": "*.txt\n"})
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: "debug.log", want: true},
+		{givePath: "a/b/x.tmp", want: true},
+		{givePath: "a/b/x.txt", want: false},
+		{givePath: "c/d/file.txt", want: true},
+		{givePath: "e/main.go", want: true},
+		{givePath: "e/f/main.go", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := repo.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+
+	if err = repo.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestRepository_CheckIgnore(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/info/exclude": "*.swp\n",
+		".gitignore":        "# Logs.\n*.log\nbuild/\n",
+		"pkg/.gitignore":    "!keep.log\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithPatterns("*.tmp"),
+		gitignore.WithIsTracked(func(path string) bool {
+			return path == "tracked.log"
+		}),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath    string
+		wantRaw     string
+		wantSource  string
+		wantLine    int
+		wantIgnored bool
+		wantOK      bool
+	}{
+		{givePath: "debug.log", wantRaw: "*.log", wantSource: ".gitignore", wantLine: 2, wantIgnored: true, wantOK: true},
+		{givePath: "pkg/keep.log", wantRaw: "!keep.log", wantSource: "pkg/.gitignore", wantLine: 1, wantOK: true},
+		{givePath: "build/sub/main.o", wantRaw: "build/", wantSource: ".gitignore", wantLine: 3, wantIgnored: true, wantOK: true},
+		{givePath: "a.swp", wantRaw: "*.swp", wantSource: filepath.Join(root, ".git", "info", "exclude"), wantLine: 1, wantIgnored: true, wantOK: true},
+		{givePath: "pkg/a.tmp", wantRaw: "*.tmp", wantLine: 1, wantIgnored: true, wantOK: true},
+		{givePath: "main.go"},
+		{givePath: "tracked.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.givePath, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := repo.CheckIgnore(tt.givePath)
+			if ok != tt.wantOK {
+				t.Fatalf("CheckIgnore() ok = %v, want %v", ok, tt.wantOK)
+			}
+
+			if got.Pattern.Raw != tt.wantRaw || got.Pattern.Source != tt.wantSource || got.Pattern.Line != tt.wantLine {
+				t.Errorf("CheckIgnore() pattern = %s:%d: %q, want %s:%d: %q",
+					got.Pattern.Source, got.Pattern.Line, got.Pattern.Raw, tt.wantSource, tt.wantLine, tt.wantRaw)
+			}
+
+			if got.Ignored != tt.wantIgnored {
+				t.Errorf("CheckIgnore() ignored = %v, want %v", got.Ignored, tt.wantIgnored)
+			}
+
+			if match := repo.Match(tt.givePath); match != tt.wantIgnored {
+				t.Errorf("Match() = %v, disagrees with CheckIgnore() ignored = %v", match, tt.wantIgnored)
+			}
+		})
+	}
+}
+
+func TestRepository_MatcherForDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/info/exclude": "*.swp\n",
+		".gitmodules":       "[submodule \"lib\"]\n\tpath = lib\n",
+		".gitignore":        "*.log\n/top.txt\na/b/anchored.txt\nbuild/\n",
+		"a/.gitignore":      "!keep.log\n/b/c/deep.txt\n*.tmp\n",
+		"a/b/.gitignore":    "!x.tmp\n",
+		"lib/.git":          "gitdir: ../.git/modules/lib\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithPatterns("*.bak"),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	paths := []string{
+		"debug.log", "keep.log", "top.txt", "anchored.txt", "x.tmp", "y.tmp",
+		"a.swp", "a.bak", "c/deep.txt", "deep.txt", "build/", "build/out.o",
+		".git/config", "main.go",
+	}
+
+	for _, dir := range []string{".", "a", "a/b", "a/b/c", "x"} {
+		file, err := repo.MatcherForDir(dir)
+		if err != nil {
+			t.Fatalf("MatcherForDir(%q) error = %v", dir, err)
+		}
+
+		for _, path := range paths {
+			name := path
+			if dir != "." {
+				name = dir + "/" + path
+			}
+
+			isDir := path[len(path)-1] == '/'
+			want := repo.Match(name)
+
+			if got := gitignore.IsIgnored(file, strings.TrimSuffix(path, "/"), isDir); got != want {
+				t.Errorf("MatcherForDir(%q).Match(%q) = %v, Match(%q) = %v", dir, path, got, name, want)
+			}
+		}
+	}
+
+	lib, err := repo.MatcherForDir("lib/")
+	if err != nil {
+		t.Fatalf("MatcherForDir() error = %v", err)
+	}
+
+	if lib.Match("debug.log") || !lib.Match(".git") {
+		t.Error("MatcherForDir() applies the rules of the repository inside a submodule")
+	}
+
+	if _, err = repo.MatcherForDir("../outside"); !errors.Is(err, gitignore.ErrOutsideBase) {
+		t.Errorf("MatcherForDir() error = %v, want %v", err, gitignore.ErrOutsideBase)
+	}
+}
@@ -0,0 +1,50 @@
+package gitignore
+
+// Result describes the decision of a File for a path.
+type Result struct {
+	// Pattern describes the rule that decided whether the path is ignored.
+	Pattern PatternInfo
+
+	// Ignored reports whether the path is ignored, which is when the deciding
+	// rule is not negated.
+	Ignored bool
+}
+
+// MatchResult is like Match, but also returns the rule deciding whether path
+// is ignored, so tools can show users why a path was skipped. It returns false
+// if no rule matches path, in which case it is not ignored.
+func (f *File) MatchResult(path string) (Result, bool) {
+	pat := f.deciding(path)
+	if pat == nil {
+		return Result{}, false
+	}
+
+	return Result{
+		Pattern: f.info(pat),
+		Ignored: !pat.Negate,
+	}, true
+}
+
+// MatchAll returns every rule of f matching path, in file order, including
+// negated ones, for linters and auditing tools that need the complete chain of
+// rules rather than the final decision. The rule set with WithGitDirIgnored,
+// which takes precedence over every other rule, comes last. It returns an
+// empty slice if no rule matches path.
+func (f *File) MatchAll(path string) []PatternInfo {
+	var (
+		clean    = f.clean(path)
+		matching = make([]PatternInfo, 0)
+	)
+
+	for _, pat := range f.patterns {
+		if pat.Match(clean) {
+			matching = append(matching, f.info(pat))
+		}
+	}
+
+	if f.gitDir != nil && f.gitDir.Match(clean) {
+		matching = append(matching, f.info(f.gitDir))
+	}
+
+	return matching
+}
@@ -0,0 +1,140 @@
+package gitignore_test
+
+import (
+	"slices"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestFile_MatchResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		givePath  string
+		wantOK    bool
+		wantRaw   string
+		wantLine  int
+		wantMatch bool
+	}{
+		{
+			name:      "Single rule",
+			givePath:  "debug.log",
+			wantOK:    true,
+			wantRaw:   "*.log",
+			wantLine:  2,
+			wantMatch: true,
+		},
+		{
+			name:      "Negation wins",
+			givePath:  "keep.log",
+			wantOK:    true,
+			wantRaw:   "!keep.log",
+			wantLine:  3,
+			wantMatch: false,
+		},
+		{
+			name:      "Last matching rule",
+			givePath:  "tmp/debug.log",
+			wantOK:    true,
+			wantRaw:   "tmp/",
+			wantLine:  5,
+			wantMatch: true,
+		},
+		{
+			name:     "No matching rule",
+			givePath: "main.go",
+			wantOK:   false,
+		},
+	}
+
+	file, err := gitignore.NewFromLines([]string{"# logs", "*.log", "!keep.log", "", "tmp/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := file.MatchResult(tt.givePath)
+			if ok != tt.wantOK {
+				t.Fatalf("MatchResult(%q) ok = %v, want %v", tt.givePath, ok, tt.wantOK)
+			}
+
+			if got.Pattern.Raw != tt.wantRaw || got.Pattern.Line != tt.wantLine {
+				t.Errorf("MatchResult(%q) pattern = %d: %q, want %d: %q",
+					tt.givePath, got.Pattern.Line, got.Pattern.Raw, tt.wantLine, tt.wantRaw)
+			}
+
+			if got.Ignored != tt.wantMatch {
+				t.Errorf("MatchResult(%q) ignored = %v, want %v", tt.givePath, got.Ignored, tt.wantMatch)
+			}
+
+			if got.Ignored != file.Match(tt.givePath) {
+				t.Errorf("MatchResult(%q) ignored = %v, disagrees with Match", tt.givePath, got.Ignored)
+			}
+
+			if got.Pattern.Negate == got.Ignored && ok {
+				t.Errorf("MatchResult(%q) negate = %v, ignored = %v", tt.givePath, got.Pattern.Negate, got.Ignored)
+			}
+		})
+	}
+}
+
+func TestFile_MatchAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		givePath  string
+		wantLines []int
+	}{
+		{
+			name:      "Single rule",
+			givePath:  "debug.log",
+			wantLines: []int{1},
+		},
+		{
+			name:      "Rule and negation",
+			givePath:  "keep.log",
+			wantLines: []int{1, 2},
+		},
+		{
+			name:      "Rules after negation",
+			givePath:  "logs/keep.log",
+			wantLines: []int{1, 2, 3},
+		},
+		{
+			name:      "No matching rule",
+			givePath:  "main.go",
+			wantLines: []int{},
+		},
+	}
+
+	file, err := gitignore.NewFromLines([]string{"*.log", "!keep.log", "logs/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := file.MatchAll(tt.givePath)
+			if got == nil {
+				t.Fatalf("MatchAll(%q) = nil, want empty slice", tt.givePath)
+			}
+
+			lines := make([]int, 0, len(got))
+			for _, info := range got {
+				lines = append(lines, info.Line)
+			}
+
+			if !slices.Equal(lines, tt.wantLines) {
+				t.Errorf("MatchAll(%q) lines = %v, want %v", tt.givePath, lines, tt.wantLines)
+			}
+		})
+	}
+}
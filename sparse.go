@@ -0,0 +1,267 @@
+package gitignore
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrNotSparse is returned when reading the sparse checkout of a repository
+// where core.sparseCheckout is not enabled.
+const ErrNotSparse xerrors.Error = "sparse checkout is not enabled"
+
+// sparseCheckoutFile is the path of the file holding the patterns of a sparse
+// checkout, relative to the git directory.
+const sparseCheckoutFile = "info/sparse-checkout"
+
+// SparseCheckout selects the paths present in the working tree of a sparse
+// checkout, from the patterns of its $GIT_DIR/info/sparse-checkout file.
+//
+// In non-cone mode, the patterns use the syntax of .gitignore files, sharing
+// their engine, and a matching pattern includes a path rather than ignoring it.
+// Like git, the deciding pattern is looked for on the path first, then on its
+// parent directories, from the deepest.
+//
+// In cone mode, the patterns only select directories: "/dir/" includes dir
+// recursively, unless followed by "!/dir/*/", in which case only the files
+// directly inside dir are. Files at the root, and the parents of included
+// directories, are always included. Like git, the patterns fall back to
+// non-cone mode if any of them does not have one of these forms.
+//
+// A SparseCheckout is safe for concurrent use.
+type SparseCheckout struct {
+	// rules holds the patterns, used in non-cone mode.
+	rules *File
+
+	// recursive holds the directories included recursively in cone mode.
+	recursive map[string]struct{}
+
+	// parents holds the directories whose files are included in cone mode,
+	// along with the parents of every included directory.
+	parents map[string]struct{}
+
+	cone bool
+}
+
+// NewSparseCheckout reads the sparse-checkout file at path, in cone mode if
+// cone is true, as set by core.sparseCheckoutCone.
+func NewSparseCheckout(path string, cone bool) (*SparseCheckout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return parseSparseCheckout(path, data, cone)
+}
+
+// ParseSparseCheckout is like NewSparseCheckout, but reads the patterns from
+// r.
+func ParseSparseCheckout(r io.Reader, cone bool) (*SparseCheckout, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return parseSparseCheckout("", data, cone)
+}
+
+// parseSparseCheckout parses the patterns in data, attributing them to source.
+func parseSparseCheckout(source string, data []byte, cone bool) (*SparseCheckout, error) {
+	rules, err := parse(source, bytes.NewReader(data), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SparseCheckout{
+		rules: rules,
+	}
+
+	if cone {
+		s.cone = s.parseCone(data)
+	}
+
+	return s, nil
+}
+
+// parseCone records the directories selected by the cone mode patterns in
+// data, and reports whether every pattern has one of the forms of cone mode.
+func (s *SparseCheckout) parseCone(data []byte) bool {
+	s.recursive = make(map[string]struct{})
+	s.parents = make(map[string]struct{})
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || line[0] == '#' || line == "/*" || line == "!/*/" {
+			continue
+		}
+
+		rule, negated := strings.CutPrefix(line, "!")
+
+		// Only "!/dir/*/" may be negated, excluding the subdirectories of a
+		// directory included before.
+		suffix := "/"
+		if negated {
+			suffix = "/*/"
+		}
+
+		rule, ok := strings.CutSuffix(rule, suffix)
+		if !ok || !strings.HasPrefix(rule, "/") {
+			return false
+		}
+
+		dir, ok := unescapeConePath(rule[1:])
+		if !ok {
+			return false
+		}
+
+		if negated {
+			if _, listed := s.recursive[dir]; !listed {
+				return false
+			}
+
+			delete(s.recursive, dir)
+			s.parents[dir] = struct{}{}
+
+			continue
+		}
+
+		s.recursive[dir] = struct{}{}
+
+		for d := path.Dir(dir); d != "."; d = path.Dir(d) {
+			s.parents[d] = struct{}{}
+		}
+	}
+
+	return scanner.Err() == nil
+}
+
+// unescapeConePath returns the directory path of a cone mode pattern with its
+// escaped characters unescaped, and whether it is valid, which is when it is
+// not empty and has no wildcard.
+func unescapeConePath(dir string) (string, bool) {
+	if dir == "" || path.Clean(dir) != dir {
+		return "", false
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(dir); i++ {
+		switch c := dir[i]; c {
+		case '\\':
+			if i == len(dir)-1 {
+				return "", false
+			}
+
+			i++
+			b.WriteByte(dir[i])
+		case '*', '?', '[':
+			return "", false
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), true
+}
+
+// Cone reports whether s uses the special semantics of cone mode, which is the
+// case if it was requested and every pattern has one of its forms.
+func (s *SparseCheckout) Cone() bool {
+	return s.cone
+}
+
+// Includes reports whether path is present in the sparse checkout. The path is
+// slash-separated and relative to the root of the working tree, with a
+// trailing slash for directories. A directory is present if it is included, or
+// if it holds included paths in cone mode.
+func (s *SparseCheckout) Includes(path string) bool {
+	p := filepath.ToSlash(path)
+	name := strings.TrimSuffix(p, "/")
+
+	if s.cone {
+		return s.includesCone(name, name != p)
+	}
+
+	for {
+		if included, matched := s.rules.decide(p); matched {
+			return included
+		}
+
+		i := strings.LastIndexByte(name, '/')
+		if i < 0 {
+			return false
+		}
+
+		name = name[:i]
+		p = name + "/"
+	}
+}
+
+// includesCone is Includes in cone mode, for name without its trailing slash.
+func (s *SparseCheckout) includesCone(name string, isDir bool) bool {
+	dir := path.Dir(name)
+
+	if isDir {
+		if _, ok := s.parents[name]; ok {
+			return true
+		}
+
+		dir = name
+	} else if _, ok := s.parents[dir]; ok || dir == "." {
+		return true
+	}
+
+	for ; dir != "."; dir = path.Dir(dir) {
+		if _, ok := s.recursive[dir]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match reports whether path is outside of the sparse checkout, implementing
+// [Matcher], so it can be combined with ignore rules, such as with Any, to skip
+// the paths that are either ignored or not checked out.
+func (s *SparseCheckout) Match(path string) bool {
+	return !s.Includes(path)
+}
+
+// SparseCheckout returns the sparse checkout of the working tree of r, read
+// from $GIT_DIR/info/sparse-checkout in the mode set by core.sparseCheckoutCone.
+// It returns ErrNotSparse if core.sparseCheckout is not enabled, and an empty
+// SparseCheckout, including nothing, if the file does not exist, like git.
+func (r *Repository) SparseCheckout() (*SparseCheckout, error) {
+	sparse, err := r.config.Bool("core.sparseCheckout")
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if !sparse {
+		return nil, ErrNotSparse
+	}
+
+	cone, err := r.config.Bool("core.sparseCheckoutCone")
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	s, err := NewSparseCheckout(filepath.Join(r.gitDir, filepath.FromSlash(sparseCheckoutFile)), cone)
+	if errors.Is(err, fs.ErrNotExist) {
+		return parseSparseCheckout("", nil, cone)
+	}
+
+	return s, err
+}
@@ -0,0 +1,145 @@
+package gitignore_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+// sparseTree lists the files of the working tree the sparse checkout tests
+// select from.
+//
+//nolint:gochecknoglobals // Read-only test table.
+var sparseTree = []string{"a.txt", "A/x", "A/B/y", "A/B/C/z", "A/D/w", "E/v", "E/F/u", "G/t"}
+
+func TestSparseCheckout(t *testing.T) {
+	t.Parallel()
+
+	// The expected files are the ones checked out by git sparse-checkout
+	// set with the same patterns.
+	tests := []struct {
+		name     string
+		give     string
+		giveCone bool
+		wantCone bool
+		want     []string
+		wantDirs map[string]bool
+	}{
+		{
+			name:     "Cone mode",
+			give:     "/*\n!/*/\n/A/\n!/A/*/\n/A/B/\n/E/\n",
+			giveCone: true,
+			wantCone: true,
+			want:     []string{"a.txt", "A/x", "A/B/y", "A/B/C/z", "E/v", "E/F/u"},
+			wantDirs: map[string]bool{"A/": true, "A/B/": true, "A/B/C/": true, "A/D/": false, "E/F/": true, "G/": false},
+		},
+		{
+			name:     "Non-cone mode",
+			give:     "/*.txt\nA/\n!A/D/\nv\n",
+			want:     []string{"a.txt", "A/x", "A/B/y", "A/B/C/z", "E/v"},
+			wantDirs: map[string]bool{"A/": true, "A/D/": false, "G/": false},
+		},
+		{
+			name:     "Cone mode falls back on other patterns",
+			give:     "/*\n!/*/\n/A/\n*.txt\n",
+			giveCone: true,
+			wantCone: false,
+			want:     []string{"a.txt", "A/x", "A/B/y", "A/B/C/z", "A/D/w"},
+		},
+		{
+			name:     "Cone mode requires the directory before its negation",
+			give:     "/*\n!/*/\n!/A/*/\n",
+			giveCone: true,
+			wantCone: false,
+			want:     []string{"a.txt"},
+		},
+		{
+			name:     "Escaped cone directory",
+			give:     "/*\n!/*/\n/G\\*/\n",
+			giveCone: true,
+			wantCone: true,
+			want:     []string{"a.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := gitignore.ParseSparseCheckout(strings.NewReader(tt.give), tt.giveCone)
+			if err != nil {
+				t.Fatalf("ParseSparseCheckout() error = %v", err)
+			}
+
+			if s.Cone() != tt.wantCone {
+				t.Errorf("Cone() = %v, want %v", s.Cone(), tt.wantCone)
+			}
+
+			var got []string
+
+			for _, name := range sparseTree {
+				if s.Includes(name) {
+					got = append(got, name)
+				}
+
+				if s.Match(name) == s.Includes(name) {
+					t.Errorf("Match(%q) = Includes(%q)", name, name)
+				}
+			}
+
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Includes() selects %q, want %q", got, tt.want)
+			}
+
+			for dir, want := range tt.wantDirs {
+				if got := s.Includes(dir); got != want {
+					t.Errorf("Includes(%q) = %v, want %v", dir, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRepository_SparseCheckout(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":               "[core]\n\tsparseCheckout = true\n\tsparseCheckoutCone = true\n",
+		".git/info/sparse-checkout": "/*\n!/*/\n/A/\n",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	s, err := repo.SparseCheckout()
+	if err != nil {
+		t.Fatalf("SparseCheckout() error = %v", err)
+	}
+
+	if !s.Cone() || !s.Includes("A/B/y") || s.Includes("E/v") {
+		t.Error("SparseCheckout() does not honor the patterns of the repository")
+	}
+
+	other := t.TempDir()
+	writeTree(t, other, map[string]string{".git/config": ""})
+
+	repo, err = gitignore.OpenRepository(other, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	if _, err = repo.SparseCheckout(); !errors.Is(err, gitignore.ErrNotSparse) {
+		t.Errorf("SparseCheckout() error = %v, want %v", err, gitignore.ErrNotSparse)
+	}
+
+	if _, err = gitignore.NewSparseCheckout(filepath.Join(other, "missing"), false); err == nil {
+		t.Error("NewSparseCheckout() error = nil, want an error")
+	}
+}
@@ -0,0 +1,115 @@
+package gitignore
+
+import (
+	"io/fs"
+	"time"
+)
+
+// Stats describes the composition of the rules of a File.
+type Stats struct {
+	// Rules is the number of rules, as returned by Len.
+	Rules int
+
+	// Negations is the number of negated rules, re-including paths.
+	Negations int
+
+	// Anchored is the number of rules only matching relative to the directory
+	// of the .gitignore file.
+	Anchored int
+
+	// DirOnly is the number of rules only matching directories.
+	DirOnly int
+
+	// Comments is the number of comment lines skipped while parsing.
+	Comments int
+
+	// Blanks is the number of blank lines skipped while parsing.
+	Blanks int
+}
+
+// Stats returns statistics about the rules of f, for dashboards or to decide
+// which matching strategy suits a rule set. The comment and blank lines of
+// every file merged into f, or added with AddLines, are counted, while lines
+// that could not be parsed are not counted at all.
+func (f *File) Stats() Stats {
+	stats := Stats{
+		Rules:    len(f.patterns),
+		Comments: f.skipped.Comments,
+		Blanks:   f.skipped.Blanks,
+	}
+
+	for _, pat := range f.patterns {
+		if pat.Negate {
+			stats.Negations++
+		}
+
+		if pat.Anchored {
+			stats.Anchored++
+		}
+
+		if pat.DirOnly {
+			stats.DirOnly++
+		}
+	}
+
+	return stats
+}
+
+// WalkStats summarizes a walk, for tools reporting what they skipped, such as
+// backup tools. It is filled by walkers given WithStats.
+type WalkStats struct {
+	// Files is the number of files visited, which are the files that are not
+	// ignored.
+	Files int
+
+	// Ignored is the number of ignored files.
+	Ignored int
+
+	// Pruned is the number of ignored directories, which are not read.
+	Pruned int
+
+	// BytesSkipped is the total size of the ignored files. The files inside
+	// pruned directories are not counted, since they are never read.
+	BytesSkipped int64
+
+	// Elapsed is the duration of the walk.
+	Elapsed time.Duration
+}
+
+// startStats resets the statistics of the walk, if requested, and returns a
+// function recording its duration once done.
+func (o *walkOptions) startStats() func() {
+	if o.stats == nil {
+		return func() {}
+	}
+
+	*o.stats = WalkStats{}
+	start := time.Now()
+
+	return func() {
+		o.stats.Elapsed = time.Since(start)
+	}
+}
+
+// count records in the statistics of the walk, if requested, that the entry d
+// was visited, or ignored if ignored is true.
+func (o *walkOptions) count(d fs.DirEntry, ignored bool) {
+	if o.stats == nil {
+		return
+	}
+
+	switch {
+	case d.IsDir():
+		if ignored {
+			o.stats.Pruned++
+		}
+	case ignored:
+		o.stats.Ignored++
+
+		if info, err := d.Info(); err == nil {
+			o.stats.BytesSkipped += info.Size()
+		}
+	default:
+		o.stats.Files++
+	}
+}
@@ -0,0 +1,74 @@
+package gitignore_test
+
+import (
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestFile_Stats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveLines []string
+		want      gitignore.Stats
+	}{
+		{
+			name:      "Empty",
+			giveLines: []string{},
+			want:      gitignore.Stats{},
+		},
+		{
+			name:      "Comments and blanks",
+			giveLines: []string{"# Build.", "", "   ", "# Logs.", `\#not-a-comment`},
+			want:      gitignore.Stats{Rules: 1, Comments: 2, Blanks: 2},
+		},
+		{
+			name: "Mixed rules",
+			giveLines: []string{
+				"# Output.",
+				"/build/",
+				"*.log",
+				"!keep.log",
+				"",
+				"node_modules/",
+				"!/docs/",
+			},
+			want: gitignore.Stats{Rules: 5, Negations: 2, Anchored: 2, DirOnly: 3, Comments: 1, Blanks: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := gitignore.NewFromLines(tt.giveLines)
+			if err != nil {
+				t.Fatalf("NewFromLines(%q) error = %v", tt.giveLines, err)
+			}
+
+			if got := file.Stats(); got != tt.want {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFile_Stats_Merge(t *testing.T) {
+	t.Parallel()
+
+	global := newTestMatcher(t, "# Global.", "*.swp")
+	repo := newTestMatcher(t, "# Repo.", "", "*.log", "!keep.log")
+
+	file := gitignore.Merge(global, nil, repo)
+
+	if err := file.AddLines("", "*.tmp"); err != nil {
+		t.Fatalf("AddLines() error = %v", err)
+	}
+
+	want := gitignore.Stats{Rules: 4, Negations: 1, Comments: 2, Blanks: 2}
+	if got := file.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,208 @@
+package gitignore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// gitDir is the name of the directory git keeps its data in. It is never
+// reported as untracked.
+const gitDir = ".git"
+
+// gitignoreFile is the name of the per-directory ignore files.
+const gitignoreFile = ".gitignore"
+
+// Untracked returns the paths in fsys that are neither tracked nor ignored,
+// which is the list of untracked files git status reports.
+//
+// The tracked argument lists the slash-separated paths of tracked files,
+// relative to the root of fsys. Ignored paths are decided by m, which may be
+// nil, and by the .gitignore files found in the directories of fsys, with rules
+// from deeper directories taking precedence over the ones above them and m.
+//
+// Like git status, directories that contain no tracked files are collapsed into
+// a single entry with a trailing slash instead of listing every file inside
+// them, and directories without any untracked file are not reported at all.
+//
+// Directories and .gitignore files that cannot be read are handled according
+// to the error policy set by opts, failing on the first one by default.
+func Untracked(fsys fs.FS, tracked []string, m Matcher, opts ...WalkOption) ([]string, error) {
+	s := &scanner{
+		fsys:        fsys,
+		matcher:     m,
+		opts:        newWalkOptions(opts),
+		tracked:     make(map[string]struct{}, len(tracked)),
+		trackedDirs: make(map[string]struct{}),
+		untracked:   make([]string, 0),
+	}
+
+	for _, name := range tracked {
+		name = path.Clean(name)
+
+		s.tracked[name] = struct{}{}
+
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			s.trackedDirs[dir] = struct{}{}
+		}
+	}
+
+	if err := s.scan(".", nil); err != nil {
+		return nil, err
+	}
+
+	return s.untracked, s.opts.err()
+}
+
+// scopedRules holds the rules of a .gitignore file found in dir.
+type scopedRules struct {
+	file *File
+	dir  string
+}
+
+// scanner holds the state of an Untracked call.
+type scanner struct {
+	fsys        fs.FS
+	matcher     Matcher
+	opts        *walkOptions
+	tracked     map[string]struct{}
+	trackedDirs map[string]struct{}
+	untracked   []string
+}
+
+// scan records the untracked paths found inside dir, descending only into
+// directories that contain tracked files.
+func (s *scanner) scan(dir string, rules []scopedRules) error {
+	entries, rules, err := s.readDir(dir, rules)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+
+		if entry.Name() == gitDir || s.ignored(name, entry.IsDir(), rules) {
+			continue
+		}
+
+		if !entry.IsDir() {
+			if _, ok := s.tracked[name]; !ok {
+				s.untracked = append(s.untracked, name)
+			}
+
+			continue
+		}
+
+		if _, ok := s.trackedDirs[name]; ok {
+			if err = s.scan(name, rules); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var found bool
+
+		found, err = s.hasUntracked(name, rules)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			s.untracked = append(s.untracked, name+"/")
+		}
+	}
+
+	return nil
+}
+
+// hasUntracked reports whether dir, which contains no tracked files, contains
+// at least one file that is not ignored.
+func (s *scanner) hasUntracked(dir string, rules []scopedRules) (bool, error) {
+	entries, rules, err := s.readDir(dir, rules)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+
+		if entry.Name() == gitDir || s.ignored(name, entry.IsDir(), rules) {
+			continue
+		}
+
+		if !entry.IsDir() {
+			return true, nil
+		}
+
+		var found bool
+
+		found, err = s.hasUntracked(name, rules)
+		if err != nil || found {
+			return found, err
+		}
+	}
+
+	return false, nil
+}
+
+// readDir returns the entries of dir and the rules that apply to them, which
+// are the given rules plus the ones in the .gitignore file of dir, if any. When
+// dir cannot be read and the error policy skips it, no entries are returned.
+func (s *scanner) readDir(dir string, rules []scopedRules) ([]fs.DirEntry, []scopedRules, error) {
+	if err := s.opts.canceled(); err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := fs.ReadDir(s.fsys, dir)
+	if err != nil {
+		return nil, nil, s.opts.handle(dir, fmt.Errorf("%w", err))
+	}
+
+	s.opts.sort(entries)
+
+	name := path.Join(dir, gitignoreFile)
+
+	data, err := fs.ReadFile(s.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, rules, nil
+	}
+
+	if err != nil {
+		return nil, nil, s.opts.handle(name, fmt.Errorf("%w", err))
+	}
+
+	file, err := parse(name, bytes.NewReader(data), nil)
+	if err != nil {
+		return nil, nil, s.opts.handle(name, err)
+	}
+
+	scoped := make([]scopedRules, len(rules), len(rules)+1)
+	copy(scoped, rules)
+
+	return entries, append(scoped, scopedRules{file: file, dir: dir}), nil
+}
+
+// ignored reports whether name is ignored by the given rules or, if none of
+// them has an opinion on it, by the scanner's matcher.
+func (s *scanner) ignored(name string, isDir bool, rules []scopedRules) bool {
+	if isDir {
+		name += "/"
+	}
+
+	for i := len(rules) - 1; i >= 0; i-- {
+		rel := name
+		if rules[i].dir != "." {
+			rel = strings.TrimPrefix(name, rules[i].dir+"/")
+		}
+
+		if ignored, matched := rules[i].file.decide(rel); matched {
+			return ignored
+		}
+	}
+
+	return s.matcher != nil && s.matcher.Match(name)
+}
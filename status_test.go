@@ -0,0 +1,281 @@
+package gitignore_test
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestUntracked(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		".git/HEAD":                {Data: []byte("ref: refs/heads/trunk")},
+		".gitignore":               {Data: []byte("*.log\nbin/\n")},
+		"go.mod":                   {Data: []byte("module example.com/app")},
+		"main.go":                  {Data: []byte("package main")},
+		"notes.txt":                {Data: []byte("notes")},
+		"debug.log":                {Data: []byte("debug")},
+		"bin/app":                  {Data: []byte("binary")},
+		"internal/app/app.go":      {Data: []byte("package app")},
+		"internal/app/app_test.go": {Data: []byte("package app")},
+		"internal/app/trace.log":   {Data: []byte("trace")},
+		"internal/app/.gitignore":  {Data: []byte("*.tmp\n!keep.log\n")},
+		"internal/app/cache.tmp":   {Data: []byte("cache")},
+		"internal/app/keep.log":    {Data: []byte("keep")},
+		"scratch/a.go":             {Data: []byte("package scratch")},
+		"scratch/b/c.go":           {Data: []byte("package b")},
+		"logs/today.log":           {Data: []byte("today")},
+		"empty/.gitkeep.log":       {Data: []byte("")},
+	}
+
+	tests := []struct {
+		name        string
+		giveTracked []string
+		giveRules   []string
+		want        []string
+	}{
+		{
+			name: "Tracked files with untracked siblings and directories",
+			giveTracked: []string{
+				".gitignore",
+				"go.mod",
+				"main.go",
+				"internal/app/app.go",
+			},
+			want: []string{
+				"internal/app/.gitignore",
+				"internal/app/app_test.go",
+				"internal/app/keep.log",
+				"notes.txt",
+				"scratch/",
+			},
+		},
+		{
+			name: "Extra matcher rules",
+			giveTracked: []string{
+				".gitignore",
+				"go.mod",
+				"main.go",
+				"internal/app/.gitignore",
+				"internal/app/app.go",
+			},
+			giveRules: []string{
+				"notes.txt",
+				"scratch/b/",
+				"*_test.go",
+			},
+			want: []string{
+				"internal/app/keep.log",
+				"scratch/",
+			},
+		},
+		{
+			name:        "Nothing tracked",
+			giveTracked: nil,
+			want: []string{
+				".gitignore",
+				"go.mod",
+				"internal/",
+				"main.go",
+				"notes.txt",
+				"scratch/",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var matcher gitignore.Matcher
+
+			if tt.giveRules != nil {
+				file, err := gitignore.NewFromLines(tt.giveRules)
+				if err != nil {
+					t.Fatalf("failed to create matcher: %v", err)
+				}
+
+				matcher = file
+			}
+
+			got, err := gitignore.Untracked(fsys, tt.giveTracked, matcher)
+			if err != nil {
+				t.Fatalf("Untracked() unexpected error: %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Untracked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// brokenFS is an [fs.FS] failing to read the directories and files in broken.
+type brokenFS struct {
+	fs.FS
+
+	broken map[string]struct{}
+}
+
+func (b brokenFS) Open(name string) (fs.File, error) {
+	if _, ok := b.broken[name]; ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+
+	return b.FS.Open(name)
+}
+
+func TestUntracked_ErrorPolicy(t *testing.T) {
+	t.Parallel()
+
+	fsys := brokenFS{
+		FS: fstest.MapFS{
+			"main.go":              {},
+			"a/secret/key.pem":     {},
+			"a/visible.txt":        {},
+			"b/.gitignore":         {},
+			"b/hidden.txt":         {},
+			"c/new.txt":            {},
+			"c/deeper/private.txt": {},
+		},
+		broken: map[string]struct{}{
+			"a/secret":     {},
+			"b/.gitignore": {},
+		},
+	}
+
+	tracked := []string{"main.go", "a/visible.txt", "b/other.txt"}
+
+	tests := []struct {
+		name          string
+		giveOpts      []gitignore.WalkOption
+		wantUntracked []string
+		wantErrPaths  []string
+		wantFail      bool
+	}{
+		{
+			name:     "Fail fast by default",
+			wantFail: true,
+		},
+		{
+			name:     "Explicit fail fast",
+			giveOpts: []gitignore.WalkOption{gitignore.WithSkipErrors(), gitignore.WithFailFast()},
+			wantFail: true,
+		},
+		{
+			name:          "Skip and collect",
+			giveOpts:      []gitignore.WalkOption{gitignore.WithSkipErrors()},
+			wantUntracked: []string{"c/"},
+			wantErrPaths:  []string{"a/secret", "b/.gitignore"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			untracked, err := gitignore.Untracked(fsys, tracked, nil, tt.giveOpts...)
+			if tt.wantFail {
+				if !errors.Is(err, fs.ErrPermission) || untracked != nil {
+					t.Fatalf("Untracked() = %q, %v, want nil, %v", untracked, err, fs.ErrPermission)
+				}
+
+				return
+			}
+
+			if !slices.Equal(untracked, tt.wantUntracked) {
+				t.Errorf("Untracked() = %q, want %q", untracked, tt.wantUntracked)
+			}
+
+			joined, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint // Inspecting the joined errors.
+			if !ok {
+				t.Fatalf("Untracked() error = %v, want joined errors", err)
+			}
+
+			var paths []string
+
+			for _, e := range joined.Unwrap() {
+				var pathErr *fs.PathError
+				if !errors.As(e, &pathErr) {
+					t.Fatalf("error %v is not a *fs.PathError", e)
+				}
+
+				paths = append(paths, pathErr.Path)
+			}
+
+			if !slices.Equal(paths, tt.wantErrPaths) {
+				t.Errorf("error paths = %q, want %q", paths, tt.wantErrPaths)
+			}
+		})
+	}
+}
+
+func TestUntracked_ErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	fsys := brokenFS{
+		FS: fstest.MapFS{
+			"a/secret/key.pem": {},
+			"a/visible.txt":    {},
+			"b/new.txt":        {},
+		},
+		broken: map[string]struct{}{"a/secret": {}},
+	}
+
+	var seen []string
+
+	untracked, err := gitignore.Untracked(fsys, []string{"a/visible.txt"}, nil, gitignore.WithErrorHandler(func(path string, err error) error {
+		seen = append(seen, path)
+
+		if !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("handler error = %v, want %v", err, fs.ErrPermission)
+		}
+
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Untracked() error = %v", err)
+	}
+
+	if want := []string{"b/"}; !slices.Equal(untracked, want) {
+		t.Errorf("Untracked() = %q, want %q", untracked, want)
+	}
+
+	if want := []string{"a/secret"}; !slices.Equal(seen, want) {
+		t.Errorf("handler paths = %q, want %q", seen, want)
+	}
+
+	errStop := errors.New("stop")
+
+	_, err = gitignore.Untracked(fsys, []string{"a/visible.txt"}, nil, gitignore.WithErrorHandler(func(_ string, _ error) error {
+		return errStop
+	}))
+	if !errors.Is(err, errStop) {
+		t.Errorf("Untracked() error = %v, want %v", err, errStop)
+	}
+}
+
+func TestUntracked_ParseError(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"a/.gitignore": {Data: []byte("*.log\n[invalid\n")},
+		"a/b.txt":      {Data: []byte("b")},
+	}
+
+	_, err := gitignore.Untracked(fsys, nil, nil)
+
+	var parseErr *gitignore.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Untracked() error = %v, want *ParseError", err)
+	}
+
+	if parseErr.Source != "a/.gitignore" || parseErr.Line != 2 || parseErr.Pattern != "[invalid" {
+		t.Errorf("Untracked() error = %+v, want source %q, line 2, pattern %q", parseErr, "a/.gitignore", "[invalid")
+	}
+}
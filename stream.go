@@ -0,0 +1,75 @@
+package gitignore
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// ReadPathsFrom reads paths delimited by sep from r, such as '\n' for the
+// output of find or 0 for the output of find -print0 and git ls-files -z, and
+// calls fn with each path and whether m ignores it, in order, as they are
+// read. Reading stops at the first error returned by fn, which ReadPathsFrom
+// returns.
+//
+// Empty entries are skipped, a leading "./" is ignored when matching, and when
+// sep is '\n', a trailing carriage return is removed. Paths are otherwise
+// passed to fn as read.
+func ReadPathsFrom(r io.Reader, sep byte, m Matcher, fn func(path string, ignored bool) error) error {
+	scanner := bufio.NewScanner(r)
+
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, sep); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+
+		return 0, nil, nil
+	})
+
+	for scanner.Scan() {
+		path := scanner.Text()
+
+		if sep == '\n' {
+			path = strings.TrimSuffix(path, "\r")
+		}
+
+		if path == "" {
+			continue
+		}
+
+		if err := fn(path, m.Match(strings.TrimPrefix(path, "./"))); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// FilterSeq returns an iterator yielding the paths of seq that m does not
+// ignore, in order, so path producers compose with matchers without building
+// intermediate slices. Paths are matched as given, so directories must have a
+// trailing slash. [File.Filter] derives a File from a subset of rules instead.
+func FilterSeq(m Matcher, seq iter.Seq[string]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for p := range seq {
+			if m.Match(p) {
+				continue
+			}
+
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
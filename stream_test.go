@@ -0,0 +1,143 @@
+package gitignore_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestReadPathsFrom(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveInput   string
+		giveSep     byte
+		wantIgnored []string
+		wantKept    []string
+	}{
+		{
+			name:        "Newline-delimited",
+			giveInput:   "main.go\ndebug.log\nbuild/app\n",
+			giveSep:     '\n',
+			wantIgnored: []string{"debug.log", "build/app"},
+			wantKept:    []string{"main.go"},
+		},
+		{
+			name:        "NUL-delimited",
+			giveInput:   "main.go\x00debug.log\x00name with\nnewline.log\x00",
+			giveSep:     0,
+			wantIgnored: []string{"debug.log", "name with\nnewline.log"},
+			wantKept:    []string{"main.go"},
+		},
+		{
+			name:        "Output of find",
+			giveInput:   "./main.go\r\n./build/app\r\n\r\n./keep.log",
+			giveSep:     '\n',
+			wantIgnored: []string{"./build/app"},
+			wantKept:    []string{"./main.go", "./keep.log"},
+		},
+		{
+			name:      "Empty input",
+			giveInput: "",
+			giveSep:   0,
+		},
+	}
+
+	m, err := gitignore.NewFromLines([]string{"*.log", "!keep.log", "/build/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ignored, kept []string
+
+			err := gitignore.ReadPathsFrom(strings.NewReader(tt.giveInput), tt.giveSep, m, func(path string, isIgnored bool) error {
+				if isIgnored {
+					ignored = append(ignored, path)
+				} else {
+					kept = append(kept, path)
+				}
+
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("ReadPathsFrom() error = %v", err)
+			}
+
+			if !slices.Equal(ignored, tt.wantIgnored) {
+				t.Errorf("ignored = %q, want %q", ignored, tt.wantIgnored)
+			}
+
+			if !slices.Equal(kept, tt.wantKept) {
+				t.Errorf("kept = %q, want %q", kept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestReadPathsFrom_Error(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errStop = errors.New("stop")
+		seen    []string
+	)
+
+	m := gitignore.MatcherFunc(func(_ string) bool { return false })
+
+	err := gitignore.ReadPathsFrom(strings.NewReader("a\nb\nc\n"), '\n', m, func(path string, _ bool) error {
+		seen = append(seen, path)
+
+		if path == "b" {
+			return errStop
+		}
+
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("ReadPathsFrom() error = %v, want %v", err, errStop)
+	}
+
+	if want := []string{"a", "b"}; !slices.Equal(seen, want) {
+		t.Errorf("seen = %q, want %q", seen, want)
+	}
+}
+
+func TestFilterSeq(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMatcher(t, "*.log", "!keep.log", "build/")
+
+	paths := []string{"main.go", "debug.log", "keep.log", "build/", "build/app", "docs/index.md"}
+
+	got := slices.Collect(gitignore.FilterSeq(m, slices.Values(paths)))
+
+	if want := []string{"main.go", "keep.log", "docs/index.md"}; !slices.Equal(got, want) {
+		t.Errorf("FilterSeq() = %q, want %q", got, want)
+	}
+
+	var first []string
+
+	for p := range gitignore.FilterSeq(m, slices.Values(paths)) {
+		first = append(first, p)
+
+		if len(first) == 2 {
+			break
+		}
+	}
+
+	if want := []string{"main.go", "keep.log"}; !slices.Equal(first, want) {
+		t.Errorf("FilterSeq() with break = %q, want %q", first, want)
+	}
+
+	if got := slices.Collect(gitignore.FilterSeq(m, slices.Values([]string(nil)))); len(got) != 0 {
+		t.Errorf("FilterSeq() of empty sequence = %q, want none", got)
+	}
+}
@@ -0,0 +1,90 @@
+package gitignore_test
+
+import (
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/gitignore-go/gitignoretest"
+)
+
+func TestStress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rules = newTestMatcher(t, "*.log", "!keep.log", "build/", "/vendor")
+		local = newTestMatcher(t, "!debug.log", "*.tmp")
+		paths = []string{"a.log", "keep.log", "debug.log", "build/", "build/out", "vendor/", "src/vendor/", "x.tmp", "main.go"}
+	)
+
+	tests := []struct {
+		name    string
+		matcher gitignore.Matcher
+	}{
+		{name: "File", matcher: rules},
+		{name: "Chain", matcher: gitignore.Chain(rules, local)},
+		{name: "Any", matcher: gitignore.Any(rules, local)},
+		{name: "All", matcher: gitignore.All(rules, local)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gitignoretest.Stress(t, tt.matcher, paths)
+		})
+	}
+}
+
+func TestFilterFS_Stress(t *testing.T) {
+	t.Parallel()
+
+	fsys := gitignore.NewFilterFS(fstest.MapFS{
+		"main.go":         {Data: []byte("package main")},
+		"debug.log":       {Data: []byte("debug")},
+		"build/out":       {Data: []byte("binary")},
+		"src/lib.go":      {Data: []byte("package src")},
+		"src/vendor/a.go": {Data: []byte("package a")},
+	}, newTestMatcher(t, "*.log", "build/", "vendor/"))
+
+	var wg sync.WaitGroup
+
+	for range 16 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 50 {
+				var files []string
+
+				err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+					if err != nil {
+						return err
+					}
+
+					if !d.IsDir() {
+						files = append(files, path)
+					}
+
+					return nil
+				})
+				if err != nil {
+					t.Errorf("WalkDir() unexpected error: %v", err)
+
+					return
+				}
+
+				if len(files) != 2 {
+					t.Errorf("WalkDir() found %v, want main.go and src/lib.go", files)
+
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,46 @@
+package gitignore
+
+import (
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+const (
+	// ErrStrict is wrapped by the *ParseError returned for every line rejected
+	// with WithStrict, along with one of the errors below describing why.
+	ErrStrict xerrors.Error = "rule rejected in strict mode"
+
+	// ErrTrailingBackslash reports a rule ending with an unescaped backslash,
+	// which git considers invalid and never matches.
+	ErrTrailingBackslash = pattern.ErrTrailingBackslash
+
+	// ErrUselessEscape reports a backslash escaping a character with no special
+	// meaning, such as in "\a", which git silently drops.
+	ErrUselessEscape = pattern.ErrUselessEscape
+
+	// ErrDoubleStar reports two consecutive asterisks not forming a whole path
+	// component, such as in "a**b", which git treats as a single one.
+	ErrDoubleStar = pattern.ErrDoubleStar
+
+	// ErrNeverMatches reports a rule that can never match a path, such as "/"
+	// or a lone "!".
+	ErrNeverMatches = pattern.ErrNeverMatches
+
+	// ErrTrailingSpaces reports a line with unescaped trailing spaces, which git
+	// silently strips.
+	ErrTrailingSpaces = pattern.ErrTrailingSpaces
+)
+
+// WithStrict makes the constructors reject the rules git would silently ignore
+// or treat differently than they read, so policy tools can enforce clean
+// .gitignore files. Each rejected line is reported with a *ParseError wrapping
+// ErrStrict and the reason, such as ErrUselessEscape, and pointing at the
+// offending column.
+//
+// Along with WithPermissive, the rejected lines are skipped and reported rather
+// than failing.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
@@ -0,0 +1,68 @@
+package gitignore_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestWithStrict(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"*.log", `fo\a`, "a**b", "build/"}
+
+	if _, err := gitignore.NewFromLines(lines); err != nil {
+		t.Fatalf("NewFromLines(%q) error = %v, want nil without WithStrict", lines, err)
+	}
+
+	_, err := gitignore.NewFromLines(lines, gitignore.WithStrict())
+
+	var parseErr *gitignore.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("NewFromLines(%q) error = %v, want *ParseError", lines, err)
+	}
+
+	if !errors.Is(err, gitignore.ErrStrict) || !errors.Is(err, gitignore.ErrUselessEscape) {
+		t.Errorf("NewFromLines(%q) error = %v, want %v and %v", lines, err, gitignore.ErrStrict, gitignore.ErrUselessEscape)
+	}
+
+	if errors.Is(err, gitignore.ErrRegexCompile) {
+		t.Errorf("errors.Is(err, ErrRegexCompile) = true, want false")
+	}
+
+	if parseErr.Line != 2 || parseErr.Column != 3 || parseErr.Pattern != `fo\a` {
+		t.Errorf("ParseError = %+v, want line 2, column 3, pattern %q", parseErr, `fo\a`)
+	}
+}
+
+func TestWithStrict_Permissive(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"*.log", `fo\a`, "[invalid", "a**b", "/", "build/"}
+
+	file, err := gitignore.NewFromLines(lines, gitignore.WithStrict(), gitignore.WithPermissive())
+	if file == nil {
+		t.Fatalf("NewFromLines(%q) = nil file, error %v", lines, err)
+	}
+
+	errs := gitignore.ParseErrors(err)
+
+	want := []error{gitignore.ErrUselessEscape, gitignore.ErrRegexCompile, gitignore.ErrDoubleStar, gitignore.ErrNeverMatches}
+	if len(errs) != len(want) {
+		t.Fatalf("ParseErrors() = %v, want %d errors", errs, len(want))
+	}
+
+	for i, e := range errs {
+		if !errors.Is(e, want[i]) {
+			t.Errorf("ParseErrors()[%d] = %v, want %v", i, e, want[i])
+		}
+	}
+
+	if got := file.Patterns(); !slices.EqualFunc(got, []string{"*.log", "build/"}, func(info gitignore.PatternInfo, raw string) bool {
+		return info.Raw == raw
+	}) {
+		t.Errorf("Patterns() = %+v, want *.log and build/", got)
+	}
+}
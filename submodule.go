@@ -0,0 +1,156 @@
+package gitignore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/gitconfig"
+)
+
+// gitmodulesFile is the name of the file registering the submodules of a
+// repository.
+const gitmodulesFile = ".gitmodules"
+
+// WithSubmodulesIgnored makes Match report every submodule, and everything
+// inside it, as ignored, so walkers skip submodules as a whole.
+func WithSubmodulesIgnored() RepositoryOption {
+	return func(o *repositoryOptions) {
+		o.ignoreSubmodules = true
+	}
+}
+
+// Submodules returns the slash-separated paths of the submodules of r, relative
+// to the root of the working tree, in lexical order. Submodules are the
+// directories registered in the .gitmodules file, and the ones holding a nested
+// repository found so far, since directories are only checked for one as they
+// are matched.
+func (r *Repository) Submodules() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	paths := make([]string, 0, len(r.submodules))
+
+	for p := range r.submodules {
+		paths = append(paths, p)
+	}
+
+	slices.Sort(paths)
+
+	return paths
+}
+
+// submodule returns the path of the submodule holding name, or name itself if
+// it is a submodule, and whether there is one.
+func (r *Repository) submodule(name string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.submodules) == 0 {
+		return "", false
+	}
+
+	for i := range len(name) {
+		if name[i] == '/' {
+			if _, ok := r.submodules[name[:i]]; ok {
+				return name[:i], true
+			}
+		}
+	}
+
+	_, ok := r.submodules[name]
+
+	return name, ok
+}
+
+// checkSubmodule returns the decision for the submodule at dir, a directory
+// path with a trailing slash, which applies to everything inside it, and
+// whether there is one. Like git, a submodule is a single unit: the ones
+// registered in .gitmodules are tracked and never ignored, while nested
+// repositories that are not registered are ignored if their directory is.
+func (r *Repository) checkSubmodule(dir string) (Result, bool) {
+	if r.ignoreSubmodules {
+		return Result{Ignored: true}, true
+	}
+
+	r.mu.Lock()
+	registered := r.submodules[strings.TrimSuffix(dir, "/")]
+	r.mu.Unlock()
+
+	if registered {
+		return Result{}, false
+	}
+
+	return r.checkRules(dir)
+}
+
+// loadSubmodules reads the paths of the submodules registered in the
+// .gitmodules file of the working tree, if any.
+func (r *Repository) loadSubmodules() error {
+	data, err := fs.ReadFile(r.fsys, gitmodulesFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	config, err := gitconfig.Parse(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", gitmodulesFile, err)
+	}
+
+	for key, values := range config {
+		if !strings.HasPrefix(key, "submodule.") || !strings.HasSuffix(key, ".path") || len(values) == 0 {
+			continue
+		}
+
+		name := path.Clean(filepath.ToSlash(values[len(values)-1]))
+		if name == "." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			continue
+		}
+
+		r.submodules[name] = true
+	}
+
+	return nil
+}
+
+// probe records the directory name as a submodule if it holds a nested
+// repository, with a .git directory or file, checking it only once. The file
+// system is checked without holding r.mu, so other directories can be served
+// meanwhile.
+func (r *Repository) probe(name string) {
+	r.mu.Lock()
+
+	_, probed := r.probed[name]
+	_, known := r.submodules[name]
+
+	r.mu.Unlock()
+
+	if probed || known {
+		return
+	}
+
+	_, err := fs.Stat(r.fsys, path.Join(name, gitDir))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Another goroutine may have probed the directory meanwhile.
+	if _, ok := r.probed[name]; ok {
+		return
+	}
+
+	r.probed[name] = struct{}{}
+
+	if _, ok := r.submodules[name]; !ok && err == nil {
+		r.submodules[name] = false
+	}
+}
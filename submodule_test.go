@@ -0,0 +1,186 @@
+package gitignore_test
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestRepository_Submodules(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":             "",
+		".gitignore":              "*.log\nlib/\nvendor/\n",
+		".gitmodules":             "[submodule \"lib\"]\n\tpath = lib\n\turl = https://example.com/lib.git\n",
+		"lib/.git":                "gitdir: ../.git/modules/lib\n",
+		"lib/.gitignore":          "*.txt\n",
+		"nested/.git/config":      "",
+		"nested/.gitignore":       "*.txt\n",
+		"vendor/dep/.git/config":  "",
+		"vendor/dep/.gitignore":   "*.go\n",
+		"pkg/.gitignore":          "*.tmp\n",
+		"pkg/inner/.git/HEAD":     "",
+		"pkg/inner/data/file.tmp": "",
+	})
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		// Registered submodules are never ignored, whatever the rules say.
+		{givePath: "lib/", want: false},
+		{givePath: "lib/a.txt", want: false},
+		{givePath: "lib/a.log", want: false},
+
+		// The rules of nested repositories do not apply to the parent.
+		{givePath: "nested/", want: false},
+		{givePath: "nested/a.txt", want: false},
+		{givePath: "pkg/inner/", want: false},
+		{givePath: "pkg/inner/data/file.tmp", want: false},
+		{givePath: "pkg/file.tmp", want: true},
+
+		// Nested repositories inside ignored directories stay ignored.
+		{givePath: "vendor/dep/", want: true},
+		{givePath: "vendor/dep/main.go", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := repo.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+
+	// Nested repositories are found as they are matched, and never inside
+	// ignored directories.
+	want := []string{"lib", "nested", "pkg/inner"}
+	if got := repo.Submodules(); !slices.Equal(got, want) {
+		t.Errorf("Submodules() = %q, want %q", got, want)
+	}
+}
+
+func TestWithSubmodulesIgnored(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":        "",
+		".gitmodules":        "[submodule \"lib\"]\n\tpath = lib\n",
+		"lib/.git":           "gitdir: ../.git/modules/lib\n",
+		"nested/.git/config": "",
+	})
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithSubmodulesIgnored(),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		givePath string
+		want     bool
+	}{
+		{givePath: "lib/", want: true},
+		{givePath: "lib/main.go", want: true},
+		{givePath: "nested/", want: true},
+		{givePath: "nested/main.go", want: true},
+		{givePath: "main.go", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := repo.Match(tt.givePath); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.givePath, got, tt.want)
+		}
+	}
+}
+
+func TestRepository_ConcurrentProbe(t *testing.T) {
+	t.Parallel()
+
+	var (
+		probing = make(chan struct{})
+		release = make(chan struct{})
+		once    sync.Once
+	)
+
+	// Checking slow/.git blocks until released, which must not keep other
+	// directories from being served.
+	read := func(path string) ([]byte, error) {
+		switch path {
+		case "slow/.git":
+			once.Do(func() { close(probing) })
+			<-release
+
+			return []byte("gitdir: ../.git/modules/slow\n"), nil
+		case "fast/.gitignore":
+			return []byte("*.log\n"), nil
+		default:
+			return nil, fmt.Errorf("%s: %w", path, fs.ErrNotExist)
+		}
+	}
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{".git/config": ""})
+
+	repo, err := gitignore.OpenRepository(root,
+		gitignore.WithHomeDir(t.TempDir()),
+		gitignore.WithoutDefaultExcludes(),
+		gitignore.WithBlobReader(read),
+	)
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	slow := make(chan bool)
+
+	go func() {
+		slow <- repo.Match("slow/")
+	}()
+
+	<-probing
+
+	fast := make(chan bool)
+
+	go func() {
+		fast <- repo.Match("fast/a.log")
+	}()
+
+	select {
+	case got := <-fast:
+		if !got {
+			t.Errorf("Match(%q) = false, want true", "fast/a.log")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Match() blocked while another directory was probed")
+	}
+
+	close(release)
+
+	if <-slow {
+		t.Errorf("Match(%q) = true, want false", "slow/")
+	}
+
+	if got, want := repo.Submodules(), []string{"slow"}; !slices.Equal(got, want) {
+		t.Errorf("Submodules() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,408 @@
+package templates
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+const (
+	// ErrUnexpectedStatus is returned when a remote template source answers
+	// with an unexpected HTTP status code.
+	ErrUnexpectedStatus xerrors.Error = "unexpected HTTP status"
+
+	// ErrListUnsupported is returned when listing the templates of a remote
+	// source without an index.
+	ErrListUnsupported xerrors.Error = "listing templates is not supported by this source"
+
+	// ErrResponseTooLarge is returned when a remote template source answers
+	// with a body larger than a Client accepts.
+	ErrResponseTooLarge xerrors.Error = "response too large"
+)
+
+const (
+	// DefaultTimeout is the default timeout for requests made by a Client.
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultRef is the default git reference of the github/gitignore
+	// repository templates are fetched from.
+	DefaultRef = "main"
+
+	// maxTemplateSize is the maximum size of a template a Client accepts, to
+	// protect against misbehaving servers.
+	maxTemplateSize = 1 << 20
+)
+
+// ClientOption configures a Client.
+type ClientOption func(c *Client)
+
+// WithHTTPClient sets the HTTP client used to fetch templates. When set, the
+// timeout given to WithTimeout is ignored in favor of the client's own.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithTimeout sets the timeout for each request made by the client.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithCacheDir enables on-disk caching of fetched templates in dir. Cached
+// templates are revalidated with their ETag, and are used as a fallback when
+// the remote source cannot be reached.
+func WithCacheDir(dir string) ClientOption {
+	return func(c *Client) {
+		c.cacheDir = dir
+	}
+}
+
+// WithGitHub fetches templates from the github/gitignore repository at the
+// given git reference, such as a tag or commit hash. Pinning a reference keeps
+// the output stable while still being fresher than the embedded snapshot.
+func WithGitHub(ref string) ClientOption {
+	return func(c *Client) {
+		WithURLs(func(name string) []string {
+			base := "https://raw.githubusercontent.com/github/gitignore/" + url.PathEscape(ref) + "/"
+
+			return []string{
+				base + url.PathEscape(name) + extension,
+				base + globalDir + "/" + url.PathEscape(name) + extension,
+			}
+		})(c)
+
+		c.indexURL = "https://api.github.com/repos/github/gitignore/git/trees/" + url.PathEscape(ref) + "?recursive=1"
+		c.parseIndex = parseGitHubTree
+	}
+}
+
+// WithToptal fetches templates from the gitignore.io API hosted by Toptal.
+func WithToptal() ClientOption {
+	return func(c *Client) {
+		WithURLs(func(name string) []string {
+			return []string{
+				"https://www.toptal.com/developers/gitignore/api/" + url.PathEscape(strings.ToLower(name)),
+			}
+		})(c)
+
+		WithIndexURL("https://www.toptal.com/developers/gitignore/api/list?format=lines")(c)
+	}
+}
+
+// WithURLs sets the function that returns the URLs a template may be fetched
+// from, which are tried in order until one exists. It allows using private
+// mirrors of the template collection.
+//
+// Listing templates is unsupported until an index is set with WithIndexURL
+// after this option.
+func WithURLs(fn func(name string) []string) ClientOption {
+	return func(c *Client) {
+		c.urls = fn
+		c.indexURL = ""
+		c.parseIndex = nil
+	}
+}
+
+// WithIndexURL sets the URL of the index listing the available templates, one
+// name per line.
+func WithIndexURL(u string) ClientOption {
+	return func(c *Client) {
+		c.indexURL = u
+		c.parseIndex = parseLines
+	}
+}
+
+// Client fetches templates from a remote source, for users who want templates
+// fresher than the embedded snapshot. By default, templates are fetched from
+// the github/gitignore repository at [DefaultRef].
+type Client struct {
+	httpClient *http.Client
+	urls       func(name string) []string
+	parseIndex func(data []byte) ([]string, error)
+	indexURL   string
+	cacheDir   string
+	timeout    time.Duration
+}
+
+// NewClient returns a new Client configured with the given options.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		timeout: DefaultTimeout,
+	}
+
+	WithGitHub(DefaultRef)(c)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{
+			Timeout: c.timeout,
+		}
+	}
+
+	return c
+}
+
+// List implements TemplateSource. It returns [ErrListUnsupported] if the source
+// has no index.
+func (c *Client) List(ctx context.Context) ([]string, error) {
+	if c.indexURL == "" {
+		return nil, ErrListUnsupported
+	}
+
+	data, err := c.fetch(ctx, c.indexURL)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return nil, fmt.Errorf("%w: index not found at %s", ErrUnexpectedStatus, c.indexURL)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := c.parseIndex(data)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	return names, nil
+}
+
+// Get implements TemplateSource.
+func (c *Client) Get(ctx context.Context, name string) ([]string, error) {
+	for _, u := range c.urls(name) {
+		data, err := c.fetch(ctx, u)
+		if errors.Is(err, ErrTemplateNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+}
+
+// fetch returns the body of the given URL, using and updating the cache when
+// enabled.
+func (c *Client) fetch(ctx context.Context, u string) ([]byte, error) {
+	cached, etag := c.readCache(u)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if cached != nil && etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if cached != nil {
+			return cached, nil
+		}
+
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if cached != nil {
+			return cached, nil
+		}
+
+		return nil, fmt.Errorf("%w: %s for %s", ErrUnexpectedStatus, resp.Status, u)
+	case http.StatusNotFound:
+		return nil, ErrTemplateNotFound
+	default:
+		if cached != nil && resp.StatusCode >= http.StatusInternalServerError {
+			return cached, nil
+		}
+
+		return nil, fmt.Errorf("%w: %s for %s", ErrUnexpectedStatus, resp.Status, u)
+	}
+
+	// One more byte than allowed is read to tell a body of exactly the
+	// maximum size from a larger one, which is rejected rather than
+	// truncated.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if len(data) > maxTemplateSize {
+		return nil, fmt.Errorf("%w: more than %d bytes from %s", ErrResponseTooLarge, maxTemplateSize, u)
+	}
+
+	if err = c.writeCache(u, data, resp.Header.Get("ETag")); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// readCache returns the cached body and ETag for the given URL, or nil if it
+// is not cached.
+func (c *Client) readCache(u string) ([]byte, string) {
+	if c.cacheDir == "" {
+		return nil, ""
+	}
+
+	file := c.cachePath(u)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, ""
+	}
+
+	etag, err := os.ReadFile(file + ".etag")
+	if err != nil {
+		return data, ""
+	}
+
+	return data, string(etag)
+}
+
+// writeCache stores the body and ETag of the given URL in the cache, if
+// enabled.
+func (c *Client) writeCache(u string, data []byte, etag string) error {
+	if c.cacheDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.cacheDir, 0o700); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	file := c.cachePath(u)
+
+	if err := writeFileAtomic(file, data); err != nil {
+		return err
+	}
+
+	if etag == "" {
+		if err := os.Remove(file + ".etag"); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w", err)
+		}
+
+		return nil
+	}
+
+	return writeFileAtomic(file+".etag", []byte(etag))
+}
+
+// cachePath returns the path of the cache file for the given URL.
+func (c *Client) cachePath(u string) string {
+	sum := sha256.Sum256([]byte(u))
+
+	return filepath.Join(c.cacheDir, hex.EncodeToString(sum[:]))
+}
+
+// parseLines parses an index listing one template name per line.
+func parseLines(data []byte) ([]string, error) {
+	names := make([]string, 0)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+// parseGitHubTree parses the response of the GitHub API for the tree of the
+// github/gitignore repository, returning its root and global templates.
+func parseGitHubTree(data []byte) ([]string, error) {
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		} `json:"tree"`
+	}
+
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	var (
+		names = make([]string, 0, len(tree.Tree))
+		seen  = make(map[string]struct{}, len(tree.Tree))
+	)
+
+	for _, entry := range tree.Tree {
+		dir, file := path.Split(entry.Path)
+
+		if entry.Type != "blob" || !strings.HasSuffix(file, extension) || (dir != "" && dir != globalDir+"/") {
+			continue
+		}
+
+		name := strings.TrimSuffix(file, extension)
+		if _, ok := seen[strings.ToLower(name)]; ok {
+			continue
+		}
+
+		seen[strings.ToLower(name)] = struct{}{}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to name, so
+// concurrent readers never see a partially written file.
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+
+		return fmt.Errorf("%w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+
+		return fmt.Errorf("%w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), name); err != nil {
+		os.Remove(tmp.Name())
+
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package templates_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/templates"
+)
+
+// newTemplateServer returns a server serving a single template at /Go, counting
+// in sent how many full responses it sent.
+func newTemplateServer(t *testing.T, sent *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Go" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		sent.Add(1)
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("*.exe\n*.test\n")) //nolint:errcheck // Test server.
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestClient_Get(t *testing.T) {
+	t.Parallel()
+
+	var sent atomic.Int32
+
+	srv := newTemplateServer(t, &sent)
+
+	client := templates.NewClient(
+		templates.WithCacheDir(t.TempDir()),
+		templates.WithURLs(func(name string) []string {
+			return []string{srv.URL + "/Global/" + name, srv.URL + "/" + name}
+		}),
+	)
+
+	want := []string{"*.exe", "*.test"}
+
+	for range 2 {
+		got, err := client.Get(context.Background(), "Go")
+		if err != nil {
+			t.Fatalf("Get() unexpected error: %v", err)
+		}
+
+		if !slices.Equal(got, want) {
+			t.Errorf("Get() = %v, want %v", got, want)
+		}
+	}
+
+	if got := sent.Load(); got != 1 {
+		t.Errorf("server sent the template %d times, want 1", got)
+	}
+
+	if _, err := client.Get(context.Background(), "Missing"); !errors.Is(err, templates.ErrTemplateNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, templates.ErrTemplateNotFound)
+	}
+}
+
+func TestClient_Get_Offline(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sent  atomic.Int32
+		srv   = newTemplateServer(t, &sent)
+		dir   = t.TempDir()
+		url   = srv.URL
+		urlFn = templates.WithURLs(func(name string) []string {
+			return []string{url + "/" + name}
+		})
+	)
+
+	if _, err := templates.NewClient(templates.WithCacheDir(dir), urlFn).Get(context.Background(), "Go"); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+
+	srv.Close()
+
+	got, err := templates.NewClient(templates.WithCacheDir(dir), urlFn).Get(context.Background(), "Go")
+	if err != nil {
+		t.Fatalf("Get() with unreachable server unexpected error: %v", err)
+	}
+
+	if want := []string{"*.exe", "*.test"}; !slices.Equal(got, want) {
+		t.Errorf("Get() = %v, want %v", got, want)
+	}
+
+	if _, err = templates.NewClient(urlFn).Get(context.Background(), "Go"); err == nil {
+		t.Error("Get() without cache and unreachable server expected error, got nil")
+	}
+}
+
+func TestClient_Get_Timeout(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-done
+	}))
+
+	t.Cleanup(func() {
+		close(done)
+		srv.Close()
+	})
+
+	client := templates.NewClient(
+		templates.WithTimeout(50*time.Millisecond),
+		templates.WithURLs(func(name string) []string {
+			return []string{srv.URL + "/" + name}
+		}),
+	)
+
+	if _, err := client.Get(context.Background(), "Go"); err == nil {
+		t.Error("Get() expected timeout error, got nil")
+	}
+}
+
+func TestClient_Get_UnexpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	client := templates.NewClient(templates.WithURLs(func(name string) []string {
+		return []string{srv.URL + "/" + name}
+	}))
+
+	if _, err := client.Get(context.Background(), "Go"); !errors.Is(err, templates.ErrUnexpectedStatus) {
+		t.Errorf("Get() error = %v, want %v", err, templates.ErrUnexpectedStatus)
+	}
+}
+
+func TestClient_Get_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveSize int
+		wantErr  error
+	}{
+		{
+			name:     "Maximum size",
+			giveSize: 1 << 20,
+		},
+		{
+			name:     "Over maximum size",
+			giveSize: 1<<20 + 1,
+			wantErr:  templates.ErrResponseTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(strings.Repeat("a", tt.giveSize))) //nolint:errcheck // Test server.
+			}))
+
+			t.Cleanup(srv.Close)
+
+			dir := t.TempDir()
+
+			client := templates.NewClient(
+				templates.WithCacheDir(dir),
+				templates.WithURLs(func(name string) []string {
+					return []string{srv.URL + "/" + name}
+				}),
+			)
+
+			_, err := client.Get(context.Background(), "Go")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get() error = %v, want %v", err, tt.wantErr)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("failed to read cache directory: %v", err)
+			}
+
+			if cached := len(entries) > 0; cached != (tt.wantErr == nil) {
+				t.Errorf("cached = %t, want %t", cached, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestClient_Get_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sent atomic.Int32
+		srv  = newTemplateServer(t, &sent)
+		dir  = t.TempDir()
+		wg   sync.WaitGroup
+		want = []string{"*.exe", "*.test"}
+	)
+
+	for range 16 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			client := templates.NewClient(
+				templates.WithCacheDir(dir),
+				templates.WithURLs(func(name string) []string {
+					return []string{srv.URL + "/" + name}
+				}),
+			)
+
+			for range 20 {
+				got, err := client.Get(context.Background(), "Go")
+				if err != nil {
+					t.Errorf("Get() unexpected error: %v", err)
+
+					return
+				}
+
+				if !slices.Equal(got, want) {
+					t.Errorf("Get() = %v, want %v", got, want)
+
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,119 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// sectionBegin is the prefix of the comment opening a managed section.
+	sectionBegin = "# gitignore-go:begin "
+
+	// sectionEnd is the prefix of the comment closing a managed section.
+	sectionEnd = "# gitignore-go:end "
+)
+
+// ComposeTemplates returns the lines of a .gitignore file combining the
+// templates with the given names, in order.
+//
+// Each template is emitted in its own managed section, delimited by comments
+// naming the template, so later updates can replace each block independently:
+//
+//	# gitignore-go:begin Go
+//	...
+//	# gitignore-go:end Go
+//
+// Rules already emitted by a previous template are removed from later ones,
+// while comments and blank lines are kept as is. Templates given more than once
+// are only emitted the first time.
+func ComposeTemplates(names ...string) ([]string, error) {
+	return ComposeFrom(context.Background(), Embedded(), names...)
+}
+
+// ComposeFrom is like ComposeTemplates, but reads the templates from src.
+// Names are resolved against the ones listed by src, ignoring case, unless it
+// does not support listing, in which case the given names are used as is.
+func ComposeFrom(ctx context.Context, src TemplateSource, names ...string) ([]string, error) {
+	available, err := src.List(ctx)
+	if err != nil && !errors.Is(err, ErrListUnsupported) {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	var (
+		composed = make([]string, 0)
+		seen     = make(map[string]struct{})
+		emitted  = make(map[string]struct{}, len(names))
+	)
+
+	for _, name := range names {
+		canonical := canonicalName(available, name)
+		if _, ok := emitted[canonical]; ok {
+			continue
+		}
+
+		emitted[canonical] = struct{}{}
+
+		var lines []string
+
+		lines, err = src.Get(ctx, canonical)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		if len(composed) > 0 {
+			composed = append(composed, "")
+		}
+
+		composed = append(composed, sectionBegin+canonical)
+
+		for _, line := range trimBlank(lines) {
+			if isRule(line) {
+				if _, ok := seen[line]; ok {
+					continue
+				}
+
+				seen[line] = struct{}{}
+			}
+
+			composed = append(composed, line)
+		}
+
+		composed = append(composed, sectionEnd+canonical)
+	}
+
+	return composed, nil
+}
+
+// canonicalName returns the name in available matching name, ignoring case, or
+// name itself if there is none.
+func canonicalName(available []string, name string) string {
+	for _, candidate := range available {
+		if strings.EqualFold(candidate, name) {
+			return candidate
+		}
+	}
+
+	return name
+}
+
+// isRule reports whether line is a rule rather than a comment or blank line.
+func isRule(line string) bool {
+	trimmed := strings.TrimSpace(line)
+
+	return trimmed != "" && !strings.HasPrefix(trimmed, "#")
+}
+
+// trimBlank returns lines without its leading and trailing blank lines.
+func trimBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
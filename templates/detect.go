@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// detector maps the files that identify a kind of project to the template
+// recommended for it.
+type detector struct {
+	template string
+	patterns []string
+}
+
+// detectors lists the known kinds of projects, in the order their templates
+// are recommended.
+//
+//nolint:gochecknoglobals // Read-only lookup table.
+var detectors = []detector{
+	{template: "Go", patterns: []string{"go.mod", "go.work"}},
+	{template: "Node", patterns: []string{"package.json"}},
+	{template: "Python", patterns: []string{"pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", "Pipfile"}},
+	{template: "Rust", patterns: []string{"Cargo.toml"}},
+	{template: "Java", patterns: []string{"pom.xml", "build.gradle", "build.gradle.kts"}},
+	{template: "Ruby", patterns: []string{"Gemfile", "*.gemspec"}},
+	{template: "Terraform", patterns: []string{"*.tf"}},
+}
+
+// Detect inspects the root of fsys and returns the names of the templates
+// recommended for the project it contains, such as "Go" for a directory with a
+// go.mod file. It returns an empty slice if no known project is found.
+//
+// Only files that unambiguously identify a project are considered. Projects
+// whose usual files, such as a Dockerfile, have no matching template are not
+// reported.
+func Detect(fsys fs.FS) ([]string, error) {
+	names := make([]string, 0)
+
+	for _, d := range detectors {
+		for _, pattern := range d.patterns {
+			matches, err := fs.Glob(fsys, pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%w", err)
+			}
+
+			if len(matches) > 0 {
+				names = append(names, d.template)
+
+				break
+			}
+		}
+	}
+
+	return names, nil
+}
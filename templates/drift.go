@@ -0,0 +1,179 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrMalformedSection is returned when the managed sections of a .gitignore
+// file are not properly opened and closed.
+const ErrMalformedSection xerrors.Error = "malformed managed section"
+
+// Section is a managed section of a .gitignore file, as emitted by
+// ComposeTemplates.
+type Section struct {
+	// Template is the name of the template the section was generated from.
+	Template string
+
+	// Lines holds the lines between the section markers.
+	Lines []string
+
+	// Start is the 1-based line number of the opening marker.
+	Start int
+}
+
+// Drift describes how a managed section differs from the current version of
+// its template.
+type Drift struct {
+	// Template is the name of the template the section was generated from.
+	Template string
+
+	// Missing lists the rules of the template absent from the section.
+	Missing []string
+
+	// Extra lists the rules of the section absent from the template.
+	Extra []string
+}
+
+// Sections returns the managed sections found in the given lines of a
+// .gitignore file, in order. Lines outside of managed sections are ignored.
+func Sections(lines []string) ([]Section, error) {
+	var (
+		sections = make([]Section, 0)
+		current  *Section
+	)
+
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, sectionBegin):
+			if current != nil {
+				return nil, fmt.Errorf("%w: line %d: section %q opened inside section %q",
+					ErrMalformedSection, i+1, strings.TrimPrefix(line, sectionBegin), current.Template)
+			}
+
+			current = &Section{
+				Template: strings.TrimPrefix(line, sectionBegin),
+				Lines:    make([]string, 0),
+				Start:    i + 1,
+			}
+		case strings.HasPrefix(line, sectionEnd):
+			name := strings.TrimPrefix(line, sectionEnd)
+
+			if current == nil || current.Template != name {
+				return nil, fmt.Errorf("%w: line %d: unexpected end of section %q", ErrMalformedSection, i+1, name)
+			}
+
+			sections = append(sections, *current)
+			current = nil
+		case current != nil:
+			current.Lines = append(current.Lines, line)
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%w: line %d: section %q is never closed", ErrMalformedSection, current.Start, current.Template)
+	}
+
+	return sections, nil
+}
+
+// CheckDrift compares every managed section in the given lines of a .gitignore
+// file against the current version of its template, as returned by get, and
+// reports the sections that differ. Use [Template] as get to compare against
+// the embedded templates.
+//
+// Like ComposeTemplates, a rule of a template is not reported as missing when
+// an earlier managed section already contains it.
+func CheckDrift(lines []string, get func(name string) ([]string, error)) ([]Drift, error) {
+	sections, err := Sections(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		drifts = make([]Drift, 0)
+		seen   = make(map[string]struct{})
+	)
+
+	for _, section := range sections {
+		var want []string
+
+		want, err = get(section.Template)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		have := rules(section.Lines)
+
+		drift := Drift{
+			Template: section.Template,
+			Missing:  make([]string, 0),
+			Extra:    make([]string, 0),
+		}
+
+		wantRules := rules(want)
+
+		for _, rule := range ordered(want) {
+			_, inSection := have[rule]
+			_, inEarlier := seen[rule]
+
+			if !inSection && !inEarlier {
+				drift.Missing = append(drift.Missing, rule)
+			}
+		}
+
+		for _, rule := range ordered(section.Lines) {
+			if _, ok := wantRules[rule]; !ok {
+				drift.Extra = append(drift.Extra, rule)
+			}
+		}
+
+		for rule := range have {
+			seen[rule] = struct{}{}
+		}
+
+		if len(drift.Missing) > 0 || len(drift.Extra) > 0 {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	return drifts, nil
+}
+
+// rules returns the set of rules in lines.
+func rules(lines []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(lines))
+
+	for _, line := range lines {
+		if isRule(line) {
+			set[line] = struct{}{}
+		}
+	}
+
+	return set
+}
+
+// ordered returns the unique rules in lines, in order of appearance.
+func ordered(lines []string) []string {
+	var (
+		unique = make([]string, 0, len(lines))
+		seen   = make(map[string]struct{}, len(lines))
+	)
+
+	for _, line := range lines {
+		if !isRule(line) {
+			continue
+		}
+
+		if _, ok := seen[line]; ok {
+			continue
+		}
+
+		seen[line] = struct{}{}
+		unique = append(unique, line)
+	}
+
+	return unique
+}
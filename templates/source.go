@@ -0,0 +1,185 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// TemplateSource is a collection of templates, such as the embedded snapshot,
+// a directory, or a remote repository.
+type TemplateSource interface {
+	// List returns the names of every template in the collection.
+	List(ctx context.Context) ([]string, error)
+
+	// Get returns the lines of the template with the given name, or an error
+	// wrapping ErrTemplateNotFound if it does not exist.
+	Get(ctx context.Context, name string) ([]string, error)
+}
+
+// Ensure the built-in sources implement TemplateSource.
+var (
+	_ TemplateSource = (*FSSource)(nil)
+	_ TemplateSource = (*Client)(nil)
+)
+
+// globalDir is the directory holding global templates in a collection laid out
+// like github/gitignore.
+const globalDir = "Global"
+
+// communityDir is the directory holding the templates contributed by the
+// community in a collection laid out like github/gitignore, grouped in
+// subdirectories.
+const communityDir = "community"
+
+// FSSource is a TemplateSource reading templates from a file system laid out
+// like the github/gitignore repository, with one NAME.gitignore file per
+// template at its root, in its Global directory, or anywhere below its
+// community directory. Names are case-insensitive.
+type FSSource struct {
+	fsys fs.FS
+}
+
+// NewFSSource returns a new FSSource reading templates from fsys.
+func NewFSSource(fsys fs.FS) *FSSource {
+	return &FSSource{
+		fsys: fsys,
+	}
+}
+
+// NewDirSource returns a new FSSource reading templates from the directory dir,
+// such as a checkout of a private template repository.
+func NewDirSource(dir string) *FSSource {
+	return NewFSSource(os.DirFS(dir))
+}
+
+// Embedded returns the TemplateSource for the templates embedded in this
+// package.
+func Embedded() *FSSource {
+	sub, err := fs.Sub(files, "files")
+	if err != nil {
+		// The directory is embedded at build time, so it always exists.
+		panic(err)
+	}
+
+	return NewFSSource(sub)
+}
+
+// List implements TemplateSource. Names are sorted alphabetically, ignoring
+// case.
+func (s *FSSource) List(_ context.Context) ([]string, error) {
+	paths, err := s.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		names = append(names, strings.TrimSuffix(path.Base(p), extension))
+	}
+
+	return names, nil
+}
+
+// Get implements TemplateSource.
+func (s *FSSource) Get(_ context.Context, name string) ([]string, error) {
+	paths, err := s.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range paths {
+		if templateName(p) != strings.ToLower(name) {
+			continue
+		}
+
+		var data []byte
+
+		data, err = fs.ReadFile(s.fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+}
+
+// paths returns the paths of every template in the file system, sorted by
+// name. Templates at the root take precedence over global ones with the same
+// name, which take precedence over community ones, themselves in path order.
+func (s *FSSource) paths() ([]string, error) {
+	var (
+		paths = make([]string, 0)
+		seen  = make(map[string]struct{})
+	)
+
+	add := func(matches []string) {
+		for _, match := range matches {
+			name := templateName(match)
+			if _, ok := seen[name]; ok {
+				continue
+			}
+
+			seen[name] = struct{}{}
+			paths = append(paths, match)
+		}
+	}
+
+	for _, pattern := range []string{"*" + extension, globalDir + "/*" + extension} {
+		matches, err := fs.Glob(s.fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		add(matches)
+	}
+
+	community, err := s.community()
+	if err != nil {
+		return nil, err
+	}
+
+	add(community)
+
+	slices.SortFunc(paths, func(a, b string) int {
+		return strings.Compare(templateName(a), templateName(b))
+	})
+
+	return paths, nil
+}
+
+// community returns the paths of the templates below the community directory,
+// in lexical order, or none if there is no such directory.
+func (s *FSSource) community() ([]string, error) {
+	matches := make([]string, 0)
+
+	err := fs.WalkDir(s.fsys, communityDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && strings.HasSuffix(p, extension) {
+			matches = append(matches, p)
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return matches, nil
+}
+
+// templateName returns the lowercase name of the template at path p.
+func templateName(p string) string {
+	return strings.ToLower(strings.TrimSuffix(path.Base(p), extension))
+}
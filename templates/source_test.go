@@ -0,0 +1,137 @@
+package templates_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/templates"
+)
+
+func TestFSSource(t *testing.T) {
+	t.Parallel()
+
+	src := templates.NewFSSource(fstest.MapFS{
+		"Go.gitignore":              {Data: []byte("*.exe\n")},
+		"Internal.gitignore":        {Data: []byte("/secrets/\n")},
+		"Global/macOS.gitignore":    {Data: []byte(".DS_Store\n")},
+		"Global/go.gitignore":       {Data: []byte("shadowed\n")},
+		"community/Foo.gitignore":   {Data: []byte("foo\n")},
+		"community/A/Go.gitignore":  {Data: []byte("shadowed\n")},
+		"community/B/Bar.gitignore": {Data: []byte("bar\n")},
+		"community/C/Bar.gitignore": {Data: []byte("shadowed\n")},
+		"README.md":                 {Data: []byte("# Templates\n")},
+	})
+
+	names, err := src.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+
+	if want := []string{"Bar", "Foo", "Go", "Internal", "macOS"}; !slices.Equal(names, want) {
+		t.Errorf("List() = %v, want %v", names, want)
+	}
+
+	tests := []struct {
+		name      string
+		giveName  string
+		wantLines []string
+		wantErr   error
+	}{
+		{
+			name:      "Root template",
+			giveName:  "internal",
+			wantLines: []string{"/secrets/"},
+		},
+		{
+			name:      "Global template",
+			giveName:  "MACOS",
+			wantLines: []string{".DS_Store"},
+		},
+		{
+			name:      "Root takes precedence over global",
+			giveName:  "Go",
+			wantLines: []string{"*.exe"},
+		},
+		{
+			name:      "Community template",
+			giveName:  "foo",
+			wantLines: []string{"foo"},
+		},
+		{
+			name:      "Nested community template",
+			giveName:  "Bar",
+			wantLines: []string{"bar"},
+		},
+		{
+			name:     "Unknown template",
+			giveName: "Baz",
+			wantErr:  templates.ErrTemplateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lines, err := src.Get(context.Background(), tt.giveName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get(%q) error = %v, want %v", tt.giveName, err, tt.wantErr)
+			}
+
+			if !slices.Equal(lines, tt.wantLines) {
+				t.Errorf("Get(%q) = %v, want %v", tt.giveName, lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestClient_List(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index":
+			w.Write([]byte("node\ngo\n\n")) //nolint:errcheck // Test server.
+		case "/go":
+			w.Write([]byte("*.exe\n")) //nolint:errcheck // Test server.
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	t.Cleanup(srv.Close)
+
+	urls := templates.WithURLs(func(name string) []string {
+		return []string{srv.URL + "/" + name}
+	})
+
+	if _, err := templates.NewClient(urls).List(context.Background()); !errors.Is(err, templates.ErrListUnsupported) {
+		t.Errorf("List() without index error = %v, want %v", err, templates.ErrListUnsupported)
+	}
+
+	client := templates.NewClient(urls, templates.WithIndexURL(srv.URL+"/index"))
+
+	names, err := client.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+
+	if want := []string{"go", "node"}; !slices.Equal(names, want) {
+		t.Errorf("List() = %v, want %v", names, want)
+	}
+
+	lines, err := templates.ComposeFrom(context.Background(), client, "GO")
+	if err != nil {
+		t.Fatalf("ComposeFrom() unexpected error: %v", err)
+	}
+
+	want := []string{"# gitignore-go:begin go", "*.exe", "# gitignore-go:end go"}
+	if !slices.Equal(lines, want) {
+		t.Errorf("ComposeFrom() = %v, want %v", lines, want)
+	}
+}
@@ -0,0 +1,47 @@
+// Package templates provides a collection of .gitignore templates embedded in
+// the binary, so generating .gitignore files works offline and always produces
+// the same output for a given version of this module.
+//
+// The templates are a snapshot of the whole [github/gitignore] collection at
+// commit 57286c3887203259752b747db94e6c3ad10ec53d, laid out as upstream, which
+// is released into the public domain under CC0-1.0, as stated in
+// files/LICENSE. Global templates, such as macOS or VisualStudioCode, and the
+// ones contributed by the community, such as Hugo, are included alongside the
+// language and framework ones.
+//
+// [github/gitignore]: https://github.com/github/gitignore
+package templates
+
+import (
+	"context"
+	"embed"
+
+	"git.sr.ht/~jamesponddotco/xstd-go/xerrors"
+)
+
+// ErrTemplateNotFound is returned when a template does not exist.
+const ErrTemplateNotFound xerrors.Error = "template not found"
+
+// extension is the file extension of the embedded templates.
+const extension = ".gitignore"
+
+//go:embed files
+var files embed.FS
+
+// Template returns the lines of the embedded template with the given name, such
+// as "Go" or "macOS". Names are case-insensitive.
+func Template(name string) ([]string, error) {
+	return Embedded().Get(context.Background(), name)
+}
+
+// Templates returns the names of every embedded template, sorted
+// alphabetically.
+func Templates() []string {
+	names, err := Embedded().List(context.Background())
+	if err != nil {
+		// The directory is embedded at build time, so it always exists.
+		panic(err)
+	}
+
+	return names
+}
@@ -0,0 +1,296 @@
+package templates_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+	"git.sr.ht/~jamesponddotco/gitignore-go/templates"
+)
+
+func TestTemplates(t *testing.T) {
+	t.Parallel()
+
+	names := templates.Templates()
+
+	for _, want := range []string{"Go", "Node", "Python", "Rust", "macOS"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Templates() = %v, want it to contain %q", names, want)
+		}
+	}
+
+	// Every template must be usable with this package.
+	for _, name := range names {
+		lines, err := templates.Template(name)
+		if err != nil {
+			t.Errorf("Template(%q) unexpected error: %v", name, err)
+
+			continue
+		}
+
+		if _, err = gitignore.NewFromLines(lines); err != nil {
+			t.Errorf("NewFromLines(Template(%q)) unexpected error: %v", name, err)
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveName  string
+		wantMatch string
+		wantErr   error
+	}{
+		{
+			name:      "Exact name",
+			giveName:  "Go",
+			wantMatch: "go.work",
+		},
+		{
+			name:      "Case-insensitive name",
+			giveName:  "node",
+			wantMatch: "node_modules/",
+		},
+		{
+			name:      "Global template",
+			giveName:  "MACOS",
+			wantMatch: ".DS_Store",
+		},
+		{
+			name:      "Community template",
+			giveName:  "Hugo",
+			wantMatch: "public/",
+		},
+		{
+			name:     "Unknown template",
+			giveName: "NoSuchLanguage",
+			wantErr:  templates.ErrTemplateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lines, err := templates.Template(tt.giveName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Template(%q) error = %v, want %v", tt.giveName, err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			file, err := gitignore.NewFromLines(lines)
+			if err != nil {
+				t.Fatalf("failed to create matcher: %v", err)
+			}
+
+			if !file.Match(tt.wantMatch) {
+				t.Errorf("Template(%q) does not match %q", tt.giveName, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestComposeTemplates(t *testing.T) {
+	t.Parallel()
+
+	lines, err := templates.ComposeTemplates("Go", "python", "Go")
+	if err != nil {
+		t.Fatalf("ComposeTemplates() unexpected error: %v", err)
+	}
+
+	wantMarkers := []string{
+		"# gitignore-go:begin Go",
+		"# gitignore-go:end Go",
+		"# gitignore-go:begin Python",
+		"# gitignore-go:end Python",
+	}
+
+	for _, want := range wantMarkers {
+		if got := count(lines, want); got != 1 {
+			t.Errorf("ComposeTemplates() contains %q %d times, want 1", want, got)
+		}
+	}
+
+	// Both templates ignore .env and *.so, which must only be emitted once.
+	for _, rule := range []string{".env", "*.so"} {
+		if got := count(lines, rule); got != 1 {
+			t.Errorf("ComposeTemplates() contains rule %q %d times, want 1", rule, got)
+		}
+	}
+
+	if _, err = gitignore.NewFromLines(lines); err != nil {
+		t.Errorf("NewFromLines(ComposeTemplates()) unexpected error: %v", err)
+	}
+
+	if _, err = templates.ComposeTemplates("Go", "Missing"); !errors.Is(err, templates.ErrTemplateNotFound) {
+		t.Errorf("ComposeTemplates() error = %v, want %v", err, templates.ErrTemplateNotFound)
+	}
+}
+
+func count(lines []string, line string) int {
+	var n int
+
+	for _, l := range lines {
+		if l == line {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveFS   fstest.MapFS
+		wantList []string
+	}{
+		{
+			name: "Go and Node project",
+			giveFS: fstest.MapFS{
+				"package.json": {},
+				"go.mod":       {},
+				"Dockerfile":   {},
+			},
+			wantList: []string{"Go", "Node"},
+		},
+		{
+			name: "Glob patterns",
+			giveFS: fstest.MapFS{
+				"main.tf":        {},
+				"gem.gemspec":    {},
+				"pyproject.toml": {},
+			},
+			wantList: []string{"Python", "Ruby", "Terraform"},
+		},
+		{
+			name: "Nested files are ignored",
+			giveFS: fstest.MapFS{
+				"tools/go.mod": {},
+			},
+			wantList: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := templates.Detect(tt.giveFS)
+			if err != nil {
+				t.Fatalf("Detect() unexpected error: %v", err)
+			}
+
+			if !slices.Equal(got, tt.wantList) {
+				t.Errorf("Detect() = %v, want %v", got, tt.wantList)
+			}
+		})
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	t.Parallel()
+
+	current, err := templates.ComposeTemplates("Go", "Python")
+	if err != nil {
+		t.Fatalf("ComposeTemplates() unexpected error: %v", err)
+	}
+
+	drifts, err := templates.CheckDrift(current, templates.Template)
+	if err != nil {
+		t.Fatalf("CheckDrift() unexpected error: %v", err)
+	}
+
+	if len(drifts) != 0 {
+		t.Errorf("CheckDrift() of current templates = %v, want no drift", drifts)
+	}
+
+	// Simulate an older version of the Go template: one rule was added upstream
+	// since, and one was removed.
+	outdated := []string{
+		"# Project rules.",
+		"/bin/",
+		"",
+		"# gitignore-go:begin Go",
+		"*.exe",
+		"*.exe~",
+		"*.dll",
+		"*.so",
+		"*.dylib",
+		"*.out",
+		"go.work",
+		"go.work.sum",
+		"*.obsolete",
+		"# gitignore-go:end Go",
+	}
+
+	drifts, err = templates.CheckDrift(outdated, func(_ string) ([]string, error) {
+		return []string{"*.exe", "*.exe~", "*.dll", "*.so", "*.dylib", "*.test", "*.out", "go.work", "go.work.sum"}, nil
+	})
+	if err != nil {
+		t.Fatalf("CheckDrift() unexpected error: %v", err)
+	}
+
+	want := []templates.Drift{{
+		Template: "Go",
+		Missing:  []string{"*.test"},
+		Extra:    []string{"*.obsolete"},
+	}}
+
+	if len(drifts) != len(want) {
+		t.Fatalf("CheckDrift() = %v, want %v", drifts, want)
+	}
+
+	for i := range want {
+		if drifts[i].Template != want[i].Template ||
+			!slices.Equal(drifts[i].Missing, want[i].Missing) ||
+			!slices.Equal(drifts[i].Extra, want[i].Extra) {
+			t.Errorf("CheckDrift()[%d] = %+v, want %+v", i, drifts[i], want[i])
+		}
+	}
+}
+
+func TestSections_Malformed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveLines []string
+	}{
+		{
+			name:      "Unclosed section",
+			giveLines: []string{"# gitignore-go:begin Go", "*.exe"},
+		},
+		{
+			name:      "Unopened section",
+			giveLines: []string{"*.exe", "# gitignore-go:end Go"},
+		},
+		{
+			name:      "Nested sections",
+			giveLines: []string{"# gitignore-go:begin Go", "# gitignore-go:begin Node", "# gitignore-go:end Node", "# gitignore-go:end Go"},
+		},
+		{
+			name:      "Mismatched end",
+			giveLines: []string{"# gitignore-go:begin Go", "# gitignore-go:end Node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := templates.Sections(tt.giveLines); !errors.Is(err, templates.ErrMalformedSection) {
+				t.Errorf("Sections() error = %v, want %v", err, templates.ErrMalformedSection)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package gitignore
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// ShouldUpload reports whether the file at path, described by info, should be
+// uploaded by deploy and sync tools, which is when it is a regular file not
+// ignored by m.
+//
+// The path is relative to the directory the rules apply to and may use the
+// separator of the operating system. When path is a directory ignored by m,
+// ShouldUpload returns [fs.SkipDir], so callbacks of [filepath.Walk] and
+// [fs.WalkDir] can return the error as is to prune the whole subtree instead
+// of visiting every file inside it.
+func ShouldUpload(m Matcher, path string, info fs.FileInfo) (bool, error) {
+	path = filepath.ToSlash(path)
+
+	if path == "." || path == "" {
+		return false, nil
+	}
+
+	if info.IsDir() {
+		if m.Match(path + "/") {
+			return false, fs.SkipDir
+		}
+
+		return false, nil
+	}
+
+	if !info.Mode().IsRegular() {
+		return false, nil
+	}
+
+	return !m.Match(path), nil
+}
@@ -0,0 +1,117 @@
+package gitignore_test
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"testing"
+	"testing/fstest"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestShouldUpload(t *testing.T) {
+	t.Parallel()
+
+	matcher := newTestMatcher(t, "*.log", "!keep.log", "node_modules/", "tmp")
+
+	fsys := fstest.MapFS{
+		"index.html":                  {Data: []byte("<h1>Hello</h1>")},
+		"debug.log":                   {Data: []byte("debug")},
+		"keep.log":                    {Data: []byte("keep")},
+		"link":                        {Data: []byte("index.html"), Mode: fs.ModeSymlink},
+		"node_modules/left-pad/a.js":  {Data: []byte("module.exports = {}")},
+		"static/style.css":            {Data: []byte("body {}")},
+		"static/tmp/cache.bin":        {Data: []byte("cache")},
+		"static/vendor/lib/script.js": {Data: []byte("console.log('hi')")},
+	}
+
+	tests := []struct {
+		name     string
+		givePath string
+		want     bool
+		wantErr  error
+	}{
+		{
+			name:     "Included file",
+			givePath: "index.html",
+			want:     true,
+		},
+		{
+			name:     "Ignored file",
+			givePath: "debug.log",
+			want:     false,
+		},
+		{
+			name:     "Negated file",
+			givePath: "keep.log",
+			want:     true,
+		},
+		{
+			name:     "Symbolic link",
+			givePath: "link",
+			want:     false,
+		},
+		{
+			name:     "Included directory",
+			givePath: "static",
+			want:     false,
+		},
+		{
+			name:     "Ignored directory",
+			givePath: "node_modules",
+			want:     false,
+			wantErr:  fs.SkipDir,
+		},
+		{
+			name:     "Nested ignored directory",
+			givePath: "static/tmp",
+			want:     false,
+			wantErr:  fs.SkipDir,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			info := lstat(t, fsys, tt.givePath)
+
+			got, err := gitignore.ShouldUpload(matcher, tt.givePath, info)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ShouldUpload(%q) error = %v, want %v", tt.givePath, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ShouldUpload(%q) = %v, want %v", tt.givePath, got, tt.want)
+			}
+		})
+	}
+}
+
+// lstat returns the information of name without following symbolic links.
+func lstat(t *testing.T, fsys fs.FS, name string) fs.FileInfo {
+	t.Helper()
+
+	entries, err := fs.ReadDir(fsys, path.Dir(name))
+	if err != nil {
+		t.Fatalf("failed to read directory of %q: %v", name, err)
+	}
+
+	for _, entry := range entries {
+		if entry.Name() != path.Base(name) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatalf("failed to stat %q: %v", name, err)
+		}
+
+		return info
+	}
+
+	t.Fatalf("failed to find %q", name)
+
+	return nil
+}
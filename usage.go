@@ -0,0 +1,85 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// DiskUsage returns the total size, in bytes, of the regular files below root
+// that m does not ignore, and of the ones it ignores, such as build artifacts
+// and dependencies, which are the files inside ignored directories included.
+// Only the rules of m apply, with paths relative to root, and the .git
+// directories are skipped, like with ListIncluded. A nil m ignores nothing.
+//
+// Directories that cannot be read are handled according to the error policy set
+// by opts, failing on the first one by default. The sizes counted so far are
+// returned along with the error.
+func DiskUsage(root string, m Matcher, opts ...WalkOption) (int64, int64, error) {
+	w, err := newTreeWalker(root, opts)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// An empty chain ignores nothing.
+	if m == nil {
+		m = chain(nil)
+	}
+
+	var included, ignored int64
+
+	// every counts every file inside an ignored directory.
+	every := func(name string, d fs.DirEntry) (bool, error) {
+		size, err := w.size(name, d)
+		ignored += size
+
+		return true, err
+	}
+
+	err = w.walk(".", func(name string, d fs.DirEntry) (bool, error) {
+		if d.Name() == gitDir {
+			return false, nil
+		}
+
+		if d.IsDir() {
+			if !m.Match(name + "/") {
+				return true, nil
+			}
+
+			return false, w.walk(name, every)
+		}
+
+		size, err := w.size(name, d)
+		if m.Match(name) {
+			ignored += size
+		} else {
+			included += size
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return included, ignored, err
+	}
+
+	return included, ignored, w.opts.err()
+}
+
+// size returns the size of the entry d at name if it is a regular file, or 0.
+// Files removed while walking count as empty.
+func (w *walker) size(name string, d fs.DirEntry) (int64, error) {
+	if !d.Type().IsRegular() {
+		return 0, nil
+	}
+
+	info, err := d.Info()
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, w.opts.handle(name, fmt.Errorf("%w", err))
+	}
+
+	return info.Size(), nil
+}
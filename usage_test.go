@@ -0,0 +1,84 @@
+package gitignore_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestDiskUsage(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/objects/pack":         strings.Repeat("x", 1000),
+		".gitignore":                "*.log\nnode_modules/\n",
+		"main.go":                   strings.Repeat("m", 100),
+		"pkg/a.go":                  strings.Repeat("a", 20),
+		"pkg/debug.log":             strings.Repeat("d", 300),
+		"node_modules/pkg/index.js": strings.Repeat("i", 4000),
+		"node_modules/pkg/keep.go":  strings.Repeat("k", 5),
+	})
+
+	if err := os.Symlink("main.go", filepath.Join(root, "link.go")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	file, err := gitignore.New(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	included, ignored, err := gitignore.DiskUsage(root, file)
+	if err != nil {
+		t.Fatalf("DiskUsage() unexpected error: %v", err)
+	}
+
+	// The .gitignore file counts too.
+	if want := int64(len("*.log\nnode_modules/\n") + 100 + 20); included != want {
+		t.Errorf("DiskUsage() included = %d, want %d", included, want)
+	}
+
+	if want := int64(300 + 4000 + 5); ignored != want {
+		t.Errorf("DiskUsage() ignored = %d, want %d", ignored, want)
+	}
+}
+
+func TestDiskUsage_NotExist(t *testing.T) {
+	t.Parallel()
+
+	file, err := gitignore.NewFromLines(nil)
+	if err != nil {
+		t.Fatalf("NewFromLines() unexpected error: %v", err)
+	}
+
+	if _, _, err := gitignore.DiskUsage(filepath.Join(t.TempDir(), "missing"), file); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("DiskUsage() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestDiskUsage_NilMatcher(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":   strings.Repeat("c", 1000),
+		"main.go":       strings.Repeat("m", 100),
+		"pkg/debug.log": strings.Repeat("d", 300),
+	})
+
+	included, ignored, err := gitignore.DiskUsage(root, nil)
+	if err != nil {
+		t.Fatalf("DiskUsage() unexpected error: %v", err)
+	}
+
+	if included != 400 || ignored != 0 {
+		t.Errorf("DiskUsage() = %d, %d, want 400, 0", included, ignored)
+	}
+}
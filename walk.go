@@ -0,0 +1,271 @@
+package gitignore
+
+import (
+	"errors"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Walk walks the file tree rooted at root like [filepath.WalkDir], calling fn
+// for every file and directory that m does not ignore, root included. Ignored
+// directories are pruned without being read, so large ignored trees such as
+// node_modules are never entered, and nothing inside them is reported, even if
+// a negated rule matches it, like with git.
+//
+// Paths are given to m slash-separated and relative to root, with a trailing
+// slash for directories, so m is typically the File of the .gitignore file of
+// root, or a Repository rooted at it, which also applies the .gitignore files
+// of the directories below. A nil m ignores nothing.
+//
+// As with filepath.WalkDir, errors reading root or a directory are passed to
+// fn, which decides whether to carry on, and fn may return [fs.SkipDir] or
+// [fs.SkipAll]. The error policy set by opts thus does not apply, while the
+// walk stops as soon as the context set with WithContext is done.
+//
+// Symbolic links are reported without being followed, unless another policy
+// is set with WithSymlinkPolicy.
+func Walk(root string, m Matcher, fn fs.WalkDirFunc, opts ...WalkOption) error {
+	return walk(root, m, fn, newWalkOptions(opts))
+}
+
+// walk is Walk, configured by o.
+func walk(root string, m Matcher, fn fs.WalkDirFunc, o *walkOptions) error {
+	ignored := func(string, fs.DirEntry) (bool, bool) {
+		return false, false
+	}
+
+	if m != nil {
+		ignored = Predicate(m)
+	}
+
+	defer o.startStats()()
+
+	t := &treeWalk{
+		fn:       fn,
+		ignored:  ignored,
+		opts:     o,
+		root:     root,
+		realRoot: root,
+		walking:  make(map[string]struct{}),
+	}
+
+	if o.symlinks == SymlinkFollow {
+		t.realRoot = realPath(root)
+	}
+
+	info, err := os.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = t.visit(root, t.realRoot, fs.FileInfoToDirEntry(info))
+	}
+
+	if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+
+	return err
+}
+
+// treeWalk is a walk of the tree rooted at root, which may go through the
+// symbolic links to directories it follows.
+type treeWalk struct {
+	fn      fs.WalkDirFunc
+	ignored func(path string, d fs.DirEntry) (bool, bool)
+	opts    *walkOptions
+
+	// root is the root of the walk, and realRoot its path with every
+	// symbolic link resolved, which followed links must not escape.
+	root     string
+	realRoot string
+
+	// walking holds the real paths of the directories being walked, from
+	// the root to the current one, which followed links must not lead back
+	// to.
+	walking map[string]struct{}
+}
+
+// visit reports the entry d at path, whose real path is real, unless it is
+// skipped, then walks it if it is a directory, like [filepath.WalkDir] does.
+func (t *treeWalk) visit(path, real string, d fs.DirEntry) error {
+	if err := t.opts.canceled(); err != nil {
+		return err
+	}
+
+	if path != t.root {
+		rel, err := filepath.Rel(t.root, path)
+		if err != nil {
+			return t.fn(path, d, err)
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 && t.opts.symlinks == SymlinkSkip {
+			return nil
+		}
+
+		var target string
+
+		d, target = t.resolve(path, real, d)
+		if target != "" {
+			real = target
+		}
+
+		skip, _ := t.ignored(rel, d)
+
+		t.opts.count(d, skip)
+
+		if skip || t.opts.pruned(filepath.ToSlash(rel), d) {
+			return nil
+		}
+
+		if t.opts.deepest(strings.Count(rel, string(filepath.Separator)) + 1) {
+			return skipDir(t.fn(path, d, nil), d)
+		}
+	}
+
+	if err := t.fn(path, d, nil); err != nil || !d.IsDir() {
+		return skipDir(err, d)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if err = t.fn(path, d, err); err != nil {
+			return skipDir(err, d)
+		}
+	}
+
+	if t.opts.symlinks == SymlinkFollow {
+		t.walking[real] = struct{}{}
+		defer delete(t.walking, real)
+	}
+
+	t.opts.sort(entries)
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if err := t.visit(filepath.Join(path, name), filepath.Join(real, name), entry); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				break
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// skipDir returns err, the error of fn for the entry d, or nil if it is
+// [fs.SkipDir] and d is a directory, which is skipped, like [filepath.WalkDir]
+// does.
+func skipDir(err error, d fs.DirEntry) error {
+	if errors.Is(err, fs.SkipDir) && d.IsDir() {
+		return nil
+	}
+
+	return err
+}
+
+// resolve returns the entry to report for d, found at name, whose real path is
+// real, and the real path of the directory to walk in its place if it is a
+// symbolic link to follow. Links are only followed with SymlinkFollow, and
+// links to directories only if they are within the root of the walk, and not
+// one of the directories being walked, which would loop forever, whether the
+// link points to one of its ancestors or to another link leading back to it.
+// The links that are not followed are reported as is.
+func (t *treeWalk) resolve(name, real string, d fs.DirEntry) (fs.DirEntry, string) {
+	if t.opts.symlinks != SymlinkFollow || d.Type()&fs.ModeSymlink == 0 {
+		return d, ""
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		// Dangling links cannot be followed.
+		return d, ""
+	}
+
+	if !info.IsDir() {
+		return fs.FileInfoToDirEntry(info), ""
+	}
+
+	target := realPath(name)
+
+	if _, err := RelPath(t.realRoot, target); err != nil {
+		return d, ""
+	}
+
+	if _, ok := t.walking[target]; ok {
+		return d, ""
+	}
+
+	return fs.FileInfoToDirEntry(info), target
+}
+
+// realPath returns the absolute path of name with every symbolic link
+// resolved, or its absolute path if they cannot be resolved, so the real paths
+// of directories can be compared.
+func realPath(name string) string {
+	if real, err := filepath.EvalSymlinks(name); err == nil {
+		name = real
+	}
+
+	if abs, err := filepath.Abs(name); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(name)
+}
+
+// WalkSeq is like Walk, but returns an iterator yielding the path and entry of
+// every file and directory m does not ignore, so consumers can process them as
+// the walk goes, and stop it by breaking out of the loop. The tree is only
+// walked as the iterator is consumed, which applies backpressure to the walk.
+//
+// Errors reading root or a directory are handled according to the error policy
+// set by opts: by default, the walk stops at the first one. The walk also stops
+// once the context set with WithContext is done. The returned
+// function reports the error that stopped the walk, or the errors collected
+// with WithSkipErrors, once the iteration is done:
+//
+//	seq, errf := gitignore.WalkSeq(root, m)
+//
+//	for path, d := range seq {
+//		// Process path.
+//	}
+//
+//	if err := errf(); err != nil {
+//		return err
+//	}
+func WalkSeq(root string, m Matcher, opts ...WalkOption) (iter.Seq2[string, fs.DirEntry], func() error) {
+	var (
+		o   = newWalkOptions(opts)
+		err error
+	)
+
+	seq := func(yield func(string, fs.DirEntry) bool) {
+		o.errs = nil
+
+		err = walk(root, m, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return o.handle(path, err)
+			}
+
+			if !yield(path, d) {
+				return fs.SkipAll
+			}
+
+			return nil
+		}, o)
+	}
+
+	return seq, func() error {
+		if err != nil {
+			return err
+		}
+
+		return o.err()
+	}
+}
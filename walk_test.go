@@ -0,0 +1,876 @@
+package gitignore_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".git/config":               "",
+		".gitignore":                "*.log\nnode_modules/\n!keep.log\n",
+		"main.go":                   "",
+		"debug.log":                 "",
+		"keep.log":                  "",
+		"node_modules/pkg/index.js": "",
+		"node_modules/keep.log":     "",
+		"pkg/.gitignore":            "*.tmp\n",
+		"pkg/a.go":                  "",
+		"pkg/a.tmp":                 "",
+		"pkg/sub/b.tmp":             "",
+		"pkg/sub/b.go":              "",
+	})
+
+	repo, err := gitignore.OpenRepository(root, gitignore.WithHomeDir(t.TempDir()), gitignore.WithoutDefaultExcludes())
+	if err != nil {
+		t.Fatalf("OpenRepository() error = %v", err)
+	}
+
+	var (
+		got     []string
+		matched []string
+	)
+
+	m := gitignore.MatcherFunc(func(path string) bool {
+		matched = append(matched, path)
+
+		return repo.Match(path)
+	})
+
+	err = gitignore.Walk(root, m, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			rel += "/"
+		}
+
+		got = append(got, rel)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	want := []string{
+		"./",
+		".gitignore",
+		"keep.log",
+		"main.go",
+		"pkg/",
+		"pkg/.gitignore",
+		"pkg/a.go",
+		"pkg/sub/",
+		"pkg/sub/b.go",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Walk() visited %q, want %q", got, want)
+	}
+
+	for _, path := range matched {
+		if strings.HasPrefix(path, "node_modules/") && path != "node_modules/" {
+			t.Errorf("Walk() entered ignored directory, matching %q", path)
+		}
+	}
+}
+
+func TestWalk_NilMatcher(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".gitignore": "*.log\n",
+		"debug.log":  "",
+	})
+
+	var count int
+
+	err := gitignore.Walk(root, nil, func(string, fs.DirEntry, error) error {
+		count++
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	if count != 3 {
+		t.Errorf("Walk() visited %d entries, want %d", count, 3)
+	}
+}
+
+func TestWalk_Error(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"a/file": "",
+		"b/file": "",
+	})
+
+	errStop := errors.New("stop")
+
+	var visited []string
+
+	err := gitignore.Walk(root, nil, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		visited = append(visited, filepath.Base(path))
+
+		switch filepath.Base(path) {
+		case "a":
+			return fs.SkipDir
+		case "b":
+			return errStop
+		}
+
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("Walk() error = %v, want %v", err, errStop)
+	}
+
+	if want := []string{filepath.Base(root), "a", "b"}; !slices.Equal(visited, want) {
+		t.Errorf("Walk() visited %q, want %q", visited, want)
+	}
+
+	missing := filepath.Join(root, "missing")
+
+	err = gitignore.Walk(missing, nil, func(_ string, _ fs.DirEntry, err error) error {
+		return err
+	})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Walk() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestWalkSeq(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"main.go":           "",
+		"debug.log":         "",
+		"node_modules/a.js": "",
+		"pkg/a.go":          "",
+		"pkg/b.go":          "",
+	})
+
+	m, err := gitignore.NewFromLines([]string{"*.log", "node_modules/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	seq, errf := gitignore.WalkSeq(root, m)
+
+	var got []string
+
+	for path, d := range seq {
+		if d.IsDir() {
+			continue
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			t.Fatalf("Rel() error = %v", err)
+		}
+
+		got = append(got, filepath.ToSlash(rel))
+
+		if len(got) == 2 {
+			break
+		}
+	}
+
+	if err = errf(); err != nil {
+		t.Errorf("WalkSeq() error = %v", err)
+	}
+
+	if want := []string{"main.go", "pkg/a.go"}; !slices.Equal(got, want) {
+		t.Errorf("WalkSeq() yielded %q, want %q", got, want)
+	}
+}
+
+func TestWalkSeq_Error(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	tests := []struct {
+		name     string
+		giveOpts []gitignore.WalkOption
+	}{
+		{name: "Fail fast"},
+		{name: "Skip errors", giveOpts: []gitignore.WalkOption{gitignore.WithSkipErrors()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			seq, errf := gitignore.WalkSeq(missing, nil, tt.giveOpts...)
+
+			for path := range seq {
+				t.Errorf("WalkSeq() yielded %q", path)
+			}
+
+			if err := errf(); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("WalkSeq() error = %v, want %v", err, os.ErrNotExist)
+			}
+		})
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"a/file.go": "",
+		"b/file.go": "",
+		"debug.log": "",
+	})
+
+	m, err := gitignore.NewFromLines([]string{"*.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	canceled := gitignore.WithContext(ctx)
+
+	tests := []struct {
+		name string
+		give func() error
+	}{
+		{
+			name: "Walk",
+			give: func() error {
+				return gitignore.Walk(root, m, func(string, fs.DirEntry, error) error {
+					return nil
+				}, canceled)
+			},
+		},
+		{
+			name: "WalkSeq",
+			give: func() error {
+				seq, errf := gitignore.WalkSeq(root, m, canceled, gitignore.WithSkipErrors())
+				for range seq {
+					t.Error("WalkSeq() yielded an entry")
+				}
+
+				return errf()
+			},
+		},
+		{
+			name: "ListIgnored",
+			give: func() error {
+				_, err := m.ListIgnored(root, canceled, gitignore.WithSkipErrors())
+
+				return err
+			},
+		},
+		{
+			name: "ListIncluded",
+			give: func() error {
+				_, err := m.ListIncluded(root, canceled)
+
+				return err
+			},
+		},
+		{
+			name: "Untracked",
+			give: func() error {
+				_, err := gitignore.Untracked(os.DirFS(root), nil, m, canceled)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.give(); !errors.Is(err, context.Canceled) {
+				t.Errorf("error = %v, want %v", err, context.Canceled)
+			}
+		})
+	}
+}
+
+func TestWalk_Cancel(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"a/file.go": "",
+		"b/file.go": "",
+		"c/file.go": "",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var visited []string
+
+	err := gitignore.Walk(root, nil, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		visited = append(visited, filepath.Base(path))
+
+		if filepath.Base(path) == "b" {
+			cancel()
+		}
+
+		return nil
+	}, gitignore.WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Walk() error = %v, want %v", err, context.Canceled)
+	}
+
+	if want := []string{filepath.Base(root), "a", "file.go", "b"}; !slices.Equal(visited, want) {
+		t.Errorf("Walk() visited %q, want %q", visited, want)
+	}
+}
+
+func TestWithStats(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"main.go":                   "package main\n",
+		"debug.log":                 "12345",
+		"pkg/a.go":                  "package pkg\n",
+		"pkg/trace.log":             "1234567890",
+		"node_modules/pkg/index.js": "module.exports = {}\n",
+	})
+
+	file, err := gitignore.NewFromLines([]string{"*.log", "node_modules/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() unexpected error: %v", err)
+	}
+
+	want := gitignore.WalkStats{
+		Files:        2,
+		Ignored:      2,
+		Pruned:       1,
+		BytesSkipped: 15,
+	}
+
+	tests := []struct {
+		name string
+		walk func(opts ...gitignore.WalkOption) error
+	}{
+		{
+			name: "Walk",
+			walk: func(opts ...gitignore.WalkOption) error {
+				return gitignore.Walk(root, file, func(_ string, _ fs.DirEntry, err error) error {
+					return err
+				}, opts...)
+			},
+		},
+		{
+			name: "ListIncluded",
+			walk: func(opts ...gitignore.WalkOption) error {
+				_, err := file.ListIncluded(root, opts...)
+
+				return err
+			},
+		},
+		{
+			name: "ListIgnored",
+			walk: func(opts ...gitignore.WalkOption) error {
+				_, err := file.ListIgnored(root, opts...)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Stale statistics must be reset by the walk.
+			stats := gitignore.WalkStats{Files: 42}
+
+			if err := tt.walk(gitignore.WithStats(&stats)); err != nil {
+				t.Fatalf("walk unexpected error: %v", err)
+			}
+
+			if stats.Elapsed <= 0 {
+				t.Errorf("Elapsed = %v, want a positive duration", stats.Elapsed)
+			}
+
+			stats.Elapsed = 0
+
+			if stats != want {
+				t.Errorf("WithStats() = %+v, want %+v", stats, want)
+			}
+		})
+	}
+}
+
+func TestWithSymlinkPolicy(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		".gitignore":     "*.log\n",
+		"main.go":        "",
+		"data/file.txt":  "",
+		"data/trace.log": "",
+	})
+
+	for link, target := range map[string]string{
+		"link-file": "main.go",
+		"link-dir":  "data",
+		"data/loop": "..",
+		"escape":    t.TempDir(),
+		"dangling":  "missing",
+	} {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(link))); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+	}
+
+	file, err := gitignore.New(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		givePolicy gitignore.SymlinkPolicy
+		giveStop   string
+		want       []string
+	}{
+		{
+			name:       "Report",
+			givePolicy: gitignore.SymlinkReport,
+			want: []string{
+				".gitignore", "dangling@", "data/", "data/file.txt", "data/loop@",
+				"escape@", "link-dir@", "link-file@", "main.go",
+			},
+		},
+		{
+			name:       "Skip",
+			givePolicy: gitignore.SymlinkSkip,
+			want:       []string{".gitignore", "data/", "data/file.txt", "main.go"},
+		},
+		{
+			name:       "Follow",
+			givePolicy: gitignore.SymlinkFollow,
+			want: []string{
+				".gitignore", "dangling@", "data/", "data/file.txt", "data/loop@",
+				"escape@", "link-dir/", "link-dir/file.txt", "link-dir/loop@",
+				"link-file", "main.go",
+			},
+		},
+		{
+			name:       "Stop inside a followed link",
+			givePolicy: gitignore.SymlinkFollow,
+			giveStop:   "link-dir/file.txt",
+			want: []string{
+				".gitignore", "dangling@", "data/", "data/file.txt", "data/loop@",
+				"escape@", "link-dir/", "link-dir/file.txt",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+
+			err := gitignore.Walk(root, file, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if path == root {
+					return nil
+				}
+
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return err
+				}
+
+				rel = filepath.ToSlash(rel)
+
+				switch {
+				case d.IsDir():
+					got = append(got, rel+"/")
+				case d.Type()&fs.ModeSymlink != 0:
+					got = append(got, rel+"@")
+				default:
+					got = append(got, rel)
+				}
+
+				if rel == tt.giveStop {
+					return fs.SkipAll
+				}
+
+				return nil
+			}, gitignore.WithSymlinkPolicy(tt.givePolicy))
+			if err != nil {
+				t.Fatalf("Walk() unexpected error: %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Walk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithSymlinkPolicy_MutualLinks(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"a/file.txt": "",
+		"b/file.txt": "",
+	})
+
+	for link, target := range map[string]string{
+		"a/l1": "../b",
+		"b/l2": "../a",
+	} {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(link))); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+	}
+
+	var got []string
+
+	err := gitignore.Walk(root, nil, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			rel += "@"
+		}
+
+		got = append(got, filepath.ToSlash(rel))
+
+		return nil
+	}, gitignore.WithSymlinkPolicy(gitignore.SymlinkFollow))
+	if err != nil {
+		t.Fatalf("Walk() unexpected error: %v", err)
+	}
+
+	// Each link is followed once, until it leads back to a directory being
+	// walked.
+	want := []string{
+		".", "a", "a/file.txt", "a/l1", "a/l1/file.txt", "a/l1/l2@",
+		"b", "b/file.txt", "b/l2", "b/l2/file.txt", "b/l2/l1@",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Walk() = %v, want %v", got, want)
+	}
+}
+
+func TestWithMaxDepth(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"main.go":         "",
+		"debug.log":       "",
+		"a/a.go":          "",
+		"a/a.log":         "",
+		"a/b/b.go":        "",
+		"a/b/c/c.go":      "",
+		"build/out.bin":   "",
+		"build/sub/x.bin": "",
+	})
+
+	file, err := gitignore.NewFromLines([]string{"*.log", "build/"})
+	if err != nil {
+		t.Fatalf("NewFromLines() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		giveDepth    int
+		wantWalk     []string
+		wantIncluded []string
+		wantIgnored  []string
+	}{
+		{
+			name:         "No limit",
+			giveDepth:    0,
+			wantWalk:     []string{"a", "a/a.go", "a/b", "a/b/b.go", "a/b/c", "a/b/c/c.go", "main.go"},
+			wantIncluded: []string{"a/a.go", "a/b/b.go", "a/b/c/c.go", "main.go"},
+			wantIgnored:  []string{"a/a.log", "build/out.bin", "build/sub/x.bin", "debug.log"},
+		},
+		{
+			name:         "One level",
+			giveDepth:    1,
+			wantWalk:     []string{"a", "main.go"},
+			wantIncluded: []string{"main.go"},
+			wantIgnored:  []string{"debug.log"},
+		},
+		{
+			name:         "Two levels",
+			giveDepth:    2,
+			wantWalk:     []string{"a", "a/a.go", "a/b", "main.go"},
+			wantIncluded: []string{"a/a.go", "main.go"},
+			wantIgnored:  []string{"a/a.log", "build/out.bin", "debug.log"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opt := gitignore.WithMaxDepth(tt.giveDepth)
+
+			if got := walkPaths(t, root, file, opt); !slices.Equal(got, tt.wantWalk) {
+				t.Errorf("Walk() = %v, want %v", got, tt.wantWalk)
+			}
+
+			included, err := file.ListIncluded(root, opt)
+			if err != nil {
+				t.Fatalf("ListIncluded() unexpected error: %v", err)
+			}
+
+			if !slices.Equal(included, tt.wantIncluded) {
+				t.Errorf("ListIncluded() = %v, want %v", included, tt.wantIncluded)
+			}
+
+			ignored, err := file.ListIgnored(root, opt, gitignore.WithExpandedDirs())
+			if err != nil {
+				t.Fatalf("ListIgnored() unexpected error: %v", err)
+			}
+
+			if !slices.Equal(ignored, tt.wantIgnored) {
+				t.Errorf("ListIgnored() = %v, want %v", ignored, tt.wantIgnored)
+			}
+		})
+	}
+}
+
+func TestWithPrune(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"main.go":                 "",
+		"vendor/lib/lib.go":       "",
+		"pkg/a.go":                "",
+		"pkg/testdata/golden.txt": "",
+		"pkg/debug.log":           "",
+	})
+
+	file, err := gitignore.NewFromLines([]string{"*.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() unexpected error: %v", err)
+	}
+
+	var pruned []string
+
+	opt := gitignore.WithPrune(func(path string, d fs.DirEntry) bool {
+		if !d.IsDir() {
+			t.Errorf("prune function called with file %q", path)
+		}
+
+		pruned = append(pruned, path)
+
+		return path == "vendor" || d.Name() == "testdata"
+	})
+
+	want := []string{"main.go", "pkg", "pkg/a.go"}
+	if got := walkPaths(t, root, file, opt); !slices.Equal(got, want) {
+		t.Errorf("Walk() = %v, want %v", got, want)
+	}
+
+	wantPruned := []string{"pkg", "pkg/testdata", "vendor"}
+	if !slices.Equal(pruned, wantPruned) {
+		t.Errorf("prune function called with %v, want %v", pruned, wantPruned)
+	}
+
+	included, err := file.ListIncluded(root, opt)
+	if err != nil {
+		t.Fatalf("ListIncluded() unexpected error: %v", err)
+	}
+
+	if want := []string{"main.go", "pkg/a.go"}; !slices.Equal(included, want) {
+		t.Errorf("ListIncluded() = %v, want %v", included, want)
+	}
+}
+
+// walkPaths returns the slash-separated paths, relative to root, of the
+// entries reported by Walk, root excluded.
+func walkPaths(t *testing.T, root string, m gitignore.Matcher, opts ...gitignore.WalkOption) []string {
+	t.Helper()
+
+	paths := make([]string, 0)
+
+	err := gitignore.Walk(root, m, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		paths = append(paths, filepath.ToSlash(rel))
+
+		return nil
+	}, opts...)
+	if err != nil {
+		t.Fatalf("Walk() unexpected error: %v", err)
+	}
+
+	return paths
+}
+
+func TestWithSortedOutput(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"a/b.go":   "",
+		"a/c/d.go": "",
+		"a-b.go":   "",
+		"a.txt":    "",
+		"ab.go":    "",
+		"b/x.log":  "",
+	})
+
+	file, err := gitignore.NewFromLines([]string{"*.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		giveOpts      []gitignore.WalkOption
+		wantWalk      []string
+		wantIncluded  []string
+		wantUntracked []string
+	}{
+		{
+			name:          "Default",
+			wantWalk:      []string{"a/", "a/b.go", "a/c/", "a/c/d.go", "a-b.go", "a.txt", "ab.go", "b/"},
+			wantIncluded:  []string{"a/b.go", "a/c/d.go", "a-b.go", "a.txt", "ab.go"},
+			wantUntracked: []string{"a/", "a-b.go", "a.txt", "ab.go"},
+		},
+		{
+			name:          "Sorted",
+			giveOpts:      []gitignore.WalkOption{gitignore.WithSortedOutput()},
+			wantWalk:      []string{"a-b.go", "a.txt", "a/", "a/b.go", "a/c/", "a/c/d.go", "ab.go", "b/"},
+			wantIncluded:  []string{"a-b.go", "a.txt", "a/b.go", "a/c/d.go", "ab.go"},
+			wantUntracked: []string{"a-b.go", "a.txt", "a/", "ab.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := make([]string, 0)
+
+			err := gitignore.Walk(root, file, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || path == root {
+					return err
+				}
+
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return err
+				}
+
+				rel = filepath.ToSlash(rel)
+				if d.IsDir() {
+					rel += "/"
+				}
+
+				got = append(got, rel)
+
+				return nil
+			}, tt.giveOpts...)
+			if err != nil {
+				t.Fatalf("Walk() unexpected error: %v", err)
+			}
+
+			if !slices.Equal(got, tt.wantWalk) {
+				t.Errorf("Walk() = %v, want %v", got, tt.wantWalk)
+			}
+
+			included, err := file.ListIncluded(root, tt.giveOpts...)
+			if err != nil {
+				t.Fatalf("ListIncluded() unexpected error: %v", err)
+			}
+
+			if !slices.Equal(included, tt.wantIncluded) {
+				t.Errorf("ListIncluded() = %v, want %v", included, tt.wantIncluded)
+			}
+
+			untracked, err := gitignore.Untracked(os.DirFS(root), nil, file, tt.giveOpts...)
+			if err != nil {
+				t.Fatalf("Untracked() unexpected error: %v", err)
+			}
+
+			if !slices.Equal(untracked, tt.wantUntracked) {
+				t.Errorf("Untracked() = %v, want %v", untracked, tt.wantUntracked)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package gitignore
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// SymlinkPolicy sets how walkers handle symbolic links.
+type SymlinkPolicy int
+
+const (
+	// SymlinkReport reports symbolic links as entries of their own, without
+	// following them, like [filepath.WalkDir]. This is the default.
+	SymlinkReport SymlinkPolicy = iota
+
+	// SymlinkSkip skips symbolic links altogether.
+	SymlinkSkip
+
+	// SymlinkFollow follows symbolic links, reporting them as the file or
+	// directory they point to, and walking the directories they point to as
+	// if they were in place of the link. Links to directories outside of the
+	// root of the walk, or to one of the directories being walked, such as
+	// one holding the link or one holding a link to the directory of the
+	// link, which would loop forever, are reported as is, without being
+	// followed, as are dangling links.
+	SymlinkFollow
+)
+
+// WalkOption configures how functions walking a file system, such as
+// Untracked and ListIgnored, walk it and handle the errors they encounter.
+type WalkOption func(o *walkOptions)
+
+// walkOptions holds the configuration set by the options given to a walker.
+type walkOptions struct {
+	ctx        context.Context //nolint:containedctx // Options live for a single walk.
+	stats      *WalkStats
+	symlinks   SymlinkPolicy
+	maxDepth   int
+	sorted     bool
+	prune      func(path string, d fs.DirEntry) bool
+	onError    func(path string, err error) error
+	errs       []error
+	collect    bool
+	expandDirs bool
+	absolute   bool
+}
+
+// WithFailFast makes walkers stop at the first directory or .gitignore file
+// that cannot be read, returning its error. This is the default.
+func WithFailFast() WalkOption {
+	return func(o *walkOptions) {
+		o.onError = nil
+		o.collect = false
+	}
+}
+
+// WithSkipErrors makes walkers skip the directories that cannot be read, such
+// as ones without permission, and carry on, since large real-world trees always
+// contain a few. Directories whose .gitignore file cannot be read or parsed are
+// skipped as well, as their rules are unknown. The results gathered from the
+// rest of the tree are returned along with every error joined with
+// errors.Join.
+func WithSkipErrors() WalkOption {
+	return func(o *walkOptions) {
+		o.onError = nil
+		o.collect = true
+	}
+}
+
+// WithErrorHandler makes walkers call fn with the path and error of every
+// directory or .gitignore file that cannot be read. If fn returns nil, the
+// directory is skipped like with WithSkipErrors, without collecting the error;
+// otherwise walking stops and the error returned by fn is returned.
+func WithErrorHandler(fn func(path string, err error) error) WalkOption {
+	return func(o *walkOptions) {
+		o.onError = fn
+		o.collect = false
+	}
+}
+
+// WithExpandedDirs makes ListIgnored report every file inside the ignored
+// directories, rather than each of them as a single entry with a trailing
+// slash, for tools that act on files one by one. Empty ignored directories are
+// not reported.
+func WithExpandedDirs() WalkOption {
+	return func(o *walkOptions) {
+		o.expandDirs = true
+	}
+}
+
+// WithAbsolutePaths makes listers such as ListIgnored and ListIncluded return
+// absolute paths, using the separator of the operating system, rather than
+// slash-separated paths relative to the directory they list, so the results
+// can be passed to functions of the os package as is. Directories keep a
+// trailing separator.
+func WithAbsolutePaths() WalkOption {
+	return func(o *walkOptions) {
+		o.absolute = true
+	}
+}
+
+// WithContext makes walkers and listers stop as soon as ctx is done, returning
+// ctx.Err(), so long scans of huge trees can be aborted, such as when the client
+// of a server goes away. The error is returned whatever the error policy.
+func WithContext(ctx context.Context) WalkOption {
+	return func(o *walkOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithStats makes Walk, WalkSeq, ListIncluded and ListIgnored fill stats with
+// a summary of the walk once done: the number of files visited and ignored,
+// the number of ignored directories pruned, the size of the ignored files, and
+// the duration of the walk. The statistics are reset when a walk starts, and
+// must not be read before it is done.
+func WithStats(stats *WalkStats) WalkOption {
+	return func(o *walkOptions) {
+		o.stats = stats
+	}
+}
+
+// WithSymlinkPolicy sets how Walk and WalkSeq handle symbolic links. Paths
+// below a followed link are reported below the path of the link, and matched
+// against the rules as such.
+func WithSymlinkPolicy(policy SymlinkPolicy) WalkOption {
+	return func(o *walkOptions) {
+		o.symlinks = policy
+	}
+}
+
+// WithMaxDepth makes Walk, WalkSeq, ListIncluded and ListIgnored stop n levels
+// below the directory they walk, whose entries are at depth 1: the directories
+// at depth n are reported, but not read. A depth of 0 or less means no limit,
+// which is the default.
+func WithMaxDepth(n int) WalkOption {
+	return func(o *walkOptions) {
+		o.maxDepth = n
+	}
+}
+
+// WithPrune makes Walk, WalkSeq, ListIncluded and ListIgnored skip the
+// directories fn returns true for, along with everything inside them, as if
+// they were ignored, so callers can cut off subtrees for reasons of their own,
+// such as vendored code, in the same pass. Ignored directories are skipped
+// without calling fn. The path is slash-separated, without a trailing slash, in
+// the form it is matched against the rules.
+func WithPrune(fn func(path string, d fs.DirEntry) bool) WalkOption {
+	return func(o *walkOptions) {
+		o.prune = fn
+	}
+}
+
+// WithSortedOutput makes walkers report entries in lexicographic order of
+// their slash-separated paths, comparing bytes, with directories ordered as if
+// their path ended with a slash, like git orders trees, so enumerations that
+// are hashed or archived are reproducible across platforms and file systems.
+//
+// Without it, walkers report entries depth first, in lexical order of their
+// names within each directory, which is stable, but differs when a file name
+// sorts between a directory name and the name with a slash, such as "a.txt"
+// between "a" and "a/b". Walkers never read directories in parallel, so their
+// order is deterministic either way.
+func WithSortedOutput() WalkOption {
+	return func(o *walkOptions) {
+		o.sorted = true
+	}
+}
+
+// newWalkOptions returns the configuration set by opts.
+func newWalkOptions(opts []WalkOption) *walkOptions {
+	o := &walkOptions{
+		ctx: context.Background(),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// pruned reports whether the entry d at path, a slash-separated path relative
+// to the root of the walk, is a directory pruned by the function set with
+// WithPrune.
+func (o *walkOptions) pruned(path string, d fs.DirEntry) bool {
+	return o.prune != nil && d.IsDir() && o.prune(path, d)
+}
+
+// deepest reports whether entries at depth are at the maximum depth set with
+// WithMaxDepth, so directories at that depth must not be read.
+func (o *walkOptions) deepest(depth int) bool {
+	return o.maxDepth > 0 && depth >= o.maxDepth
+}
+
+// sort sorts entries in the order set with WithSortedOutput, if set.
+func (o *walkOptions) sort(entries []fs.DirEntry) {
+	if !o.sorted {
+		return
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(sortKey(a), sortKey(b))
+	})
+}
+
+// sortKey returns the name of d, with a trailing slash if it is a directory.
+func sortKey(d fs.DirEntry) string {
+	if d.IsDir() {
+		return d.Name() + "/"
+	}
+
+	return d.Name()
+}
+
+// canceled returns the error of the context of the walk once it is done, or
+// nil.
+func (o *walkOptions) canceled() error {
+	return o.ctx.Err() //nolint:wrapcheck // Returned as is, like the context package does.
+}
+
+// handle applies the error policy to err, encountered at path. It returns nil
+// if the entry should be skipped, or the error to stop walking with.
+func (o *walkOptions) handle(path string, err error) error {
+	switch {
+	case o.onError != nil:
+		return o.onError(path, err)
+	case o.collect:
+		o.errs = append(o.errs, err)
+
+		return nil
+	default:
+		return err
+	}
+}
+
+// err returns the errors collected while walking, joined, or nil if there are
+// none.
+func (o *walkOptions) err() error {
+	return errors.Join(o.errs...)
+}
@@ -0,0 +1,75 @@
+package gitignore
+
+import (
+	"fmt"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go/internal/pattern"
+)
+
+// WarningKind identifies why a line was skipped or adjusted while parsing.
+type WarningKind int
+
+const (
+	// WarningInvalidPattern reports a line skipped in permissive mode because
+	// its pattern could not be compiled or was rejected with WithStrict. The
+	// line is also reported in the error returned by the constructor.
+	WarningInvalidPattern = WarningKind(pattern.WarningInvalidPattern)
+
+	// WarningLineTooLong reports a line skipped in permissive mode because it
+	// is longer than 64 KiB, or than the limit set with WithLimits.
+	WarningLineTooLong = WarningKind(pattern.WarningLineTooLong)
+
+	// WarningTrailingSpaces reports a line whose trailing spaces were
+	// stripped, which is often unintended.
+	WarningTrailingSpaces = WarningKind(pattern.WarningTrailingSpaces)
+)
+
+// String returns a short description of the warning kind.
+func (k WarningKind) String() string {
+	switch k {
+	case WarningInvalidPattern:
+		return "invalid pattern skipped"
+	case WarningLineTooLong:
+		return "line too long skipped"
+	case WarningTrailingSpaces:
+		return "trailing spaces stripped"
+	default:
+		return fmt.Sprintf("WarningKind(%d)", int(k))
+	}
+}
+
+// Warning describes a line that was skipped or adjusted while parsing, which
+// does not prevent the rules from being used but may point at a mistake.
+type Warning struct {
+	// Source is the path of the .gitignore file, or empty if the rules were
+	// not read from a file.
+	Source string
+
+	// Text is the line, as read. It is empty for lines too long to be read.
+	Text string
+
+	// Line is the 1-based line number of the line.
+	Line int
+
+	// Kind identifies why the line was skipped or adjusted.
+	Kind WarningKind
+}
+
+// String returns the warning in the usual SOURCE:LINE prefix of compiler
+// diagnostics.
+func (w Warning) String() string {
+	if w.Source == "" {
+		return fmt.Sprintf("%d: %s: %q", w.Line, w.Kind, w.Text)
+	}
+
+	return fmt.Sprintf("%s:%d: %s: %q", w.Source, w.Line, w.Kind, w.Text)
+}
+
+// WithWarnings sets a function called for every line that is skipped or
+// adjusted while parsing, so problems can be surfaced without failing. It is
+// most useful along with WithPermissive.
+func WithWarnings(fn func(w Warning)) Option {
+	return func(o *options) {
+		o.warn = fn
+	}
+}
@@ -0,0 +1,167 @@
+package gitignore_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+func TestWithWarnings(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", 70*1024)
+
+	tests := []struct {
+		name           string
+		giveLines      []string
+		givePermissive bool
+		wantWarnings   []gitignore.Warning
+		wantIgnored    []string
+	}{
+		{
+			name:         "No warnings",
+			giveLines:    []string{"*.log", "  # comment", "build/"},
+			wantWarnings: nil,
+			wantIgnored:  []string{"debug.log"},
+		},
+		{
+			name:      "Trailing spaces",
+			giveLines: []string{"*.log  ", "  *.tmp", "   "},
+			wantWarnings: []gitignore.Warning{
+				{Text: "*.log  ", Line: 1, Kind: gitignore.WarningTrailingSpaces},
+			},
+			wantIgnored: []string{"debug.log", "cache.tmp"},
+		},
+		{
+			name:           "Invalid pattern in permissive mode",
+			giveLines:      []string{"[invalid", "*.log"},
+			givePermissive: true,
+			wantWarnings: []gitignore.Warning{
+				{Text: "[invalid", Line: 1, Kind: gitignore.WarningInvalidPattern},
+			},
+			wantIgnored: []string{"debug.log"},
+		},
+		{
+			name:           "Line too long in permissive mode",
+			giveLines:      []string{"*.tmp", long, "*.log"},
+			givePermissive: true,
+			wantWarnings: []gitignore.Warning{
+				{Line: 2, Kind: gitignore.WarningLineTooLong},
+			},
+			wantIgnored: []string{"debug.log", "cache.tmp"},
+		},
+		{
+			name:           "Last line too long in permissive mode",
+			giveLines:      []string{"*.log", long},
+			givePermissive: true,
+			wantWarnings: []gitignore.Warning{
+				{Line: 2, Kind: gitignore.WarningLineTooLong},
+			},
+			wantIgnored: []string{"debug.log"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var warnings []gitignore.Warning
+
+			opts := []gitignore.Option{
+				gitignore.WithWarnings(func(w gitignore.Warning) {
+					warnings = append(warnings, w)
+				}),
+			}
+
+			if tt.givePermissive {
+				opts = append(opts, gitignore.WithPermissive())
+			}
+
+			file, err := gitignore.NewFromLines(tt.giveLines, opts...)
+			if file == nil {
+				t.Fatalf("NewFromLines() = nil file, error %v", err)
+			}
+
+			if !slices.Equal(warnings, tt.wantWarnings) {
+				t.Errorf("warnings = %v, want %v", warnings, tt.wantWarnings)
+			}
+
+			for _, path := range tt.wantIgnored {
+				if !file.Match(path) {
+					t.Errorf("Match(%q) = false, want true", path)
+				}
+			}
+		})
+	}
+}
+
+func TestWithWarnings_LineTooLong(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"*.log", strings.Repeat("a", 70*1024)}
+
+	_, err := gitignore.NewFromLines(lines, gitignore.WithWarnings(func(w gitignore.Warning) {
+		t.Errorf("unexpected warning %v", w)
+	}))
+	if err == nil {
+		t.Fatal("NewFromLines() = nil error, want error for a line too long outside permissive mode")
+	}
+
+	var parseErr *gitignore.ParseError
+	if errors.As(err, &parseErr) {
+		t.Errorf("NewFromLines() error = %v, want a read error", err)
+	}
+}
+
+func TestWithWarnings_Source(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if err := os.WriteFile(path, []byte("*.log \n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	var warnings []string
+
+	_, err := gitignore.New(path, gitignore.WithWarnings(func(w gitignore.Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	if err != nil {
+		t.Fatalf("New(%q) error = %v", path, err)
+	}
+
+	want := []string{path + `:1: trailing spaces stripped: "*.log "`}
+	if !slices.Equal(warnings, want) {
+		t.Errorf("warnings = %q, want %q", warnings, want)
+	}
+}
+
+func TestWarningKind_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		give gitignore.WarningKind
+		want string
+	}{
+		{give: gitignore.WarningInvalidPattern, want: "invalid pattern skipped"},
+		{give: gitignore.WarningLineTooLong, want: "line too long skipped"},
+		{give: gitignore.WarningTrailingSpaces, want: "trailing spaces stripped"},
+		{give: gitignore.WarningKind(42), want: "WarningKind(42)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.give.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
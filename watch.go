@@ -0,0 +1,229 @@
+package gitignore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Event describes a change to a file or directory of a tree watched with
+// WatchTree, or an error watching it.
+type Event struct {
+	// Err is the error watching the tree, if any.
+	Err error
+
+	// Path is the path of the file or directory that changed, joined with the
+	// root given to WatchTree. It is empty for errors not related to a single
+	// path.
+	Path string
+
+	// Op is the change, or changes, made to the file or directory.
+	Op fsnotify.Op
+}
+
+// TreeWatcher watches a tree with fsnotify, reporting changes to the files and
+// directories that are not ignored. Ignored directories are never watched, so
+// trees such as node_modules cost neither watches nor events.
+//
+// A TreeWatcher is safe for concurrent use.
+type TreeWatcher struct {
+	matcher     Matcher
+	watcher     *fsnotify.Watcher
+	watching    chan struct{}
+	subscribers subscriptions[Event]
+	root        string
+
+	// dirs holds the watched directories. It is only used by the watching
+	// goroutine once started.
+	dirs map[string]struct{}
+}
+
+// WatchTree watches the tree rooted at root recursively until Close is called,
+// reporting the creation, modification, removal and renaming of the files and
+// directories m does not ignore to the functions set with Subscribe. Paths are
+// given to m like with Walk, and a nil m ignores nothing.
+//
+// Directories are watched as they are created, and the entries already in them
+// by then are reported as created, so none are missed, even if some may be
+// reported twice. The rules of m are applied as events arrive, but
+// directories that were ignored when found are not watched afterwards.
+//
+// An error is returned if a directory of the tree cannot be watched.
+func WatchTree(root string, m Matcher) (*TreeWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	w := &TreeWatcher{
+		matcher:  m,
+		watcher:  watcher,
+		watching: make(chan struct{}),
+		root:     filepath.Clean(root),
+		dirs:     make(map[string]struct{}),
+	}
+
+	if err := w.add(w.root, false); err != nil {
+		watcher.Close()
+
+		return nil, err
+	}
+
+	go watch(watcher, w.watching, &w.subscribers, w.handle, eventError)
+
+	return w, nil
+}
+
+// Subscribe sets a function called with every event of w, and returns a
+// function removing it. It is called from the watching goroutine, so it must
+// not block.
+func (w *TreeWatcher) Subscribe(fn func(Event)) func() {
+	return w.subscribers.add(fn)
+}
+
+// WatchedDirs returns the slash-separated paths of the directories watched by
+// w, relative to its root, in lexical order.
+func (w *TreeWatcher) WatchedDirs() []string {
+	dirs := make([]string, 0)
+
+	for _, dir := range w.watcher.WatchList() {
+		rel, err := filepath.Rel(w.root, dir)
+		if err != nil {
+			continue
+		}
+
+		dirs = append(dirs, filepath.ToSlash(rel))
+	}
+
+	slices.Sort(dirs)
+
+	return dirs
+}
+
+// Close stops watching the tree of w and waits for pending events to be
+// reported.
+func (w *TreeWatcher) Close() error {
+	err := w.watcher.Close()
+
+	<-w.watching
+
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// handle reports event if it changed a path that is not ignored, and watches
+// the directories it created.
+func (w *TreeWatcher) handle(event fsnotify.Event) {
+	name := filepath.Clean(event.Name)
+
+	_, watched := w.dirs[name]
+	isDir := watched
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Lstat(name); err == nil {
+			isDir = info.IsDir()
+		}
+	}
+
+	if w.ignored(name, isDir) {
+		return
+	}
+
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		w.forget(name)
+	}
+
+	w.subscribers.notify(Event{Path: name, Op: event.Op})
+
+	if event.Has(fsnotify.Create) && isDir && !watched {
+		if err := w.add(name, true); err != nil {
+			w.subscribers.notify(Event{Path: name, Err: err})
+		}
+	}
+}
+
+// add watches the directory dir and the directories below it that are not
+// ignored. If report is true, the entries found below dir are reported as
+// created.
+func (w *TreeWatcher) add(dir string, report bool) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error { //nolint:wrapcheck // Errors are wrapped by the callback.
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if path != dir {
+			if w.ignored(path, d.IsDir()) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+
+				return nil
+			}
+
+			if report {
+				w.subscribers.notify(Event{Path: path, Op: fsnotify.Create})
+			}
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if err := w.watcher.Add(path); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// Removed while walking.
+				return fs.SkipDir
+			}
+
+			return fmt.Errorf("%w", err)
+		}
+
+		w.dirs[path] = struct{}{}
+
+		return nil
+	})
+}
+
+// forget stops watching the directory name, if watched, and the directories
+// below it, after it was removed or renamed.
+func (w *TreeWatcher) forget(name string) {
+	prefix := name + string(filepath.Separator)
+
+	for dir := range w.dirs {
+		if dir == name || strings.HasPrefix(dir, prefix) {
+			// The watch is already gone if the directory was removed.
+			_ = w.watcher.Remove(dir)
+
+			delete(w.dirs, dir)
+		}
+	}
+}
+
+// ignored reports whether the file or directory at name, within the root of w,
+// is ignored.
+func (w *TreeWatcher) ignored(name string, isDir bool) bool {
+	if w.matcher == nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(w.root, name)
+	if err != nil || rel == "." {
+		return false
+	}
+
+	return IsIgnored(w.matcher, filepath.ToSlash(rel), isDir)
+}
+
+// eventError returns the event notifying of err, an error of a watcher.
+func eventError(err error) Event {
+	return Event{Err: err}
+}
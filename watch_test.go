@@ -0,0 +1,125 @@
+package gitignore_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"git.sr.ht/~jamesponddotco/gitignore-go"
+)
+
+// waitEvent waits for an event changing path on events, failing on events
+// changing any path in unwanted.
+func waitEvent(t *testing.T, events <-chan gitignore.Event, path string, unwanted ...string) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case event := <-events:
+			if event.Err != nil {
+				t.Fatalf("event of %q error = %v", event.Path, event.Err)
+			}
+
+			if slices.Contains(unwanted, event.Path) {
+				t.Fatalf("unexpected event %v of %q", event.Op, event.Path)
+			}
+
+			if event.Path == path {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for an event of %q", path)
+		}
+	}
+}
+
+func TestWatchTree(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeTree(t, root, map[string]string{
+		"src/main.go":                   "package main\n",
+		"node_modules/pkg/lib/index.js": "",
+		"build/out.bin":                 "",
+	})
+
+	matcher, err := gitignore.NewFromLines([]string{"node_modules/", "build/", "*.log"})
+	if err != nil {
+		t.Fatalf("NewFromLines() unexpected error: %v", err)
+	}
+
+	w, err := gitignore.WatchTree(root, matcher)
+	if err != nil {
+		t.Fatalf("WatchTree() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := w.Close(); err != nil {
+			t.Errorf("Close() unexpected error: %v", err)
+		}
+	})
+
+	if got, want := w.WatchedDirs(), []string{".", "src"}; !slices.Equal(got, want) {
+		t.Fatalf("WatchedDirs() = %v, want %v", got, want)
+	}
+
+	events := make(chan gitignore.Event, 64)
+
+	t.Cleanup(w.Subscribe(func(event gitignore.Event) {
+		events <- event
+	}))
+
+	var (
+		ignoredLog  = filepath.Join(root, "src", "debug.log")
+		ignoredDep  = filepath.Join(root, "node_modules", "pkg", "new.js")
+		includedSrc = filepath.Join(root, "src", "main.go")
+	)
+
+	for _, name := range []string{ignoredLog, ignoredDep, includedSrc} {
+		if err := os.WriteFile(name, []byte("changed\n"), 0o600); err != nil {
+			t.Fatalf("failed to write %q: %v", name, err)
+		}
+	}
+
+	waitEvent(t, events, includedSrc, ignoredLog, ignoredDep)
+
+	var (
+		newDir  = filepath.Join(root, "src", "pkg")
+		newFile = filepath.Join(newDir, "pkg.go")
+	)
+
+	if err := os.Mkdir(newDir, 0o700); err != nil {
+		t.Fatalf("failed to create %q: %v", newDir, err)
+	}
+
+	waitEvent(t, events, newDir)
+
+	if err := os.WriteFile(newFile, []byte("package pkg\n"), 0o600); err != nil {
+		t.Fatalf("failed to write %q: %v", newFile, err)
+	}
+
+	waitEvent(t, events, newFile)
+
+	if got, want := w.WatchedDirs(), []string{".", "src", "src/pkg"}; !slices.Equal(got, want) {
+		t.Errorf("WatchedDirs() = %v, want %v", got, want)
+	}
+
+	if err := os.RemoveAll(newDir); err != nil {
+		t.Fatalf("failed to remove %q: %v", newDir, err)
+	}
+
+	waitEvent(t, events, newDir)
+}
+
+func TestWatchTree_NotExist(t *testing.T) {
+	t.Parallel()
+
+	if _, err := gitignore.WatchTree(filepath.Join(t.TempDir(), "missing"), nil); err == nil {
+		t.Fatal("WatchTree() expected an error for a missing root")
+	}
+}